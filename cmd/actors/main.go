@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/vitalii-komenda/got/postgres"
+)
+
+const usage = `Usage:
+  actors orphans [-delete]         list actors no character links to, -delete removes them
+  actors duplicates [-merge]       list actors whose names differ only in spelling, -merge merges each group into its first actor
+  actors merge <keep> <duplicate>  move the roles of the duplicate actor to the kept one and delete it
+`
+
+// DB_HOST=localhost DB_USER=postgres DB_PASS=postgres DB_NAME=got DB_PORT=5433 go run cmd/actors/main.go orphans
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Print(usage)
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+	db, err := postgres.NewDBPool()
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	tx, err := db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Rollback(ctx)
+
+	actorsRepo := postgres.NewActorsRepository(db).WithTX(&tx)
+
+	command, args := os.Args[1], os.Args[2:]
+	switch command {
+	case "orphans":
+		flags := flag.NewFlagSet(command, flag.ExitOnError)
+		remove := flags.Bool("delete", false, "delete the orphaned actors")
+		flags.Parse(args)
+
+		if *remove {
+			deleted, err := actorsRepo.DeleteOrphans(ctx)
+			if err != nil {
+				log.Fatalf("Unable to delete orphaned actors: %v\n", err)
+			}
+			for _, name := range deleted {
+				fmt.Print("Deleted actor: ", name, "\n")
+			}
+			fmt.Printf("\n%d orphaned actors deleted\n", len(deleted))
+		} else {
+			orphans, err := actorsRepo.GetOrphans(ctx)
+			if err != nil {
+				log.Fatalf("Unable to list orphaned actors: %v\n", err)
+			}
+			for _, actor := range orphans {
+				fmt.Printf("%d\t%s\n", actor.ActorID, actor.ActorName)
+			}
+			fmt.Printf("\n%d orphaned actors\n", len(orphans))
+		}
+
+	case "duplicates":
+		flags := flag.NewFlagSet(command, flag.ExitOnError)
+		merge := flags.Bool("merge", false, "merge each group into its first actor")
+		flags.Parse(args)
+
+		groups, err := actorsRepo.FindDuplicates(ctx)
+		if err != nil {
+			log.Fatalf("Unable to find duplicate actors: %v\n", err)
+		}
+		for _, group := range groups {
+			keep := group[0]
+			fmt.Printf("%d\t%s (%d characters)\n", keep.ActorID, keep.ActorName, keep.Characters)
+			for _, duplicate := range group[1:] {
+				fmt.Printf("  %d\t%s (%d characters)\n", duplicate.ActorID, duplicate.ActorName, duplicate.Characters)
+				if *merge {
+					err = actorsRepo.Merge(ctx, keep.ActorID, duplicate.ActorID)
+					if err != nil {
+						log.Fatalf("Unable to merge %q into %q: %v\n", duplicate.ActorName, keep.ActorName, err)
+					}
+				}
+			}
+		}
+		fmt.Printf("\n%d groups of duplicate actors\n", len(groups))
+
+	case "merge":
+		if len(args) != 2 {
+			fmt.Print(usage)
+			os.Exit(2)
+		}
+		keep, err := actorsRepo.Get(ctx, args[0])
+		if err != nil {
+			log.Fatalf("Unable to find actor %q: %v\n", args[0], err)
+		}
+		// exact match, the normalized one could find the kept actor again
+		duplicateID, err := actorsRepo.GetActorID(ctx, args[1])
+		if err != nil {
+			log.Fatalf("Unable to find actor %q: %v\n", args[1], err)
+		}
+		err = actorsRepo.Merge(ctx, keep.ActorID, duplicateID)
+		if err != nil {
+			log.Fatalf("Unable to merge actors: %v\n", err)
+		}
+		fmt.Printf("Merged %q into %q\n", args[1], keep.ActorName)
+
+	default:
+		fmt.Print(usage)
+		os.Exit(2)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		log.Fatalf("Unable to commit transaction: %v\n", err)
+	}
+}
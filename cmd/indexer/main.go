@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/vitalii-komenda/got/postgres"
+	"github.com/vitalii-komenda/got/services"
+	"github.com/vitalii-komenda/got/utils"
+)
+
+// DB_HOST=localhost DB_USER=postgres DB_PASS=postgres DB_NAME=got DB_PORT=5433 ELASTICSEARCH_HOST=http://localhost:9200 go run cmd/indexer/main.go
+func main() {
+	db, err := postgres.NewDBPool()
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	actorsRepo := postgres.NewActorsRepository(db)
+	characterRepo := postgres.NewCharacterRepository(db, actorsRepo)
+	outboxRepo := postgres.NewOutboxRepository(db)
+	indexManager := services.NewIndexManager(utils.MustGetEnvOrPanic("ELASTICSEARCH_HOST"))
+	indexer := services.NewIndexer(outboxRepo, characterRepo, indexManager)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err = indexManager.EnsureIndex(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print("Indexing characters...\n")
+	err = indexer.Run(ctx)
+	if err != nil && ctx.Err() == nil {
+		panic(err)
+	}
+}
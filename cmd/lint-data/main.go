@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/vitalii-komenda/got/entities"
+	"github.com/vitalii-komenda/got/services"
+)
+
+// Checks data/got-characters.json, or the -input file in any of the
+// -format formats cmd/import reads, for relationships to unknown
+// characters, parentOf without the matching parents, duplicate names and
+// invalid image URLs. Exits with 1 when there are errors.
+//
+// go run cmd/lint-data/main.go
+// go run cmd/lint-data/main.go -report json -format csv -input - < characters.csv
+func main() {
+	input := flag.String("input", "data/got-characters.json", `file to check, "-" for stdin`)
+	format := flag.String("format", "", "one of "+strings.Join(services.CharacterFormats(), ", ")+", by default from the file extension, json for stdin")
+	reportFormat := flag.String("report", "text", "text or json")
+	flag.Parse()
+	if *reportFormat != "text" && *reportFormat != "json" {
+		log.Fatalf("-report must be text or json, not %q\n", *reportFormat)
+	}
+
+	characters, err := readCharacters(*input, *format)
+	if err != nil {
+		log.Fatalf("Unable to read %s: %v\n", *input, err)
+	}
+
+	report := services.LintCharacters(characters)
+	if *reportFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			log.Fatalf("Unable to write the report: %v\n", err)
+		}
+	} else {
+		printReport(report, len(characters))
+	}
+
+	if report.Errors > 0 {
+		os.Exit(1)
+	}
+}
+
+// printReport prints the issues under a heading per category, then the
+// counts.
+func printReport(report services.LintReport, characters int) {
+	byCategory := map[services.LintCategory][]services.LintIssue{}
+	for _, issue := range report.Issues {
+		byCategory[issue.Category] = append(byCategory[issue.Category], issue)
+	}
+	for _, category := range services.LintCategories {
+		issues := byCategory[category]
+		if len(issues) == 0 {
+			continue
+		}
+		fmt.Printf("%s (%s, %d)\n", category, issues[0].Severity, len(issues))
+		for _, issue := range issues {
+			fmt.Printf("  %s: %s\n", issue.Character, issue.Message)
+		}
+		fmt.Print("\n")
+	}
+	fmt.Printf("%d characters, %d errors, %d warnings\n", characters, report.Errors, report.Warnings)
+}
+
+// readCharacters reads the input with the reader of the format.
+func readCharacters(input string, format string) ([]entities.CharacterEntry, error) {
+	path := input
+	if input == "-" {
+		path = ""
+	}
+	reader, err := services.NewCharacterReader(format, path)
+	if err != nil {
+		return nil, err
+	}
+	if input == "-" {
+		return reader.Read(os.Stdin)
+	}
+
+	file, err := os.Open(input)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return reader.Read(file)
+}
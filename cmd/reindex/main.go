@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/vitalii-komenda/got/postgres"
+	"github.com/vitalii-komenda/got/services"
+	"github.com/vitalii-komenda/got/utils"
+)
+
+// Rebuilds the character_details index from Postgres into a new index with
+// the current mappings, then points the alias at it. Searches keep using
+// the old index until the swap, and the indexer writes to both meanwhile.
+//
+// DB_HOST=localhost DB_USER=postgres DB_PASS=postgres DB_NAME=got DB_PORT=5433 ELASTICSEARCH_HOST=http://localhost:9200 go run cmd/reindex/main.go
+func main() {
+	keepOld := flag.Bool("keep-old", false, "keep the previous index instead of deleting it after the swap")
+	flag.Parse()
+
+	ctx := context.Background()
+	db, err := postgres.NewDBPool()
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	actorsRepo := postgres.NewActorsRepository(db)
+	characterRepo := postgres.NewCharacterRepository(db, actorsRepo)
+	outboxRepo := postgres.NewOutboxRepository(db)
+	indexManager := services.NewIndexManager(utils.MustGetEnvOrPanic("ELASTICSEARCH_HOST"))
+	indexer := services.NewIndexer(outboxRepo, characterRepo, indexManager)
+
+	index, err := indexManager.CreateIndex(ctx)
+	if err != nil {
+		log.Fatalf("Unable to create index: %v\n", err)
+	}
+	fmt.Print("Created index: ", index, "\n")
+
+	err = indexManager.StartBuilding(ctx, index)
+	if err == nil {
+		var count int
+		count, err = indexer.IndexAll(ctx, index)
+		fmt.Printf("Indexed %d characters\n", count)
+	}
+	if err != nil {
+		if deleteErr := indexManager.DeleteIndex(ctx, index); deleteErr != nil {
+			fmt.Printf("Unable to delete index %s: %v\n", index, deleteErr)
+		}
+		log.Fatalf("Unable to build index: %v\n", err)
+	}
+
+	previous, err := indexManager.SwapAlias(ctx, index)
+	if err != nil {
+		log.Fatalf("Unable to swap alias: %v\n", err)
+	}
+	fmt.Print("Alias ", indexManager.Alias, " now points to ", index, "\n")
+
+	if *keepOld {
+		return
+	}
+	for _, old := range previous {
+		err = indexManager.DeleteIndex(ctx, old)
+		if err != nil {
+			log.Fatalf("Unable to delete index %s: %v\n", old, err)
+		}
+		fmt.Print("Deleted index: ", old, "\n")
+	}
+}
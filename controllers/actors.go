@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vitalii-komenda/got/entities"
+)
+
+type ActorsController struct {
+	actorsRepo entities.ActorsRepository
+}
+
+func NewActorsController(
+	actorsRepo entities.ActorsRepository,
+) *ActorsController {
+	return &ActorsController{
+		actorsRepo: actorsRepo,
+	}
+}
+
+// GetAll godoc
+// @Summary Get all actors
+// @Description Get all actors with pagination, optionally filtered by name, house of their characters or season
+// @Tags actors
+// @Accept  json
+// @Produce  json
+// @Param page query int false "Page number"
+// @Param name query string false "Part of the actor name"
+// @Param house query string false "House of a character the actor played"
+// @Param season query int false "Season the actor appeared in"
+// @Success 200 {array} entities.ActorEntry
+// @Failure 400 {object} Problem
+// @Failure 503 {object} Problem
+// @Router /actors [get]
+func (c *ActorsController) GetAll(g *gin.Context) {
+	page, season, ok := pageAndSeason(g)
+	if !ok {
+		return
+	}
+
+	filter := entities.ActorFilter{
+		Name:   g.Query("name"),
+		House:  g.Query("house"),
+		Season: season,
+	}
+	actors, err := c.actorsRepo.GetAll(g.Request.Context(), filter, page)
+	if err != nil {
+		RespondWithError(g, err)
+	} else {
+		RespondWithJSON(g, http.StatusOK, actors)
+	}
+}
+
+// Get godoc
+// @Summary Get an actor
+// @Description Get an actor by name or slug, matched ignoring case, accents and punctuation
+// @Tags actors
+// @Accept  json
+// @Produce  json
+// @Param name path string true "Actor name or slug"
+// @Success 200 {object} entities.ActorEntry
+// @Failure 404 {object} Problem
+// @Failure 503 {object} Problem
+// @Router /actors/{name} [get]
+func (c *ActorsController) Get(g *gin.Context) {
+	actor, err := c.actorsRepo.Get(g.Request.Context(), g.Params.ByName("name"))
+	if err != nil {
+		RespondWithError(g, err)
+	} else {
+		RespondWithJSON(g, http.StatusOK, actor)
+	}
+}
+
+// GetCharacters godoc
+// @Summary Get the characters an actor played
+// @Description Get the characters an actor played and the seasons they played them in, with pagination
+// @Tags actors
+// @Accept  json
+// @Produce  json
+// @Param name path string true "Actor name or slug"
+// @Param page query int false "Page number"
+// @Param season query int false "Only roles played in this season"
+// @Success 200 {array} entities.ActorRole
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 503 {object} Problem
+// @Router /actors/{name}/characters [get]
+func (c *ActorsController) GetCharacters(g *gin.Context) {
+	page, season, ok := pageAndSeason(g)
+	if !ok {
+		return
+	}
+
+	actor, err := c.actorsRepo.Get(g.Request.Context(), g.Params.ByName("name"))
+	if err != nil {
+		RespondWithError(g, err)
+		return
+	}
+	roles, err := c.actorsRepo.GetRoles(g.Request.Context(), actor.ActorID, season, page)
+	if err != nil {
+		RespondWithError(g, err)
+	} else {
+		RespondWithJSON(g, http.StatusOK, roles)
+	}
+}
+
+// pageAndSeason reads the page, 0 or more, and the season, 1 or more when
+// given, responding with 400 otherwise.
+func pageAndSeason(g *gin.Context) (int, int, bool) {
+	page, ok := queryInt(g, "page")
+	if !ok {
+		return 0, 0, false
+	}
+	if page < 0 {
+		RespondWithBadRequest(g, "page must not be negative")
+		return 0, 0, false
+	}
+	season, ok := queryInt(g, "season")
+	if !ok {
+		return 0, 0, false
+	}
+	if g.Query("season") != "" && season < 1 {
+		RespondWithBadRequest(g, "season must be 1 or more")
+		return 0, 0, false
+	}
+	return page, season, true
+}
+
+// queryInt reads an optional integer query parameter, responding with
+// 400 when it is not an integer.
+func queryInt(g *gin.Context, key string) (int, bool) {
+	value := g.Query(key)
+	if value == "" {
+		return 0, true
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		RespondWithBadRequest(g, fmt.Sprintf("%s must be an integer", key))
+		return 0, false
+	}
+	return number, true
+}
@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/vitalii-komenda/got/entities"
+	"github.com/vitalii-komenda/got/mocks"
+)
+
+func TestActorsGetAll(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockActorsRepo := new(mocks.ActorsRepositoryMock)
+	controller := NewActorsController(mockActorsRepo)
+
+	t.Run("success", func(t *testing.T) {
+		mockActorsRepo.GetAllFunc = func(ctx context.Context, filter entities.ActorFilter, page int) ([]entities.ActorEntry, error) {
+			assert.Equal(t, entities.ActorFilter{Name: "kit", House: "House Stark", Season: 3}, filter)
+			assert.Equal(t, 1, page)
+			return []entities.ActorEntry{{ActorName: "Kit Harington"}}, nil
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/actors?page=1&name=kit&house=House+Stark&season=3", nil)
+
+		controller.GetAll(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Kit Harington")
+	})
+
+	t.Run("invalid season", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/actors?season=three", nil)
+
+		controller.GetAll(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("out of range page and season", func(t *testing.T) {
+		for _, query := range []string{"page=-1", "season=0", "season=-2"} {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET", "/actors?"+query, nil)
+
+			controller.GetAll(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+		assert.Len(t, mockActorsRepo.GetAllCalls(), 1)
+	})
+}
+
+func TestActorsGet(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockActorsRepo := new(mocks.ActorsRepositoryMock)
+	controller := NewActorsController(mockActorsRepo)
+
+	t.Run("success", func(t *testing.T) {
+		mockActorsRepo.GetFunc = func(ctx context.Context, actorName string) (entities.ActorEntry, error) {
+			return entities.ActorEntry{ActorID: 1, ActorName: "Kit Harington"}, nil
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/actors/kit-harington", nil)
+		c.Params = gin.Params{gin.Param{Key: "name", Value: "kit-harington"}}
+
+		controller.Get(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockActorsRepo.GetFunc = func(ctx context.Context, actorName string) (entities.ActorEntry, error) {
+			return entities.ActorEntry{}, entities.NewNotFoundError("actor %q not found", actorName)
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/actors/nobody", nil)
+		c.Params = gin.Params{gin.Param{Key: "name", Value: "nobody"}}
+
+		controller.Get(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestActorsGetCharacters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockActorsRepo := new(mocks.ActorsRepositoryMock)
+	controller := NewActorsController(mockActorsRepo)
+
+	mockActorsRepo.GetFunc = func(ctx context.Context, actorName string) (entities.ActorEntry, error) {
+		return entities.ActorEntry{ActorID: 7, ActorName: "Kit Harington"}, nil
+	}
+	mockActorsRepo.GetRolesFunc = func(ctx context.Context, actorID int, season int, page int) ([]entities.ActorRole, error) {
+		assert.Equal(t, 7, actorID)
+		assert.Equal(t, 2, season)
+		return []entities.ActorRole{{CharacterName: "Jon Snow", SeasonsActive: []int{1, 2}}}, nil
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/actors/kit-harington/characters?season=2", nil)
+	c.Params = gin.Params{gin.Param{Key: "name", Value: "kit-harington"}}
+
+	controller.GetCharacters(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"seasonsActive":[1,2]`)
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/actors/kit-harington/characters?page=-1", nil)
+	c.Params = gin.Params{gin.Param{Key: "name", Value: "kit-harington"}}
+
+	controller.GetCharacters(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Len(t, mockActorsRepo.GetRolesCalls(), 1)
+}
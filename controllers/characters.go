@@ -1,26 +1,34 @@
 package controllers
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/vitalii-komenda/got/entities"
 )
 
 type CharactersController struct {
-	charactersRepo    entities.CharactersRepository
-	relationshipsRepo entities.RelationshipsRepository
+	charactersRepo entities.CharactersRepository
+	transactor     entities.CharacterTransactor
+	listeners      []entities.CharacterListener
 }
 
 func NewCharactersController(
 	charactersRepo entities.CharactersRepository,
-	relationshipsRepo entities.RelationshipsRepository,
+	transactor entities.CharacterTransactor,
+	listeners ...entities.CharacterListener,
 ) *CharactersController {
 	return &CharactersController{
-		charactersRepo:    charactersRepo,
-		relationshipsRepo: relationshipsRepo,
+		charactersRepo: charactersRepo,
+		transactor:     transactor,
+		listeners:      listeners,
 	}
 }
 
@@ -32,7 +40,8 @@ func NewCharactersController(
 // @Produce  json
 // @Param page query int false "Page number"
 // @Success 200 {array} []entities.CharacterEntry
-// @Failure 400 {object} map[string]any
+// @Failure 400 {object} Problem
+// @Failure 503 {object} Problem
 // @Router /characters [get]
 func (c *CharactersController) GetAll(g *gin.Context) {
 	pageStr := g.Query("page")
@@ -42,34 +51,76 @@ func (c *CharactersController) GetAll(g *gin.Context) {
 	page, err := strconv.Atoi(pageStr)
 	if err != nil {
 		fmt.Printf("Error parsing page number: %v", err)
-		RespondWithError(g, http.StatusBadRequest, err.Error())
+		RespondWithBadRequest(g, "page must be an integer")
 		return
 	}
 
 	characters, err := c.charactersRepo.GetAll(g.Request.Context(), page)
 	if err != nil {
-		RespondWithError(g, http.StatusBadRequest, err.Error())
+		RespondWithError(g, err)
 	} else {
 		RespondWithJSON(g, http.StatusOK, characters)
 	}
 }
 
 // Get godoc
-// @Summary Get a character by name
-// @Description Get a character by name
+// @Summary Get a character by slug
+// @Description Get a character by slug. Previous slugs, names and aliases, matched ignoring case, accents and punctuation, redirect to the current slug.
 // @Tags characters
 // @Accept  json
 // @Produce  json
-// @Param name path string true "Character name"
+// @Param name path string true "Character slug"
 // @Success 200 {object} []entities.CharacterEntry
-// @Failure 400 {object} map[string]any
-// @Failure 404 {object} map[string]any
+// @Success 301
+// @Header 301 {string} Location "Current URL of the character"
+// @Failure 404 {object} Problem
+// @Failure 503 {object} Problem
 // @Router /characters/{name} [get]
 func (c *CharactersController) Get(g *gin.Context) {
-	character := g.Params.ByName("name")
-	value, err := c.charactersRepo.Get(g.Request.Context(), character)
+	slug := g.Params.ByName("name")
+	ref, err := c.charactersRepo.Resolve(g.Request.Context(), slug)
 	if err != nil {
-		RespondWithError(g, http.StatusBadRequest, err.Error())
+		RespondWithError(g, err)
+		return
+	}
+	if ref.Slug != slug {
+		location := characterLocation(ref.Slug)
+		if g.Request.URL.RawQuery != "" {
+			location += "?" + g.Request.URL.RawQuery
+		}
+		g.Redirect(http.StatusMovedPermanently, location)
+		return
+	}
+
+	c.respondWithCharacter(g, ref.ID)
+}
+
+// GetByID godoc
+// @Summary Get a character by ID
+// @Description Get a character by its immutable ID
+// @Tags characters
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Character ID"
+// @Success 200 {object} []entities.CharacterEntry
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 503 {object} Problem
+// @Router /characters/id/{id} [get]
+func (c *CharactersController) GetByID(g *gin.Context) {
+	id, err := strconv.Atoi(g.Params.ByName("id"))
+	if err != nil {
+		RespondWithBadRequest(g, "id must be an integer")
+		return
+	}
+
+	c.respondWithCharacter(g, id)
+}
+
+func (c *CharactersController) respondWithCharacter(g *gin.Context, id int) {
+	value, err := c.charactersRepo.Get(g.Request.Context(), id)
+	if err != nil {
+		RespondWithError(g, err)
 	} else if len(value) == 0 {
 		RespondWithNotFound(g)
 	} else {
@@ -84,61 +135,231 @@ func (c *CharactersController) Get(g *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param character body entities.CharacterEntry true "Character Entry"
-// @Success 200 {object} entities.CharacterEntry
-// @Failure 400 {object} map[string]any
+// @Success 201 {object} entities.CharacterEntry
+// @Header 201 {string} Location "URL of the created character"
+// @Failure 400 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 503 {object} Problem
 // @Router /characters [post]
 func (c *CharactersController) Post(g *gin.Context) {
 	var character entities.CharacterEntry
 	if err := g.ShouldBindJSON(&character); err != nil {
-		g.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		RespondWithBadRequest(g, err.Error())
 		return
 	}
-	err := c.charactersRepo.CreateCharacterAndActor(g.Request.Context(), &character)
-	if err == nil {
-		err = c.relationshipsRepo.AddAll(g.Request.Context(), character)
+	if character.CharacterName == "" {
+		RespondWithError(g, entities.NewValidationError("characterName is required"))
+		return
 	}
 
+	err := c.create(g, &character)
 	if err != nil {
-		RespondWithError(g, http.StatusBadRequest, err.Error())
+		RespondWithError(g, err)
 	} else {
-		RespondWithJSON(g, http.StatusOK, character)
+		RespondWithCreated(g, characterLocation(character.Slug), character)
+	}
+}
+
+// create writes the character and its relationships in one transaction,
+// so a failed relationship doesn't leave the character behind for a retry
+// to conflict with.
+func (c *CharactersController) create(g *gin.Context, character *entities.CharacterEntry) error {
+	err := c.transactor.InTx(g.Request.Context(), func(characters entities.CharactersRepository, relationships entities.RelationshipsRepository) error {
+		if err := characters.CreateCharacterAndActor(g.Request.Context(), character); err != nil {
+			return err
+		}
+		return relationships.AddAll(g.Request.Context(), *character)
+	})
+	if err != nil {
+		return err
+	}
+	c.notifySaved(g, character.CharacterID)
+	return nil
+}
+
+func (c *CharactersController) update(g *gin.Context, character *entities.CharacterEntry, id int) error {
+	err := c.transactor.InTx(g.Request.Context(), func(characters entities.CharactersRepository, relationships entities.RelationshipsRepository) error {
+		if _, err := characters.UpdateCharacterAndActor(g.Request.Context(), character, id); err != nil {
+			return err
+		}
+		return relationships.UpdateAll(g.Request.Context(), *character)
+	})
+	if err != nil {
+		return err
 	}
+	c.notifySaved(g, id)
+	return nil
+}
+
+func characterLocation(slug string) string {
+	return "/characters/" + url.PathEscape(slug)
 }
 
 // Delete godoc
-// @Summary Delete a character by name
-// @Description Delete a character by name
+// @Summary Delete a character by slug
+// @Description Delete a character by its current or a previous slug. Names and aliases don't match.
 // @Tags characters
 // @Accept  json
 // @Produce  json
-// @Param name path string true "Character name"
-// @Success 200 {object} map[string]any
-// @Failure 400 {object} map[string]any
+// @Param name path string true "Character slug"
+// @Success 204
+// @Failure 404 {object} Problem
+// @Failure 503 {object} Problem
 // @Router /characters/{name} [delete]
 func (c *CharactersController) Delete(g *gin.Context) {
-	character := g.Params.ByName("name")
-	err := c.charactersRepo.Delete(g.Request.Context(), character)
+	ref, err := c.charactersRepo.ResolveSlug(g.Request.Context(), g.Params.ByName("name"))
+	if err == nil {
+		err = c.charactersRepo.Delete(g.Request.Context(), ref.ID)
+	}
 
 	if err != nil {
-		RespondWithError(g, http.StatusBadRequest, err.Error())
+		RespondWithError(g, err)
 	} else {
-		RespondWithJSON(g, http.StatusOK, gin.H{})
+		c.notifyDeleted(g, ref.ID)
+		RespondWithNoContent(g)
+	}
+}
+
+// notifySaved tells the listeners about a written character. The write is
+// already done, so listener failures are only logged.
+func (c *CharactersController) notifySaved(g *gin.Context, id int) {
+	for _, listener := range c.listeners {
+		if err := listener.CharacterSaved(g.Request.Context(), id); err != nil {
+			fmt.Printf("Error notifying about character %d: %v\n", id, err)
+		}
+	}
+}
+
+func (c *CharactersController) notifyDeleted(g *gin.Context, id int) {
+	for _, listener := range c.listeners {
+		if err := listener.CharacterDeleted(g.Request.Context(), id); err != nil {
+			fmt.Printf("Error notifying about deleted character %d: %v\n", id, err)
+		}
 	}
 }
 
+// Put godoc
+// @Summary Create or replace a character
+// @Description Replace a character and its relationships, creating it when it does not exist yet.
+// @Description A characterName different from the current one renames the character; the old slug keeps redirecting.
+// @Description Only the current or a previous slug matches; names and aliases don't.
+// @Tags characters
+// @Accept json
+// @Produce json
+// @Param name path string true "Character slug"
+// @Param character body entities.CharacterEntry true "Character Entry"
+// @Success 200 {object} entities.CharacterEntry
+// @Success 201 {object} entities.CharacterEntry
+// @Header 201 {string} Location "URL of the created character"
+// @Failure 400 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 503 {object} Problem
+// @Router /characters/{name} [put]
 func (c *CharactersController) Put(g *gin.Context) {
 	var character entities.CharacterEntry
 	if err := g.ShouldBindJSON(&character); err != nil {
-		g.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		RespondWithBadRequest(g, err.Error())
+		return
+	}
+	slug := g.Params.ByName("name")
+
+	ref, err := c.charactersRepo.ResolveSlug(g.Request.Context(), slug)
+	if errors.Is(err, entities.ErrNotFound) {
+		if character.CharacterName == "" {
+			character.CharacterName = slug
+		}
+		err = c.create(g, &character)
+		if err != nil {
+			RespondWithError(g, err)
+		} else {
+			RespondWithCreated(g, characterLocation(character.Slug), character)
+		}
 		return
 	}
-	characterName := g.Params.ByName("name")
-	_, err := c.charactersRepo.UpdateCharacterAndActor(g.Request.Context(), &character, characterName)
 	if err == nil {
-		err = c.relationshipsRepo.UpdateAll(g.Request.Context(), character)
+		err = c.update(g, &character, ref.ID)
+	}
+	if err != nil {
+		RespondWithError(g, err)
+	} else {
+		RespondWithJSON(g, http.StatusOK, character)
 	}
+}
+
+// Patch godoc
+// @Summary Update part of a character
+// @Description Update only the fields present in the body. Changing characterName renames the character; the old slug keeps redirecting.
+// @Description actorName, actorLink and actors replace the stored actors rather than adding to them.
+// @Description Only the current or a previous slug matches; names and aliases don't.
+// @Tags characters
+// @Accept json
+// @Produce json
+// @Param name path string true "Character slug"
+// @Param character body entities.CharacterEntry true "Fields to update"
+// @Success 200 {object} entities.CharacterEntry
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 503 {object} Problem
+// @Router /characters/{name} [patch]
+func (c *CharactersController) Patch(g *gin.Context) {
+	ref, err := c.charactersRepo.ResolveSlug(g.Request.Context(), g.Params.ByName("name"))
+	if err != nil {
+		RespondWithError(g, err)
+		return
+	}
+	current, err := c.charactersRepo.Get(g.Request.Context(), ref.ID)
+	if err != nil {
+		RespondWithError(g, err)
+		return
+	}
+	if len(current) == 0 {
+		RespondWithNotFound(g)
+		return
+	}
+
+	var fields map[string]json.RawMessage
+	if err := g.ShouldBindBodyWith(&fields, binding.JSON); err != nil {
+		RespondWithBadRequest(g, err.Error())
+		return
+	}
+
+	// binding on top of the stored character leaves absent fields untouched,
+	// but the actors are replaced as a whole: actorName or actorLink alone
+	// name the only actor, actors lists them all
+	character := current[0]
+	_, hasActorName := fields["actorName"]
+	_, hasActorLink := fields["actorLink"]
+	_, hasActors := fields["actors"]
+	if hasActorName || hasActorLink || hasActors {
+		character.Actors = nil
+	}
+	if hasActors && !hasActorName {
+		character.ActorName, character.ActorLink = "", ""
+	}
+	// the stored nickname is also stored as an alias, which a new nickname
+	// replaces
+	if _, ok := fields["nickname"]; ok && character.Nickname != "" {
+		nickname := entities.Alias{Name: character.Nickname, Type: entities.AliasNickname}
+		character.Aliases = slices.DeleteFunc(slices.Clone(character.Aliases), func(alias entities.Alias) bool {
+			return alias == nickname
+		})
+	}
+	if err := g.ShouldBindBodyWith(&character, binding.JSON); err != nil {
+		RespondWithBadRequest(g, err.Error())
+		return
+	}
+	if character.CharacterName == "" {
+		RespondWithError(g, entities.NewValidationError("characterName cannot be empty"))
+		return
+	}
+
+	err = c.update(g, &character, ref.ID)
 	if err != nil {
-		RespondWithError(g, http.StatusBadRequest, err.Error())
+		RespondWithError(g, err)
 	} else {
 		RespondWithJSON(g, http.StatusOK, character)
 	}
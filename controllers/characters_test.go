@@ -18,7 +18,7 @@ func TestGetAll(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockCharactersRepo := new(mocks.CharactersRepositoryMock)
 	mockRelationshipsRepo := new(mocks.RelationshipsRepositoryMock)
-	controller := NewCharactersController(mockCharactersRepo, mockRelationshipsRepo)
+	controller := NewCharactersController(mockCharactersRepo, sharedTx(mockCharactersRepo, mockRelationshipsRepo))
 
 	t.Run("success", func(t *testing.T) {
 		mockCharactersRepo.GetAllFunc = func(ctx context.Context, page int) ([]entities.CharacterEntry, error) {
@@ -45,7 +45,23 @@ func TestGetAll(t *testing.T) {
 
 		controller.GetAll(c)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+		assert.NotContains(t, w.Body.String(), "some error")
+	})
+
+	t.Run("database unavailable", func(t *testing.T) {
+		mockCharactersRepo.GetAllFunc = func(ctx context.Context, page int) ([]entities.CharacterEntry, error) {
+			return nil, entities.NewUnavailableError("database is unavailable")
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/characters?page=0", nil)
+
+		controller.GetAll(c)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
 	})
 
 	t.Run("no page passed", func(t *testing.T) {
@@ -78,10 +94,13 @@ func TestGet(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockCharactersRepo := new(mocks.CharactersRepositoryMock)
 	mockRelationshipsRepo := new(mocks.RelationshipsRepositoryMock)
-	controller := NewCharactersController(mockCharactersRepo, mockRelationshipsRepo)
+	controller := NewCharactersController(mockCharactersRepo, sharedTx(mockCharactersRepo, mockRelationshipsRepo))
+	mockCharactersRepo.ResolveFunc = func(ctx context.Context, key string) (entities.CharacterRef, error) {
+		return entities.CharacterRef{ID: 1, Slug: "test"}, nil
+	}
 
 	t.Run("success", func(t *testing.T) {
-		mockCharactersRepo.GetFunc = func(ctx context.Context, name string) ([]entities.CharacterEntry, error) {
+		mockCharactersRepo.GetFunc = func(ctx context.Context, characterID int) ([]entities.CharacterEntry, error) {
 			return []entities.CharacterEntry{{CharacterName: "test"}}, nil
 		}
 
@@ -96,7 +115,7 @@ func TestGet(t *testing.T) {
 	})
 
 	t.Run("failed to get", func(t *testing.T) {
-		mockCharactersRepo.GetFunc = func(ctx context.Context, name string) ([]entities.CharacterEntry, error) {
+		mockCharactersRepo.GetFunc = func(ctx context.Context, characterID int) ([]entities.CharacterEntry, error) {
 			return nil, fmt.Errorf("some error")
 		}
 		w := httptest.NewRecorder()
@@ -106,12 +125,12 @@ func TestGet(t *testing.T) {
 
 		controller.Get(c)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
 	})
 
 	t.Run("not found", func(t *testing.T) {
-		mockCharactersRepo.GetFunc = func(ctx context.Context, name string) ([]entities.CharacterEntry, error) {
-			return nil, nil
+		mockCharactersRepo.ResolveFunc = func(ctx context.Context, key string) (entities.CharacterRef, error) {
+			return entities.CharacterRef{}, entities.NewNotFoundError("character %q not found", key)
 		}
 
 		w := httptest.NewRecorder()
@@ -123,16 +142,82 @@ func TestGet(t *testing.T) {
 
 		assert.Equal(t, http.StatusNotFound, w.Code)
 	})
+
+	t.Run("old slug redirects", func(t *testing.T) {
+		mockCharactersRepo.ResolveFunc = func(ctx context.Context, key string) (entities.CharacterRef, error) {
+			return entities.CharacterRef{ID: 1, Slug: "jon-snow"}, nil
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "name", Value: "Jon Snow"}}
+		c.Request, _ = http.NewRequest("GET", "/characters/Jon%20Snow?x=1", nil)
+
+		controller.Get(c)
+
+		assert.Equal(t, http.StatusMovedPermanently, w.Code)
+		assert.Equal(t, "/characters/jon-snow?x=1", w.Header().Get("Location"))
+	})
+}
+
+func TestGetByID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCharactersRepo := new(mocks.CharactersRepositoryMock)
+	mockRelationshipsRepo := new(mocks.RelationshipsRepositoryMock)
+	controller := NewCharactersController(mockCharactersRepo, sharedTx(mockCharactersRepo, mockRelationshipsRepo))
+
+	t.Run("success", func(t *testing.T) {
+		mockCharactersRepo.GetFunc = func(ctx context.Context, characterID int) ([]entities.CharacterEntry, error) {
+			assert.Equal(t, 42, characterID)
+			return []entities.CharacterEntry{{CharacterID: 42, CharacterName: "test"}}, nil
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "id", Value: "42"}}
+		c.Request, _ = http.NewRequest("GET", "/characters/id/42", nil)
+
+		controller.GetByID(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockCharactersRepo.GetFunc = func(ctx context.Context, characterID int) ([]entities.CharacterEntry, error) {
+			return nil, nil
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "id", Value: "42"}}
+		c.Request, _ = http.NewRequest("GET", "/characters/id/42", nil)
+
+		controller.GetByID(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "id", Value: "abc"}}
+		c.Request, _ = http.NewRequest("GET", "/characters/id/abc", nil)
+
+		controller.GetByID(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
 }
 
 func TestPost(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockCharactersRepo := new(mocks.CharactersRepositoryMock)
 	mockRelationshipsRepo := new(mocks.RelationshipsRepositoryMock)
-	controller := NewCharactersController(mockCharactersRepo, mockRelationshipsRepo)
+	controller := NewCharactersController(mockCharactersRepo, sharedTx(mockCharactersRepo, mockRelationshipsRepo))
 
 	t.Run("success", func(t *testing.T) {
 		mockCharactersRepo.CreateCharacterAndActorFunc = func(ctx context.Context, character *entities.CharacterEntry) error {
+			character.Slug = "test"
 			return nil
 		}
 		mockRelationshipsRepo.AddAllFunc = func(ctx context.Context, character entities.CharacterEntry) error {
@@ -141,12 +226,13 @@ func TestPost(t *testing.T) {
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
-		c.Request, _ = http.NewRequest("POST", "/characters", strings.NewReader(`{"name":"test"}`))
+		c.Request, _ = http.NewRequest("POST", "/characters", strings.NewReader(`{"characterName":"test"}`))
 		c.Request.Header.Set("Content-Type", "application/json")
 
 		controller.Post(c)
 
-		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "/characters/test", w.Header().Get("Location"))
 	})
 
 	t.Run("invalid json", func(t *testing.T) {
@@ -160,16 +246,70 @@ func TestPost(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
+	t.Run("missing name", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/characters", strings.NewReader(`{"nickname":"test"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		controller.Post(c)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("relationships fail in the same transaction", func(t *testing.T) {
+		transactor := sharedTx(mockCharactersRepo, mockRelationshipsRepo)
+		controller := NewCharactersController(mockCharactersRepo, transactor)
+		mockCharactersRepo.CreateCharacterAndActorFunc = func(ctx context.Context, character *entities.CharacterEntry) error {
+			return nil
+		}
+		mockRelationshipsRepo.AddAllFunc = func(ctx context.Context, character entities.CharacterEntry) error {
+			return fmt.Errorf("some error")
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/characters", strings.NewReader(`{"characterName":"test"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		controller.Post(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Len(t, transactor.InTxCalls(), 1)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		mockCharactersRepo.CreateCharacterAndActorFunc = func(ctx context.Context, character *entities.CharacterEntry) error {
+			return fmt.Errorf("wrapped: %w", entities.NewConflictError("a character with this name already exists"))
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/characters", strings.NewReader(`{"characterName":"test"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		controller.Post(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "a character with this name already exists")
+	})
 }
 
 func TestDelete(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockCharactersRepo := new(mocks.CharactersRepositoryMock)
 	mockRelationshipsRepo := new(mocks.RelationshipsRepositoryMock)
-	controller := NewCharactersController(mockCharactersRepo, mockRelationshipsRepo)
+	mockListener := new(mocks.CharacterListenerMock)
+	controller := NewCharactersController(mockCharactersRepo, sharedTx(mockCharactersRepo, mockRelationshipsRepo), mockListener)
+	mockCharactersRepo.ResolveSlugFunc = func(ctx context.Context, slug string) (entities.CharacterRef, error) {
+		return entities.CharacterRef{ID: 1, Slug: "test"}, nil
+	}
 
 	t.Run("success", func(t *testing.T) {
-		mockCharactersRepo.DeleteFunc = func(ctx context.Context, name string) error {
+		mockCharactersRepo.DeleteFunc = func(ctx context.Context, characterID int) error {
+			return nil
+		}
+		mockListener.CharacterDeletedFunc = func(ctx context.Context, characterID int) error {
 			return nil
 		}
 
@@ -180,11 +320,15 @@ func TestDelete(t *testing.T) {
 
 		controller.Delete(c)
 
-		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Body.String())
+		assert.Empty(t, mockCharactersRepo.ResolveCalls(), "writes must not resolve names or aliases")
+		assert.Len(t, mockListener.CharacterDeletedCalls(), 1)
+		assert.Equal(t, 1, mockListener.CharacterDeletedCalls()[0].CharacterID)
 	})
 
 	t.Run("error", func(t *testing.T) {
-		mockCharactersRepo.DeleteFunc = func(ctx context.Context, name string) error {
+		mockCharactersRepo.DeleteFunc = func(ctx context.Context, characterID int) error {
 			return fmt.Errorf("some error")
 		}
 
@@ -195,7 +339,22 @@ func TestDelete(t *testing.T) {
 
 		controller.Delete(c)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockCharactersRepo.ResolveSlugFunc = func(ctx context.Context, slug string) (entities.CharacterRef, error) {
+			return entities.CharacterRef{}, entities.NewNotFoundError("character %q not found", slug)
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "name", Value: "test"}}
+		c.Request, _ = http.NewRequest("DELETE", "/characters/test", nil)
+
+		controller.Delete(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
 	})
 }
 
@@ -203,10 +362,34 @@ func TestPut(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockCharactersRepo := new(mocks.CharactersRepositoryMock)
 	mockRelationshipsRepo := new(mocks.RelationshipsRepositoryMock)
-	controller := NewCharactersController(mockCharactersRepo, mockRelationshipsRepo)
+	controller := NewCharactersController(mockCharactersRepo, sharedTx(mockCharactersRepo, mockRelationshipsRepo))
+	mockCharactersRepo.ResolveSlugFunc = func(ctx context.Context, slug string) (entities.CharacterRef, error) {
+		return entities.CharacterRef{ID: 1, Slug: "test"}, nil
+	}
 
 	t.Run("success", func(t *testing.T) {
-		mockCharactersRepo.UpdateCharacterAndActorFunc = func(ctx context.Context, characterEntryEntry *entities.CharacterEntry, characterName string) (int, error) {
+		mockCharactersRepo.UpdateCharacterAndActorFunc = func(ctx context.Context, characterEntryEntry *entities.CharacterEntry, characterID int) (int, error) {
+			return 1, nil
+		}
+		mockRelationshipsRepo.UpdateAllFunc = func(ctx context.Context, character entities.CharacterEntry) error {
+			return nil
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "name", Value: "test"}}
+		c.Request, _ = http.NewRequest("PUT", "/characters/test", strings.NewReader(`{"characterName":"test"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		controller.Put(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		mockCharactersRepo.UpdateCharacterAndActorFunc = func(ctx context.Context, characterEntryEntry *entities.CharacterEntry, characterID int) (int, error) {
+			assert.Equal(t, 1, characterID)
+			characterEntryEntry.Slug = entities.Slugify(characterEntryEntry.CharacterName)
 			return 1, nil
 		}
 		mockRelationshipsRepo.UpdateAllFunc = func(ctx context.Context, character entities.CharacterEntry) error {
@@ -216,12 +399,13 @@ func TestPut(t *testing.T) {
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 		c.Params = gin.Params{{Key: "name", Value: "test"}}
-		c.Request, _ = http.NewRequest("PUT", "/characters/test", strings.NewReader(`{"name":"test"}`))
+		c.Request, _ = http.NewRequest("PUT", "/characters/test", strings.NewReader(`{"characterName":"Other Name"}`))
 		c.Request.Header.Set("Content-Type", "application/json")
 
 		controller.Put(c)
 
 		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"slug":"other-name"`)
 	})
 
 	t.Run("invalid json", func(t *testing.T) {
@@ -235,4 +419,156 @@ func TestPut(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
+	t.Run("creates missing character", func(t *testing.T) {
+		mockCharactersRepo.ResolveSlugFunc = func(ctx context.Context, slug string) (entities.CharacterRef, error) {
+			return entities.CharacterRef{}, entities.NewNotFoundError("character %q not found", slug)
+		}
+		mockCharactersRepo.CreateCharacterAndActorFunc = func(ctx context.Context, character *entities.CharacterEntry) error {
+			character.Slug = "jon-snow"
+			return nil
+		}
+		mockRelationshipsRepo.AddAllFunc = func(ctx context.Context, character entities.CharacterEntry) error {
+			return nil
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "name", Value: "jon-snow"}}
+		c.Request, _ = http.NewRequest("PUT", "/characters/jon-snow", strings.NewReader(`{"characterName":"Jon Snow"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		controller.Put(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "/characters/jon-snow", w.Header().Get("Location"))
+		assert.Contains(t, w.Body.String(), `"characterName":"Jon Snow"`)
+	})
+}
+
+func TestPatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCharactersRepo := new(mocks.CharactersRepositoryMock)
+	mockRelationshipsRepo := new(mocks.RelationshipsRepositoryMock)
+	controller := NewCharactersController(mockCharactersRepo, sharedTx(mockCharactersRepo, mockRelationshipsRepo))
+	mockCharactersRepo.ResolveSlugFunc = func(ctx context.Context, slug string) (entities.CharacterRef, error) {
+		return entities.CharacterRef{ID: 1, Slug: "jon-snow"}, nil
+	}
+	mockCharactersRepo.GetFunc = func(ctx context.Context, characterID int) ([]entities.CharacterEntry, error) {
+		return []entities.CharacterEntry{{CharacterID: 1, CharacterName: "Jon Snow", Slug: "jon-snow", Nickname: "Lord Snow"}}, nil
+	}
+
+	t.Run("renames and keeps other fields", func(t *testing.T) {
+		var updated entities.CharacterEntry
+		mockCharactersRepo.UpdateCharacterAndActorFunc = func(ctx context.Context, characterEntryEntry *entities.CharacterEntry, characterID int) (int, error) {
+			updated = *characterEntryEntry
+			return 1, nil
+		}
+		mockRelationshipsRepo.UpdateAllFunc = func(ctx context.Context, character entities.CharacterEntry) error {
+			return nil
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "name", Value: "jon-snow"}}
+		c.Request, _ = http.NewRequest("PATCH", "/characters/jon-snow", strings.NewReader(`{"characterName":"Aegon Targaryen"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		controller.Patch(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "Aegon Targaryen", updated.CharacterName)
+		assert.Equal(t, "Lord Snow", updated.Nickname)
+	})
+
+	t.Run("actorName replaces the stored actors", func(t *testing.T) {
+		mockCharactersRepo.GetFunc = func(ctx context.Context, characterID int) ([]entities.CharacterEntry, error) {
+			return []entities.CharacterEntry{{
+				CharacterID: 1, CharacterName: "Jon Snow", Slug: "jon-snow",
+				ActorName: "Kit Harington",
+				Actors:    []entities.ActorEntry{{ActorName: "Kit Harington"}, {ActorName: "Sebastian Croft"}},
+			}}, nil
+		}
+		var updated entities.CharacterEntry
+		mockCharactersRepo.UpdateCharacterAndActorFunc = func(ctx context.Context, characterEntryEntry *entities.CharacterEntry, characterID int) (int, error) {
+			updated = *characterEntryEntry
+			return 1, nil
+		}
+		mockRelationshipsRepo.UpdateAllFunc = func(ctx context.Context, character entities.CharacterEntry) error {
+			return nil
+		}
+
+		for body, actors := range map[string][]string{
+			`{"actorName":"Someone Else"}`:              {"Someone Else"},
+			`{"actors":[{"actorName":"Someone Else"}]}`: {"Someone Else"},
+			`{"actorLink":"/name/nm3229685/"}`:          {"Kit Harington"},
+			`{"characterName":"Jon Snow","royal":true}`: {"Kit Harington", "Sebastian Croft"},
+		} {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "name", Value: "jon-snow"}}
+			c.Request, _ = http.NewRequest("PATCH", "/characters/jon-snow", strings.NewReader(body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			controller.Patch(c)
+
+			assert.Equal(t, http.StatusOK, w.Code, body)
+			var names []string
+			for _, actor := range updated.AllActors() {
+				names = append(names, actor.ActorName)
+			}
+			assert.Equal(t, actors, names, body)
+		}
+	})
+
+	t.Run("nickname replaces the nickname alias", func(t *testing.T) {
+		mockCharactersRepo.GetFunc = func(ctx context.Context, characterID int) ([]entities.CharacterEntry, error) {
+			return []entities.CharacterEntry{{
+				CharacterID: 1, CharacterName: "Jon Snow", Slug: "jon-snow", Nickname: "Lord Snow",
+				Aliases: []entities.Alias{{Name: "Lord Snow", Type: entities.AliasNickname}, {Name: "The White Wolf", Type: entities.AliasTitle}},
+			}}, nil
+		}
+		var updated entities.CharacterEntry
+		mockCharactersRepo.UpdateCharacterAndActorFunc = func(ctx context.Context, characterEntryEntry *entities.CharacterEntry, characterID int) (int, error) {
+			updated = *characterEntryEntry
+			return 1, nil
+		}
+		mockRelationshipsRepo.UpdateAllFunc = func(ctx context.Context, character entities.CharacterEntry) error {
+			return nil
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "name", Value: "jon-snow"}}
+		c.Request, _ = http.NewRequest("PATCH", "/characters/jon-snow", strings.NewReader(`{"nickname":"King in the North"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		controller.Patch(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []entities.Alias{
+			{Name: "The White Wolf", Type: entities.AliasTitle},
+			{Name: "King in the North", Type: entities.AliasNickname},
+		}, updated.AllAliases())
+	})
+
+	t.Run("empty name", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "name", Value: "jon-snow"}}
+		c.Request, _ = http.NewRequest("PATCH", "/characters/jon-snow", strings.NewReader(`{"characterName":""}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		controller.Patch(c)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+}
+
+// sharedTx runs the transaction with the mocks, committing nothing.
+func sharedTx(characters *mocks.CharactersRepositoryMock, relationships *mocks.RelationshipsRepositoryMock) *mocks.CharacterTransactorMock {
+	return &mocks.CharacterTransactorMock{
+		InTxFunc: func(ctx context.Context, fn func(entities.CharactersRepository, entities.RelationshipsRepository) error) error {
+			return fn(characters, relationships)
+		},
+	}
 }
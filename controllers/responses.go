@@ -1,21 +1,94 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/vitalii-komenda/got/entities"
 )
 
-func RespondWithError(g *gin.Context, code int, message string) {
-	fmt.Print("error response", message)
-	g.JSON(code, gin.H{"error": message})
+const problemContentType = "application/problem+json"
+
+// statusClientClosedRequest is the nginx status for a client that went away
+// before the answer, which net/http has no constant or text for.
+const statusClientClosedRequest = 499
+
+// Problem is an RFC 7807 error body.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+// RespondWithError maps err onto a status code by its entities error kind.
+// Errors without a kind are reported as 500 and their text is only logged.
+func RespondWithError(g *gin.Context, err error) {
+	fmt.Printf("error response: %v\n", err)
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, entities.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, entities.ErrConflict):
+		status = http.StatusConflict
+	case errors.Is(err, entities.ErrValidation):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, entities.ErrUnavailable):
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, entities.ErrBadGateway):
+		status = http.StatusBadGateway
+	case errors.Is(err, entities.ErrCanceled):
+		status = statusClientClosedRequest
+	}
+
+	detail := ""
+	var domainErr *entities.DomainError
+	if errors.As(err, &domainErr) {
+		detail = domainErr.Detail
+	}
+	RespondWithProblem(g, status, detail)
+}
+
+func RespondWithProblem(g *gin.Context, code int, detail string) {
+	g.Header("Content-Type", problemContentType)
+	g.JSON(code, Problem{
+		Type:     "about:blank",
+		Title:    statusText(code),
+		Status:   code,
+		Detail:   detail,
+		Instance: g.Request.URL.Path,
+	})
+}
+
+func statusText(code int) string {
+	if code == statusClientClosedRequest {
+		return "Client Closed Request"
+	}
+	return http.StatusText(code)
+}
+
+func RespondWithBadRequest(g *gin.Context, detail string) {
+	RespondWithProblem(g, http.StatusBadRequest, detail)
 }
 
 func RespondWithJSON(g *gin.Context, code int, payload interface{}) {
 	g.JSON(code, payload)
 }
 
+func RespondWithCreated(g *gin.Context, location string, payload interface{}) {
+	g.Header("Location", location)
+	g.JSON(http.StatusCreated, payload)
+}
+
+func RespondWithNoContent(g *gin.Context) {
+	g.Status(http.StatusNoContent)
+	g.Writer.WriteHeaderNow()
+}
+
 func RespondWithNotFound(g *gin.Context) {
-	g.JSON(http.StatusNotFound, gin.H{})
+	RespondWithProblem(g, http.StatusNotFound, "")
 }
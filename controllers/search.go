@@ -1,45 +1,287 @@
 package controllers
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/vitalii-komenda/got/entities"
-	"github.com/vitalii-komenda/got/services"
 )
 
 type SearchController struct {
 	charactersRepo entities.CharactersRepository
+	searcher       entities.Searcher
+	suggester      entities.Suggester
+	elastic        entities.FacetedSearcher
+	similar        entities.SimilarFinder
 }
 
 func NewSearchController(
 	characterRepo entities.CharactersRepository,
+	searcher entities.Searcher,
+	suggester entities.Suggester,
+	elastic entities.FacetedSearcher,
+	similar entities.SimilarFinder,
 ) *SearchController {
 	return &SearchController{
 		charactersRepo: characterRepo,
+		searcher:       searcher,
+		suggester:      suggester,
+		elastic:        elastic,
+		similar:        similar,
 	}
 }
 
-// GetFromElastic godoc
-// @Summary Search characters in elastic
-// @Description Search characters by term in elastic
+// Search godoc
+// @Summary Search characters
+// @Description Search characters by name, alias, nickname, actor or house with the configured search backend.
+// @Description The term can filter on fields, as in house:Stark royal:true alive:false killed:>3 actor:"Kit Harington" -alias:Bastard.
+// @Description Fields are name, alias, house, actor, royal, alive and killed, which compares with >, >=, < and <=.
 // @Tags search
 // @Accept  json
 // @Produce  json
-// @Param term query string true "Search term"
+// @Param term query string true "Search term or structured query"
+// @Param page query int false "Page number"
 // @Success 200 {array} []entities.CharacterEntry
-// @Failure 400 {object} map[string]any
-// @Failure 404 {object} map[string]any
-// @Router /elastic/search [get]
-func (c *SearchController) GetFromElastic(g *gin.Context) {
+// @Failure 400 {object} Problem
+// @Failure 502 {object} Problem
+// @Failure 503 {object} Problem
+// @Router /search [get]
+func (c *SearchController) Search(g *gin.Context) {
 	term := g.Query("term")
-	value, err := services.SendElasticSearchRequest(term)
+	if term == "" {
+		RespondWithBadRequest(g, "term is required")
+		return
+	}
+	page, ok := queryInt(g, "page")
+	if !ok {
+		return
+	}
+	if page < 0 {
+		RespondWithBadRequest(g, "page must not be negative")
+		return
+	}
+	query, err := entities.ParseQuery(term)
+	if err != nil {
+		RespondWithBadRequest(g, err.Error())
+		return
+	}
+
+	value, err := c.searcher.Search(g.Request.Context(), entities.SearchQuery{Term: term, Page: page, Query: query})
+	if err != nil {
+		RespondWithError(g, err)
+	} else {
+		RespondWithJSON(g, http.StatusOK, value)
+	}
+}
+
+// Suggest godoc
+// @Summary Suggest names
+// @Description Complete a prefix with character, alias, actor and house names for type-ahead, best first
+// @Tags search
+// @Accept  json
+// @Produce  json
+// @Param prefix query string true "What the user typed so far"
+// @Param size query int false "Number of suggestions, 10 by default and at most 50"
+// @Success 200 {array} entities.Suggestion
+// @Failure 400 {object} Problem
+// @Failure 503 {object} Problem
+// @Router /search/suggest [get]
+func (c *SearchController) Suggest(g *gin.Context) {
+	prefix := strings.TrimSpace(g.Query("prefix"))
+	if prefix == "" {
+		RespondWithBadRequest(g, "prefix is required")
+		return
+	}
+	size, ok := queryInt(g, "size")
+	if !ok {
+		return
+	}
+	if g.Query("size") == "" {
+		size = 10
+	} else if size < 1 || size > 50 {
+		RespondWithBadRequest(g, "size must be between 1 and 50")
+		return
+	}
+
+	value, err := c.suggester.Suggest(g.Request.Context(), prefix, size)
+	if err != nil {
+		RespondWithError(g, err)
+	} else {
+		RespondWithJSON(g, http.StatusOK, value)
+	}
+}
+
+// Similar godoc
+// @Summary Similar characters
+// @Description Characters like the given one: sharing houses, relatives, actors and seasons, or with alike names, most similar first.
+// @Description Scored with more_like_this when Elasticsearch is the search backend, falling back to Postgres, which also tells the reasons.
+// @Tags characters
+// @Accept  json
+// @Produce  json
+// @Param name path string true "Character slug"
+// @Param size query int false "Number of characters, 10 by default and at most 50"
+// @Success 200 {array} entities.SimilarCharacter
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 503 {object} Problem
+// @Router /characters/{name}/similar [get]
+func (c *SearchController) Similar(g *gin.Context) {
+	size, ok := queryInt(g, "size")
+	if !ok {
+		return
+	}
+	if g.Query("size") == "" {
+		size = 10
+	} else if size < 1 || size > 50 {
+		RespondWithBadRequest(g, "size must be between 1 and 50")
+		return
+	}
+
+	ref, err := c.charactersRepo.Resolve(g.Request.Context(), g.Params.ByName("name"))
+	var value []entities.SimilarCharacter
+	if err == nil {
+		value, err = c.similar.Similar(g.Request.Context(), ref.ID, size)
+	}
 
 	if err != nil {
-		RespondWithError(g, http.StatusBadRequest, err.Error())
-	} else if len(value) == 0 {
-		RespondWithNotFound(g)
+		RespondWithError(g, err)
 	} else {
 		RespondWithJSON(g, http.StatusOK, value)
 	}
 }
+
+// GetFromElastic godoc
+// @Summary Search characters in elastic
+// @Description Search characters by term in elastic, filtered by house, royal status or whether they are alive, with facet counts per house and royal status.
+// @Description Hits can highlight the fragments the term matched and, with debug=explain, explain their score.
+// @Description Hits have the same fields as /characters answers with.
+// @Tags search
+// @Accept  json
+// @Produce  json
+// @Param term query string false "Search term, all characters when empty"
+// @Param house query string false "House name"
+// @Param royal query bool false "Royal status"
+// @Param alive query bool false "Whether the character is alive"
+// @Param from query int false "Offset of the first hit"
+// @Param size query int false "Number of hits, 10 by default and at most 100"
+// @Param sort query string false "relevance, name or -name"
+// @Param highlight query bool false "Highlight the matched fragments of every hit"
+// @Param debug query string false "explain to add the score breakdown of every hit"
+// @Success 200 {object} entities.SearchResult
+// @Failure 400 {object} Problem
+// @Failure 500 {object} Problem
+// @Failure 502 {object} Problem
+// @Failure 503 {object} Problem
+// @Router /elastic/search [get]
+func (c *SearchController) GetFromElastic(g *gin.Context) {
+	result, ok := c.queryElastic(g)
+	if ok {
+		RespondWithJSON(g, http.StatusOK, result.SearchResult())
+	}
+}
+
+// GetFromElasticV1 godoc
+// @Summary Search characters in elastic, index document shape
+// @Description Same as /elastic/search, with the hits as the snake_case documents of the index, for clients written before /elastic/search answered like /characters.
+// @Tags search
+// @Accept  json
+// @Produce  json
+// @Param term query string false "Search term, all characters when empty"
+// @Param house query string false "House name"
+// @Param royal query bool false "Royal status"
+// @Param alive query bool false "Whether the character is alive"
+// @Param from query int false "Offset of the first hit"
+// @Param size query int false "Number of hits, 10 by default and at most 100"
+// @Param sort query string false "relevance, name or -name"
+// @Param highlight query bool false "Highlight the matched fragments of every hit"
+// @Param debug query string false "explain to add the score breakdown of every hit"
+// @Success 200 {object} entities.ElasticSearchResult
+// @Failure 400 {object} Problem
+// @Failure 500 {object} Problem
+// @Failure 502 {object} Problem
+// @Failure 503 {object} Problem
+// @Router /v1/elastic/search [get]
+func (c *SearchController) GetFromElasticV1(g *gin.Context) {
+	result, ok := c.queryElastic(g)
+	if ok {
+		RespondWithJSON(g, http.StatusOK, result)
+	}
+}
+
+// queryElastic runs the search the query parameters describe. It responds
+// with the error and returns false when it failed.
+func (c *SearchController) queryElastic(g *gin.Context) (entities.ElasticSearchResult, bool) {
+	query := entities.ElasticSearchQuery{
+		Term:  g.Query("term"),
+		House: g.Query("house"),
+		Sort:  g.Query("sort"),
+	}
+	var ok bool
+	if query.Royal, ok = queryBool(g, "royal"); !ok {
+		return entities.ElasticSearchResult{}, false
+	}
+	if query.Alive, ok = queryBool(g, "alive"); !ok {
+		return entities.ElasticSearchResult{}, false
+	}
+	if query.From, ok = queryInt(g, "from"); !ok {
+		return entities.ElasticSearchResult{}, false
+	}
+	if query.Size, ok = queryInt(g, "size"); !ok {
+		return entities.ElasticSearchResult{}, false
+	}
+	highlight, ok := queryBool(g, "highlight")
+	if !ok {
+		return entities.ElasticSearchResult{}, false
+	}
+	query.Highlight = highlight != nil && *highlight
+
+	if query.From < 0 {
+		RespondWithBadRequest(g, "from must not be negative")
+		return entities.ElasticSearchResult{}, false
+	}
+	if g.Query("size") == "" {
+		query.Size = 10
+	} else if query.Size < 1 || query.Size > 100 {
+		RespondWithBadRequest(g, "size must be between 1 and 100")
+		return entities.ElasticSearchResult{}, false
+	}
+	switch query.Sort {
+	case "", entities.SortRelevance, entities.SortName, entities.SortNameDesc:
+	default:
+		RespondWithBadRequest(g, "sort must be relevance, name or -name")
+		return entities.ElasticSearchResult{}, false
+	}
+	switch g.Query("debug") {
+	case "":
+	case "explain":
+		query.Explain = true
+	default:
+		RespondWithBadRequest(g, "debug must be explain")
+		return entities.ElasticSearchResult{}, false
+	}
+
+	result, err := c.elastic.Query(g.Request.Context(), query)
+	if err != nil {
+		RespondWithError(g, err)
+		return entities.ElasticSearchResult{}, false
+	}
+	return result, true
+}
+
+// queryBool reads an optional boolean query parameter, responding with
+// 400 when it is not a boolean.
+func queryBool(g *gin.Context, key string) (*bool, bool) {
+	value := g.Query(key)
+	if value == "" {
+		return nil, true
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		RespondWithBadRequest(g, fmt.Sprintf("%s must be true or false", key))
+		return nil, false
+	}
+	return &b, true
+}
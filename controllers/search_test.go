@@ -0,0 +1,289 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/vitalii-komenda/got/entities"
+	"github.com/vitalii-komenda/got/mocks"
+)
+
+func TestSearch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCharactersRepo := new(mocks.CharactersRepositoryMock)
+	mockSearcher := new(mocks.SearcherMock)
+	controller := NewSearchController(mockCharactersRepo, mockSearcher, new(mocks.SuggesterMock), new(mocks.FacetedSearcherMock), new(mocks.SimilarFinderMock))
+
+	t.Run("success", func(t *testing.T) {
+		mockSearcher.SearchFunc = func(ctx context.Context, query entities.SearchQuery) ([]entities.CharacterEntry, error) {
+			assert.Equal(t, "snow", query.Term)
+			assert.Equal(t, 1, query.Page)
+			assert.Equal(t, entities.AndNode{Nodes: []entities.QueryNode{entities.TextNode{Text: "snow"}}}, query.Query)
+			return []entities.CharacterEntry{{CharacterName: "Jon Snow"}}, nil
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/search?term=snow&page=1", nil)
+
+		controller.Search(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Jon Snow")
+	})
+
+	t.Run("missing term", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/search", nil)
+
+		controller.Search(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("negative page", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/search?term=snow&page=-1", nil)
+
+		controller.Search(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "page must not be negative")
+	})
+
+	t.Run("colon in a term", func(t *testing.T) {
+		mockSearcher.SearchFunc = func(ctx context.Context, query entities.SearchQuery) ([]entities.CharacterEntry, error) {
+			assert.Equal(t, entities.AndNode{Nodes: []entities.QueryNode{entities.TextNode{Text: "Ser:"}}}, query.Query)
+			return []entities.CharacterEntry{{CharacterName: "Ser Pounce"}}, nil
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/search?term=Ser:", nil)
+
+		controller.Search(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("invalid structured query", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", `/search?term=royal:maybe`, nil)
+
+		controller.Search(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "invalid query at position 7: royal must be true or false")
+	})
+
+	t.Run("backend unavailable", func(t *testing.T) {
+		mockSearcher.SearchFunc = func(ctx context.Context, query entities.SearchQuery) ([]entities.CharacterEntry, error) {
+			return nil, entities.NewUnavailableError("elasticsearch is unreachable")
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/search?term=snow", nil)
+
+		controller.Search(c)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+}
+
+func TestGetFromElastic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockElastic := new(mocks.FacetedSearcherMock)
+	controller := NewSearchController(new(mocks.CharactersRepositoryMock), new(mocks.SearcherMock), new(mocks.SuggesterMock), mockElastic, new(mocks.SimilarFinderMock))
+
+	get := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/elastic/search?term=snow&royal=false", nil)
+		controller.GetFromElastic(c)
+		return w
+	}
+	getV1 := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/v1/elastic/search?term=snow&royal=false", nil)
+		controller.GetFromElasticV1(c)
+		return w
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockElastic.QueryFunc = func(ctx context.Context, query entities.ElasticSearchQuery) (entities.ElasticSearchResult, error) {
+			royal := false
+			assert.Equal(t, entities.ElasticSearchQuery{Term: "snow", Royal: &royal, Size: 10}, query)
+			return entities.ElasticSearchResult{Total: 1, Hits: []entities.ElasticHit{{
+				CharacterEntryElastic: entities.CharacterEntryElastic{CharacterID: 1, CharacterName: "Jon Snow", KilledBy: []string{"Olly"}},
+				Score:                 2.5,
+				Highlights:            map[string][]string{"character_name": {"Jon <em>Snow</em>"}},
+			}}}, nil
+		}
+
+		w := get()
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{
+			"total": 1,
+			"hits": [{"characterID": 1, "characterName": "Jon Snow", "killedBy": ["Olly"], "score": 2.5, "highlights": {"characterName": ["Jon <em>Snow</em>"]}}],
+			"facets": {"house": null, "royal": null}
+		}`, w.Body.String())
+
+		w = getV1()
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{
+			"total": 1,
+			"hits": [{"character_id": 1, "character_name": "Jon Snow", "killed_by": ["Olly"], "royal": false, "score": 2.5, "highlights": {"character_name": ["Jon <em>Snow</em>"]}}],
+			"facets": {"house": null, "royal": null}
+		}`, w.Body.String())
+	})
+
+	t.Run("elasticsearch down", func(t *testing.T) {
+		mockElastic.QueryFunc = func(ctx context.Context, query entities.ElasticSearchQuery) (entities.ElasticSearchResult, error) {
+			return entities.ElasticSearchResult{}, entities.NewUnavailableError("elasticsearch is unreachable")
+		}
+
+		assert.Equal(t, http.StatusServiceUnavailable, get().Code)
+		assert.Equal(t, http.StatusServiceUnavailable, getV1().Code)
+	})
+
+	t.Run("elasticsearch failed", func(t *testing.T) {
+		mockElastic.QueryFunc = func(ctx context.Context, query entities.ElasticSearchQuery) (entities.ElasticSearchResult, error) {
+			return entities.ElasticSearchResult{}, entities.NewBadGatewayError("elasticsearch responded with 400: search_phase_execution_exception")
+		}
+
+		w := get()
+
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+		assert.Contains(t, w.Body.String(), "search_phase_execution_exception")
+	})
+}
+
+func TestGetFromElasticBadRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewSearchController(new(mocks.CharactersRepositoryMock), new(mocks.SearcherMock), new(mocks.SuggesterMock), new(mocks.FacetedSearcherMock), new(mocks.SimilarFinderMock))
+
+	for _, query := range []string{"royal=maybe", "alive=1x", "from=-1", "size=0", "size=101", "size=ten", "sort=house", "highlight=yes", "debug=all"} {
+		t.Run(query, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET", "/elastic/search?"+query, nil)
+
+			controller.GetFromElastic(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSuggester := new(mocks.SuggesterMock)
+	controller := NewSearchController(new(mocks.CharactersRepositoryMock), new(mocks.SearcherMock), mockSuggester, new(mocks.FacetedSearcherMock), new(mocks.SimilarFinderMock))
+
+	t.Run("success", func(t *testing.T) {
+		mockSuggester.SuggestFunc = func(ctx context.Context, prefix string, size int) ([]entities.Suggestion, error) {
+			assert.Equal(t, "jon", prefix)
+			assert.Equal(t, 10, size)
+			return []entities.Suggestion{{Name: "Jon Snow", Type: entities.SuggestionCharacter, Slug: "jon-snow"}}, nil
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/search/suggest?prefix=jon", nil)
+
+		controller.Suggest(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[{"name":"Jon Snow","type":"character","slug":"jon-snow"}]`, w.Body.String())
+	})
+
+	t.Run("missing prefix", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/search/suggest?prefix=+", nil)
+
+		controller.Suggest(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("size out of range", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/search/suggest?prefix=jon&size=51", nil)
+
+		controller.Suggest(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestSimilar(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCharactersRepo := new(mocks.CharactersRepositoryMock)
+	mockSimilar := new(mocks.SimilarFinderMock)
+	controller := NewSearchController(mockCharactersRepo, new(mocks.SearcherMock), new(mocks.SuggesterMock), new(mocks.FacetedSearcherMock), mockSimilar)
+
+	t.Run("success", func(t *testing.T) {
+		mockCharactersRepo.ResolveFunc = func(ctx context.Context, key string) (entities.CharacterRef, error) {
+			assert.Equal(t, "jon-snow", key)
+			return entities.CharacterRef{ID: 1, Slug: "jon-snow"}, nil
+		}
+		mockSimilar.SimilarFunc = func(ctx context.Context, characterID int, size int) ([]entities.SimilarCharacter, error) {
+			assert.Equal(t, 1, characterID)
+			assert.Equal(t, 10, size)
+			return []entities.SimilarCharacter{{
+				CharacterEntry: entities.CharacterEntry{CharacterID: 2, CharacterName: "Arya Stark"},
+				Score:          5,
+				Reasons:        []string{"shares House Stark"},
+			}}, nil
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/characters/jon-snow/similar", nil)
+		c.Params = gin.Params{{Key: "name", Value: "jon-snow"}}
+
+		controller.Similar(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[{"characterID":2,"characterName":"Arya Stark","score":5,"reasons":["shares House Stark"]}]`, w.Body.String())
+	})
+
+	t.Run("unknown character", func(t *testing.T) {
+		mockCharactersRepo.ResolveFunc = func(ctx context.Context, key string) (entities.CharacterRef, error) {
+			return entities.CharacterRef{}, entities.NewNotFoundError("character %q not found", key)
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/characters/nobody/similar", nil)
+		c.Params = gin.Params{{Key: "name", Value: "nobody"}}
+
+		controller.Similar(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("size out of range", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/characters/jon-snow/similar?size=0", nil)
+		c.Params = gin.Params{{Key: "name", Value: "jon-snow"}}
+
+		controller.Similar(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -15,6 +15,179 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/actors": {
+            "get": {
+                "description": "Get all actors with pagination, optionally filtered by name, house of their characters or season",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "actors"
+                ],
+                "summary": "Get all actors",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Part of the actor name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "House of a character the actor played",
+                        "name": "house",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Season the actor appeared in",
+                        "name": "season",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.ActorEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/actors/{name}": {
+            "get": {
+                "description": "Get an actor by name or slug, matched ignoring case, accents and punctuation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "actors"
+                ],
+                "summary": "Get an actor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Actor name or slug",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.ActorEntry"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/actors/{name}/characters": {
+            "get": {
+                "description": "Get the characters an actor played and the seasons they played them in, with pagination",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "actors"
+                ],
+                "summary": "Get the characters an actor played",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Actor name or slug",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only roles played in this season",
+                        "name": "season",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.ActorRole"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    }
+                }
+            }
+        },
         "/characters": {
             "get": {
                 "description": "Get all characters with pagination",
@@ -52,8 +225,13 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
                         }
                     }
                 }
@@ -82,25 +260,48 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "$ref": "#/definitions/entities.CharacterEntry"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the created character"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
                         }
                     }
                 }
             }
         },
-        "/characters/{name}": {
+        "/characters/id/{id}": {
             "get": {
-                "description": "Get a character by name",
+                "description": "Get a character by its immutable ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -110,12 +311,12 @@ const docTemplate = `{
                 "tags": [
                     "characters"
                 ],
-                "summary": "Get a character by name",
+                "summary": "Get a character by ID",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Character name",
-                        "name": "name",
+                        "type": "integer",
+                        "description": "Character ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -133,21 +334,27 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/controllers.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a character by name",
+            }
+        },
+        "/characters/{name}": {
+            "get": {
+                "description": "Get a character by slug. Previous slugs, names and aliases, matched ignoring case, accents and punctuation, redirect to the current slug.",
                 "consumes": [
                     "application/json"
                 ],
@@ -157,11 +364,11 @@ const docTemplate = `{
                 "tags": [
                     "characters"
                 ],
-                "summary": "Delete a character by name",
+                "summary": "Get a character by slug",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Character name",
+                        "description": "Character slug",
                         "name": "name",
                         "in": "path",
                         "required": true
@@ -171,23 +378,37 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.CharacterEntry"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "301": {
+                        "description": "Moved Permanently",
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "Current URL of the character"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/controllers.Problem"
                         }
                     }
                 }
-            }
-        },
-        "/elastic/search": {
-            "get": {
-                "description": "Search characters by term in elastic",
+            },
+            "put": {
+                "description": "Replace a character and its relationships, creating it when it does not exist yet.\nA characterName different from the current one renames the character; the old slug keeps redirecting.\nOnly the current or a previous slug matches; names and aliases don't.",
                 "consumes": [
                     "application/json"
                 ],
@@ -195,51 +416,74 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "search"
+                    "characters"
                 ],
-                "summary": "Search characters in elastic",
+                "summary": "Create or replace a character",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Search term",
-                        "name": "term",
-                        "in": "query",
+                        "description": "Character slug",
+                        "name": "name",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Character Entry",
+                        "name": "character",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entities.CharacterEntry"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "array",
-                                "items": {
-                                    "$ref": "#/definitions/entities.CharacterEntry"
-                                }
+                            "$ref": "#/definitions/entities.CharacterEntry"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entities.CharacterEntry"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the created character"
                             }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/controllers.Problem"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/controllers.Problem"
                         }
                     }
                 }
-            }
-        },
-        "/search": {
-            "get": {
-                "description": "Search characters by term",
+            },
+            "delete": {
+                "description": "Delete a character by its current or a previous slug. Names and aliases don't match.",
                 "consumes": [
                     "application/json"
                 ],
@@ -247,59 +491,962 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "search"
+                    "characters"
                 ],
-                "summary": "Search characters",
+                "summary": "Delete a character by slug",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Search term",
-                        "name": "term",
-                        "in": "query",
+                        "description": "Character slug",
+                        "name": "name",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "array",
-                                "items": {
-                                    "$ref": "#/definitions/entities.CharacterEntry"
-                                }
-                            }
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/controllers.Problem"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/controllers.Problem"
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "entities.CharacterEntry": {
-            "type": "object",
-            "properties": {
-                "actorLink": {
+            },
+            "patch": {
+                "description": "Update only the fields present in the body. Changing characterName renames the character; the old slug keeps redirecting.\nactorName, actorLink and actors replace the stored actors rather than adding to them.\nOnly the current or a previous slug matches; names and aliases don't.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "characters"
+                ],
+                "summary": "Update part of a character",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Character slug",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "character",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entities.CharacterEntry"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.CharacterEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/characters/{name}/similar": {
+            "get": {
+                "description": "Characters like the given one: sharing houses, relatives, actors and seasons, or with alike names, most similar first.\nScored with more_like_this when Elasticsearch is the search backend, falling back to Postgres, which also tells the reasons.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "characters"
+                ],
+                "summary": "Similar characters",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Character slug",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of characters, 10 by default and at most 50",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.SimilarCharacter"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/elastic/search": {
+            "get": {
+                "description": "Search characters by term in elastic, filtered by house, royal status or whether they are alive, with facet counts per house and royal status.\nHits can highlight the fragments the term matched and, with debug=explain, explain their score.\nHits have the same fields as /characters answers with.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Search characters in elastic",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search term, all characters when empty",
+                        "name": "term",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "House name",
+                        "name": "house",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Royal status",
+                        "name": "royal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Whether the character is alive",
+                        "name": "alive",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset of the first hit",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of hits, 10 by default and at most 100",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "relevance, name or -name",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Highlight the matched fragments of every hit",
+                        "name": "highlight",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "explain to add the score breakdown of every hit",
+                        "name": "debug",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.SearchResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "description": "Search characters by name, alias, nickname, actor or house with the configured search backend.\nThe term can filter on fields, as in house:Stark royal:true alive:false killed:\u003e3 actor:\"Kit Harington\" -alias:Bastard.\nFields are name, alias, house, actor, royal, alive and killed, which compares with \u003e, \u003e=, \u003c and \u003c=.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Search characters",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search term or structured query",
+                        "name": "term",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/entities.CharacterEntry"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/search/suggest": {
+            "get": {
+                "description": "Complete a prefix with character, alias, actor and house names for type-ahead, best first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Suggest names",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "What the user typed so far",
+                        "name": "prefix",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of suggestions, 10 by default and at most 50",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.Suggestion"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/elastic/search": {
+            "get": {
+                "description": "Same as /elastic/search, with the hits as the snake_case documents of the index, for clients written before /elastic/search answered like /characters.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Search characters in elastic, index document shape",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search term, all characters when empty",
+                        "name": "term",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "House name",
+                        "name": "house",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Royal status",
+                        "name": "royal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Whether the character is alive",
+                        "name": "alive",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset of the first hit",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of hits, 10 by default and at most 100",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "relevance, name or -name",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Highlight the matched fragments of every hit",
+                        "name": "highlight",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "explain to add the score breakdown of every hit",
+                        "name": "debug",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.ElasticSearchResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "controllers.Problem": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string"
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.ActorEntry": {
+            "type": "object",
+            "properties": {
+                "actorID": {
+                    "type": "integer"
+                },
+                "actorLink": {
+                    "type": "string"
+                },
+                "actorName": {
+                    "type": "string"
+                },
+                "characters": {
+                    "type": "integer"
+                },
+                "seasonsActive": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "entities.ActorRole": {
+            "type": "object",
+            "properties": {
+                "characterID": {
+                    "type": "integer"
+                },
+                "characterName": {
+                    "type": "string"
+                },
+                "houseName": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "seasonsActive": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.Alias": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/entities.AliasType"
+                }
+            }
+        },
+        "entities.AliasType": {
+            "type": "string",
+            "enum": [
+                "nickname",
+                "title",
+                "birth_name"
+            ],
+            "x-enum-varnames": [
+                "AliasNickname",
+                "AliasTitle",
+                "AliasBirthName"
+            ]
+        },
+        "entities.CharacterEntry": {
+            "type": "object",
+            "properties": {
+                "actorLink": {
+                    "type": "string"
+                },
+                "actorName": {
+                    "type": "string"
+                },
+                "actors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.ActorEntry"
+                    }
+                },
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.Alias"
+                    }
+                },
+                "characterID": {
+                    "type": "integer"
+                },
+                "characterImageFull": {
+                    "type": "string"
+                },
+                "characterImageThumb": {
+                    "type": "string"
+                },
+                "characterLink": {
+                    "type": "string"
+                },
+                "characterName": {
+                    "type": "string"
+                },
+                "extra": {
+                    "description": "Extra keeps the attributes of the imported dataset no field maps to,\nlike parentOf or serves.",
+                    "type": "object"
+                },
+                "houseName": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "killed": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "killedBy": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "marriedEngaged": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "nickname": {
+                    "type": "string"
+                },
+                "parents": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "royal": {
+                    "type": "boolean"
+                },
+                "siblings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.ElasticFacets": {
+            "type": "object",
+            "properties": {
+                "house": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.FacetCount"
+                    }
+                },
+                "royal": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.FacetCount"
+                    }
+                }
+            }
+        },
+        "entities.ElasticHit": {
+            "type": "object",
+            "properties": {
+                "actor_link": {
+                    "type": "string"
+                },
+                "actor_name": {
+                    "type": "string"
+                },
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "character_id": {
+                    "type": "integer"
+                },
+                "character_image_full": {
+                    "type": "string"
+                },
+                "character_image_thumb": {
+                    "type": "string"
+                },
+                "character_link": {
+                    "type": "string"
+                },
+                "character_name": {
+                    "type": "string"
+                },
+                "explanation": {
+                    "$ref": "#/definitions/entities.Explanation"
+                },
+                "highlights": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "house_name": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "killed": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "killed_by": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "married_engaged": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "nickname": {
+                    "type": "string"
+                },
+                "parents": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "royal": {
+                    "type": "boolean"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "siblings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.ElasticSearchResult": {
+            "type": "object",
+            "properties": {
+                "facets": {
+                    "$ref": "#/definitions/entities.ElasticFacets"
+                },
+                "hits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.ElasticHit"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entities.Explanation": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.Explanation"
+                    }
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "entities.FacetCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.SearchHit": {
+            "type": "object",
+            "properties": {
+                "actorLink": {
+                    "type": "string"
+                },
+                "actorName": {
+                    "type": "string"
+                },
+                "actors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.ActorEntry"
+                    }
+                },
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.Alias"
+                    }
+                },
+                "characterID": {
+                    "type": "integer"
+                },
+                "characterImageFull": {
+                    "type": "string"
+                },
+                "characterImageThumb": {
+                    "type": "string"
+                },
+                "characterLink": {
+                    "type": "string"
+                },
+                "characterName": {
+                    "type": "string"
+                },
+                "explanation": {
+                    "$ref": "#/definitions/entities.Explanation"
+                },
+                "extra": {
+                    "description": "Extra keeps the attributes of the imported dataset no field maps to,\nlike parentOf or serves.",
+                    "type": "object"
+                },
+                "highlights": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "houseName": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "killed": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "killedBy": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "marriedEngaged": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "nickname": {
+                    "type": "string"
+                },
+                "parents": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "royal": {
+                    "type": "boolean"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "siblings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.SearchResult": {
+            "type": "object",
+            "properties": {
+                "facets": {
+                    "$ref": "#/definitions/entities.ElasticFacets"
+                },
+                "hits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.SearchHit"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entities.SimilarCharacter": {
+            "type": "object",
+            "properties": {
+                "actorLink": {
                     "type": "string"
                 },
                 "actorName": {
                     "type": "string"
                 },
+                "actors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.ActorEntry"
+                    }
+                },
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.Alias"
+                    }
+                },
                 "characterID": {
                     "type": "integer"
                 },
@@ -315,6 +1462,10 @@ const docTemplate = `{
                 "characterName": {
                     "type": "string"
                 },
+                "extra": {
+                    "description": "Extra keeps the attributes of the imported dataset no field maps to,\nlike parentOf or serves.",
+                    "type": "object"
+                },
                 "houseName": {
                     "type": "array",
                     "items": {
@@ -348,14 +1499,40 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "royal": {
                     "type": "boolean"
                 },
+                "score": {
+                    "type": "number"
+                },
                 "siblings": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.Suggestion": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
                 }
             }
         }
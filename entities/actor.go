@@ -5,13 +5,60 @@ import (
 )
 
 type ActorEntry struct {
-	ActorName string `json:"actorName,omitempty" db:"actor_name"`
-	ActorLink string `json:"actorLink,omitempty" db:"actor_link"`
+	ActorID       int    `json:"actorID,omitempty" db:"actor_id"`
+	ActorName     string `json:"actorName,omitempty" db:"actor_name"`
+	ActorLink     string `json:"actorLink,omitempty" db:"actor_link"`
+	SeasonsActive []int  `json:"seasonsActive,omitempty" db:"seasons_active"`
+	Characters    int    `json:"characters,omitempty" db:"characters"`
+}
+
+// ActorRole is a character played by an actor and the seasons they
+// played it in.
+type ActorRole struct {
+	CharacterID   int      `json:"characterID" db:"character_id"`
+	CharacterName string   `json:"characterName" db:"character_name"`
+	Slug          string   `json:"slug" db:"slug"`
+	HouseName     []string `json:"houseName,omitempty" db:"house_name"`
+	SeasonsActive []int    `json:"seasonsActive,omitempty" db:"seasons_active"`
+}
+
+// ActorFilter narrows down actor listings. Zero values don't filter.
+type ActorFilter struct {
+	Name   string
+	House  string
+	Season int
 }
 
 //go:generate moq -out ./../mocks/actors_repository.go -pkg mocks . ActorsRepository
 type ActorsRepository interface {
 	Create(ctx context.Context, actorName string, actorLink string) (int, error)
 	GetActorID(ctx context.Context, actorName string) (int, error)
-	LinkActorToCharacter(ctx context.Context, actorId int, characterId int) error
+	LinkActorToCharacter(ctx context.Context, actorId int, characterId int, seasonsActive []int) error
+	Get(ctx context.Context, actorName string) (ActorEntry, error)
+	GetAll(ctx context.Context, filter ActorFilter, page int) ([]ActorEntry, error)
+	GetRoles(ctx context.Context, actorID int, season int, page int) ([]ActorRole, error)
+	GetOrphans(ctx context.Context) ([]ActorEntry, error)
+	DeleteOrphans(ctx context.Context, actorIDs ...int) ([]string, error)
+	FindDuplicates(ctx context.Context) ([][]ActorEntry, error)
+	Merge(ctx context.Context, keepID int, duplicateID int) error
+}
+
+// AllActors returns the character's actors with the single ActorName and
+// ActorLink fields folded in, as most of the dataset only uses those.
+func (c CharacterEntry) AllActors() []ActorEntry {
+	actors := make([]ActorEntry, 0, len(c.Actors)+1)
+	hasActor := c.ActorName == ""
+	for _, actor := range c.Actors {
+		if actor.ActorName == "" {
+			continue
+		}
+		if actor.ActorName == c.ActorName {
+			hasActor = true
+		}
+		actors = append(actors, actor)
+	}
+	if !hasActor {
+		actors = append(actors, ActorEntry{ActorName: c.ActorName, ActorLink: c.ActorLink})
+	}
+	return actors
 }
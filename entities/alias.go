@@ -0,0 +1,45 @@
+package entities
+
+type AliasType string
+
+const (
+	AliasNickname  AliasType = "nickname"
+	AliasTitle     AliasType = "title"
+	AliasBirthName AliasType = "birth_name"
+)
+
+func (t AliasType) Valid() bool {
+	switch t {
+	case AliasNickname, AliasTitle, AliasBirthName:
+		return true
+	}
+	return false
+}
+
+// Alias is an alternate name a character is known by,
+// e.g. "The Hound" (nickname) or "Khaleesi" (title).
+type Alias struct {
+	Name string    `json:"name" db:"alias"`
+	Type AliasType `json:"type,omitempty" db:"alias_type"`
+}
+
+// AllAliases returns the character's aliases with its nickname folded in,
+// so characters that only have the legacy nickname field are still found
+// by it.
+func (c CharacterEntry) AllAliases() []Alias {
+	aliases := make([]Alias, 0, len(c.Aliases)+1)
+	hasNickname := c.Nickname == ""
+	for _, alias := range c.Aliases {
+		if alias.Type == "" {
+			alias.Type = AliasNickname
+		}
+		if alias.Name == c.Nickname {
+			hasNickname = true
+		}
+		aliases = append(aliases, alias)
+	}
+	if !hasNickname {
+		aliases = append(aliases, Alias{Name: c.Nickname, Type: AliasNickname})
+	}
+	return aliases
+}
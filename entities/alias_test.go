@@ -0,0 +1,23 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllAliases(t *testing.T) {
+	character := CharacterEntry{
+		Nickname: "The Hound",
+		Aliases:  []Alias{{Name: "Dog"}},
+	}
+	assert.Equal(t, []Alias{
+		{Name: "Dog", Type: AliasNickname},
+		{Name: "The Hound", Type: AliasNickname},
+	}, character.AllAliases())
+
+	character.Aliases = []Alias{{Name: "The Hound", Type: AliasTitle}}
+	assert.Equal(t, []Alias{{Name: "The Hound", Type: AliasTitle}}, character.AllAliases())
+
+	assert.Empty(t, CharacterEntry{}.AllAliases())
+}
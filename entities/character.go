@@ -11,19 +11,25 @@ type HouseNameType []string
 type CharacterEntry struct {
 	CharacterID         int           `json:"characterID,omitempty" db:"character_id"`
 	CharacterName       string        `json:"characterName" db:"character_name"`
+	Slug                string        `json:"slug,omitempty" db:"slug"`
 	HouseName           HouseNameType `json:"houseName,omitempty" db:"house_name"`
 	CharacterImageThumb string        `json:"characterImageThumb,omitempty" db:"character_image_thumb"`
 	CharacterImageFull  string        `json:"characterImageFull,omitempty" db:"character_image_full"`
 	CharacterLink       string        `json:"characterLink,omitempty" db:"character_link"`
 	ActorName           string        `json:"actorName,omitempty" db:"actor_name"`
 	ActorLink           string        `json:"actorLink,omitempty" db:"actor_link"`
+	Actors              []ActorEntry  `json:"actors,omitempty" db:"actors"`
 	Nickname            string        `json:"nickname,omitempty" db:"nickname"`
+	Aliases             []Alias       `json:"aliases,omitempty" db:"aliases"`
 	Royal               bool          `json:"royal,omitempty" db:"royal"`
 	Parents             []string      `json:"parents,omitempty" db:"parents"`
 	Siblings            []string      `json:"siblings,omitempty" db:"siblings"`
 	KilledBy            []string      `json:"killedBy,omitempty" db:"killed_by"`
 	Killed              []string      `json:"killed,omitempty" db:"killed"`
 	MarriedEngaged      []string      `json:"marriedEngaged,omitempty" db:"married_engaged"`
+	// Extra keeps the attributes of the imported dataset no field maps to,
+	// like parentOf or serves.
+	Extra map[string]json.RawMessage `json:"extra,omitempty" db:"extra" swaggertype:"object"`
 }
 
 func (h *HouseNameType) UnmarshalJSON(data []byte) error {
@@ -42,30 +48,22 @@ func (h *HouseNameType) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("cannot unmarshal %s into HouseNameType", string(data))
 }
 
-type CharacterEntryElastic struct {
-	CharacterID         int           `json:"character_id,omitempty"`
-	CharacterName       string        `json:"character_name"`
-	HouseName           HouseNameType `json:"house_name,omitempty"`
-	CharacterImageThumb string        `json:"character_image_thumb,omitempty"`
-	CharacterImageFull  string        `json:"character_image_full,omitempty"`
-	CharacterLink       string        `json:"character_link,omitempty"`
-	ActorName           string        `json:"actor_name,omitempty"`
-	ActorLink           string        `json:"actor_link,omitempty"`
-	Nickname            string        `json:"nickname,omitempty"`
-	Royal               bool          `json:"royal,omitempty"`
-	Parents             []string      `json:"parents,omitempty"`
-	Siblings            []string      `json:"siblings,omitempty"`
-	KilledBy            []string      `json:"killed_by,omitempty"`
-	Killed              []string      `json:"killed,omitempty"`
-	MarriedEngaged      []string      `json:"married_engaged,omitempty"`
+// CharacterRef identifies a character by its immutable ID and current slug.
+type CharacterRef struct {
+	ID   int
+	Slug string
 }
 
 //go:generate moq -out ./../mocks/characters_repository.go -pkg mocks . CharactersRepository
 type CharactersRepository interface {
-	UpdateCharacterAndActor(ctx context.Context, characterEntryEntry *CharacterEntry, characterName string) (int, error)
-	Delete(ctx context.Context, name string) error
-	Get(ctx context.Context, name string) ([]CharacterEntry, error)
+	UpdateCharacterAndActor(ctx context.Context, characterEntryEntry *CharacterEntry, characterID int) (int, error)
+	Delete(ctx context.Context, characterID int) error
+	Get(ctx context.Context, characterID int) ([]CharacterEntry, error)
+	GetByIDs(ctx context.Context, characterIDs []int) ([]CharacterEntry, error)
+	Resolve(ctx context.Context, key string) (CharacterRef, error)
+	ResolveSlug(ctx context.Context, slug string) (CharacterRef, error)
 	GetAll(ctx context.Context, page int) ([]CharacterEntry, error)
+	GetAfter(ctx context.Context, afterID int, limit int) ([]CharacterEntry, error)
 	GetCharacterID(ctx context.Context, characterName string) (int, error)
 	CreateCharacter(ctx context.Context, characterEntryEntry *CharacterEntry, houseNames string) (int, error)
 	CreateCharacterAndActor(ctx context.Context, characterEntry *CharacterEntry) error
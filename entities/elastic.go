@@ -0,0 +1,145 @@
+package entities
+
+import (
+	"sort"
+	"strings"
+)
+
+// This file is the one mapping layer between the documents of the
+// character_details index and CharacterEntry.
+
+// CharacterEntryElastic is a document of the character_details index.
+type CharacterEntryElastic struct {
+	CharacterID         int           `json:"character_id,omitempty"`
+	CharacterName       string        `json:"character_name"`
+	Slug                string        `json:"slug,omitempty"`
+	HouseName           HouseNameType `json:"house_name,omitempty"`
+	CharacterImageThumb string        `json:"character_image_thumb,omitempty"`
+	CharacterImageFull  string        `json:"character_image_full,omitempty"`
+	CharacterLink       string        `json:"character_link,omitempty"`
+	ActorName           string        `json:"actor_name,omitempty"`
+	ActorLink           string        `json:"actor_link,omitempty"`
+	Nickname            string        `json:"nickname,omitempty"`
+	Aliases             []string      `json:"aliases,omitempty"`
+	Royal               bool          `json:"royal"`
+	Parents             []string      `json:"parents,omitempty"`
+	Siblings            []string      `json:"siblings,omitempty"`
+	KilledBy            []string      `json:"killed_by,omitempty"`
+	Killed              []string      `json:"killed,omitempty"`
+	MarriedEngaged      []string      `json:"married_engaged,omitempty"`
+}
+
+// CharacterEntry converts a document from the character_details index.
+func (e CharacterEntryElastic) CharacterEntry() CharacterEntry {
+	aliases := make([]Alias, len(e.Aliases))
+	for i, alias := range e.Aliases {
+		aliases[i] = Alias{Name: alias}
+	}
+	return CharacterEntry{
+		CharacterID:         e.CharacterID,
+		CharacterName:       e.CharacterName,
+		Slug:                e.Slug,
+		HouseName:           e.HouseName,
+		CharacterImageThumb: e.CharacterImageThumb,
+		CharacterImageFull:  e.CharacterImageFull,
+		CharacterLink:       e.CharacterLink,
+		ActorName:           e.ActorName,
+		ActorLink:           e.ActorLink,
+		Nickname:            e.Nickname,
+		Aliases:             aliases,
+		Royal:               e.Royal,
+		Parents:             e.Parents,
+		Siblings:            e.Siblings,
+		KilledBy:            e.KilledBy,
+		Killed:              e.Killed,
+		MarriedEngaged:      e.MarriedEngaged,
+	}
+}
+
+// CharacterEntryElastic converts the character to a document of the
+// character_details index.
+func (c CharacterEntry) CharacterEntryElastic() CharacterEntryElastic {
+	aliases := make([]string, 0, len(c.Aliases))
+	for _, alias := range c.AllAliases() {
+		aliases = append(aliases, alias.Name)
+	}
+	return CharacterEntryElastic{
+		CharacterID:         c.CharacterID,
+		CharacterName:       c.CharacterName,
+		Slug:                c.Slug,
+		HouseName:           c.HouseName,
+		CharacterImageThumb: c.CharacterImageThumb,
+		CharacterImageFull:  c.CharacterImageFull,
+		CharacterLink:       c.CharacterLink,
+		ActorName:           c.ActorName,
+		ActorLink:           c.ActorLink,
+		Nickname:            c.Nickname,
+		Aliases:             aliases,
+		Royal:               c.Royal,
+		Parents:             c.Parents,
+		Siblings:            c.Siblings,
+		KilledBy:            c.KilledBy,
+		Killed:              c.Killed,
+		MarriedEngaged:      c.MarriedEngaged,
+	}
+}
+
+// elasticFields maps the fields of index documents to the ones of
+// CharacterEntry.
+var elasticFields = map[string]string{
+	"character_id":          "characterID",
+	"character_name":        "characterName",
+	"slug":                  "slug",
+	"house_name":            "houseName",
+	"character_image_thumb": "characterImageThumb",
+	"character_image_full":  "characterImageFull",
+	"character_link":        "characterLink",
+	"actor_name":            "actorName",
+	"actor_link":            "actorLink",
+	"nickname":              "nickname",
+	"aliases":               "aliases",
+	"royal":                 "royal",
+	"parents":               "parents",
+	"siblings":              "siblings",
+	"killed_by":             "killedBy",
+	"killed":                "killed",
+	"married_engaged":       "marriedEngaged",
+}
+
+// SearchHit converts a hit, renaming the highlighted fields like the
+// fields of CharacterEntry, e.g. character_name.ngram to characterName.
+func (h ElasticHit) SearchHit() SearchHit {
+	var highlights map[string][]string
+	if h.Highlights != nil {
+		highlights = make(map[string][]string, len(h.Highlights))
+		// sorted, so the fragments of a field come before the ones of its
+		// subfields
+		fields := make([]string, 0, len(h.Highlights))
+		for field := range h.Highlights {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			name, _, _ := strings.Cut(field, ".")
+			if entityField, ok := elasticFields[name]; ok {
+				name = entityField
+			}
+			highlights[name] = append(highlights[name], h.Highlights[field]...)
+		}
+	}
+	return SearchHit{
+		CharacterEntry: h.CharacterEntry(),
+		Score:          h.Score,
+		Highlights:     highlights,
+		Explanation:    h.Explanation,
+	}
+}
+
+// SearchResult converts the result to the shape /characters answers in.
+func (r ElasticSearchResult) SearchResult() SearchResult {
+	hits := make([]SearchHit, len(r.Hits))
+	for i, hit := range r.Hits {
+		hits[i] = hit.SearchHit()
+	}
+	return SearchResult{Total: r.Total, Hits: hits, Facets: r.Facets}
+}
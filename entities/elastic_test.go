@@ -0,0 +1,44 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCharacterEntryElasticRoundTrip(t *testing.T) {
+	character := CharacterEntry{
+		CharacterID:   1,
+		CharacterName: "Sandor Clegane",
+		Slug:          "sandor-clegane",
+		HouseName:     HouseNameType{"House Clegane"},
+		ActorName:     "Rory McCann",
+		Nickname:      "The Hound",
+		Aliases:       []Alias{{Name: "The Hound"}},
+		Siblings:      []string{"Gregor Clegane"},
+		Killed:        []string{"Polliver"},
+	}
+
+	assert.Equal(t, character, character.CharacterEntryElastic().CharacterEntry())
+}
+
+func TestSearchHit(t *testing.T) {
+	hit := ElasticHit{
+		CharacterEntryElastic: CharacterEntryElastic{CharacterName: "Arya Stark", KilledBy: nil},
+		Score:                 1.5,
+		Highlights: map[string][]string{
+			"character_name":       {"<em>Arya</em> Stark"},
+			"character_name.ngram": {"<em>Ary</em>a Stark"},
+			"actor_name":           {"<em>Maisie</em> Williams"},
+		},
+	}
+
+	assert.Equal(t, SearchHit{
+		CharacterEntry: CharacterEntry{CharacterName: "Arya Stark", Aliases: []Alias{}},
+		Score:          1.5,
+		Highlights: map[string][]string{
+			"characterName": {"<em>Arya</em> Stark", "<em>Ary</em>a Stark"},
+			"actorName":     {"<em>Maisie</em> Williams"},
+		},
+	}, hit.SearchHit())
+}
@@ -0,0 +1,71 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error kinds shared by every layer. Repositories and services wrap their
+// failures with one of these so that callers can branch on errors.Is
+// instead of parsing driver messages.
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrConflict    = errors.New("conflict")
+	ErrValidation  = errors.New("validation failed")
+	ErrUnavailable = errors.New("service unavailable")
+	ErrBadGateway  = errors.New("bad gateway")
+	// ErrCanceled is a caller that gave up or ran out of time before the
+	// answer came.
+	ErrCanceled = errors.New("request canceled")
+)
+
+// DomainError pairs an error kind with a detail message that is safe to
+// show to API clients. Cause, if any, is the underlying error, which may
+// name internal hosts and is only logged.
+type DomainError struct {
+	Kind   error
+	Detail string
+	Cause  error
+}
+
+func (e *DomainError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("%v: %s: %v", e.Kind, e.Detail, e.Cause)
+	}
+	return fmt.Sprintf("%v: %s", e.Kind, e.Detail)
+}
+
+func (e *DomainError) Unwrap() []error {
+	if e.Cause != nil {
+		return []error{e.Kind, e.Cause}
+	}
+	return []error{e.Kind}
+}
+
+// NewDomainError reports the cause as an error of the kind with a fixed
+// detail, keeping the text of the cause out of API responses.
+func NewDomainError(kind error, cause error, detail string) error {
+	return &DomainError{Kind: kind, Detail: detail, Cause: cause}
+}
+
+func NewNotFoundError(format string, args ...any) error {
+	return &DomainError{Kind: ErrNotFound, Detail: fmt.Sprintf(format, args...)}
+}
+
+func NewConflictError(format string, args ...any) error {
+	return &DomainError{Kind: ErrConflict, Detail: fmt.Sprintf(format, args...)}
+}
+
+func NewValidationError(format string, args ...any) error {
+	return &DomainError{Kind: ErrValidation, Detail: fmt.Sprintf(format, args...)}
+}
+
+func NewUnavailableError(format string, args ...any) error {
+	return &DomainError{Kind: ErrUnavailable, Detail: fmt.Sprintf(format, args...)}
+}
+
+// NewBadGatewayError reports a service that answered with an error or an
+// answer that makes no sense, as opposed to not answering at all.
+func NewBadGatewayError(format string, args ...any) error {
+	return &DomainError{Kind: ErrBadGateway, Detail: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,32 @@
+package entities
+
+import (
+	"context"
+)
+
+type OutboxOperation string
+
+const (
+	OutboxUpsert OutboxOperation = "upsert"
+	OutboxDelete OutboxOperation = "delete"
+)
+
+// OutboxEvent records that a character was written and the search index
+// needs to catch up. Repositories add them in the same transaction as the
+// write itself.
+type OutboxEvent struct {
+	ID          int64           `json:"id" db:"outbox_id"`
+	CharacterID int             `json:"characterID" db:"character_id"`
+	Operation   OutboxOperation `json:"operation" db:"operation"`
+	Attempts    int             `json:"attempts" db:"attempts"`
+}
+
+//go:generate moq -out ./../mocks/search_outbox.go -pkg mocks . SearchOutbox
+type SearchOutbox interface {
+	// Pending returns the oldest events tried fewer than maxAttempts times.
+	Pending(ctx context.Context, limit int, maxAttempts int) ([]OutboxEvent, error)
+	// Done removes delivered events.
+	Done(ctx context.Context, eventIDs []int64) error
+	// Failed counts a failed delivery of the events.
+	Failed(ctx context.Context, eventIDs []int64, reason string) error
+}
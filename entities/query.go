@@ -0,0 +1,278 @@
+package entities
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// QueryField is a field a structured query can filter on, as in
+// house:Stark.
+type QueryField string
+
+const (
+	FieldName   QueryField = "name"
+	FieldAlias  QueryField = "alias"
+	FieldHouse  QueryField = "house"
+	FieldActor  QueryField = "actor"
+	FieldRoyal  QueryField = "royal"
+	FieldAlive  QueryField = "alive"
+	FieldKilled QueryField = "killed"
+)
+
+type fieldKind int
+
+const (
+	textField fieldKind = iota
+	boolField
+	countField
+)
+
+var queryFields = map[QueryField]fieldKind{
+	FieldName:   textField,
+	FieldAlias:  textField,
+	FieldHouse:  textField,
+	FieldActor:  textField,
+	FieldRoyal:  boolField,
+	FieldAlive:  boolField,
+	FieldKilled: countField,
+}
+
+// QueryOp compares a field to a value. Text fields contain the value as
+// whole words, the others equal it unless a comparison is given.
+type QueryOp string
+
+const (
+	OpMatch        QueryOp = ":"
+	OpGreater      QueryOp = ">"
+	OpGreaterEqual QueryOp = ">="
+	OpLess         QueryOp = "<"
+	OpLessEqual    QueryOp = "<="
+)
+
+// QueryNode is a node of a parsed structured query: a TextNode, FieldNode,
+// NotNode or AndNode.
+type QueryNode interface {
+	queryNode()
+}
+
+// TextNode is free text, matched against names, aliases, actors and
+// houses like a plain search term.
+type TextNode struct {
+	Text string
+}
+
+// FieldNode is a condition on one field. Number is set for killed, Bool
+// for royal and alive.
+type FieldNode struct {
+	Field  QueryField
+	Op     QueryOp
+	Value  string
+	Number int
+	Bool   bool
+}
+
+// NotNode excludes the characters matching Node, as in -house:Lannister.
+type NotNode struct {
+	Node QueryNode
+}
+
+// AndNode matches the characters matching all of Nodes.
+type AndNode struct {
+	Nodes []QueryNode
+}
+
+func (TextNode) queryNode()  {}
+func (FieldNode) queryNode() {}
+func (NotNode) queryNode()   {}
+func (AndNode) queryNode()   {}
+
+// QueryText is the free text of a query, what results are ranked by.
+// Negated text is left out.
+func QueryText(node QueryNode) string {
+	switch n := node.(type) {
+	case TextNode:
+		return n.Text
+	case AndNode:
+		var texts []string
+		for _, child := range n.Nodes {
+			if text := QueryText(child); text != "" {
+				texts = append(texts, text)
+			}
+		}
+		return strings.Join(texts, " ")
+	default:
+		return ""
+	}
+}
+
+// QueryParseError tells where and why a structured query is invalid.
+// Position counts characters from 1.
+type QueryParseError struct {
+	Position int
+	Message  string
+}
+
+func (e *QueryParseError) Error() string {
+	return fmt.Sprintf("invalid query at position %d: %s", e.Position, e.Message)
+}
+
+// ParseQuery parses a structured query such as
+//
+//	house:Stark royal:true killed:>3 actor:"Kit Harington" -alias:Bastard snow
+//
+// into an AndNode of its clauses. Words without a field are free text,
+// quotes keep words together and a leading "-" negates a clause.
+func ParseQuery(query string) (QueryNode, error) {
+	p := &queryParser{input: []rune(query)}
+	var nodes []QueryNode
+	for {
+		p.skipSpaces()
+		if p.done() {
+			break
+		}
+		node, err := p.clause()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 0 {
+		return nil, &QueryParseError{Position: 1, Message: "query is empty"}
+	}
+	return AndNode{Nodes: nodes}, nil
+}
+
+type queryParser struct {
+	input []rune
+	pos   int
+}
+
+func (p *queryParser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *queryParser) skipSpaces() {
+	for !p.done() && unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *queryParser) errorAt(pos int, format string, args ...any) error {
+	return &QueryParseError{Position: pos + 1, Message: fmt.Sprintf(format, args...)}
+}
+
+// clause parses [-](field:[op]value | value). A lone "-" and a word with a
+// colon that doesn't follow a field name, as in "Ser:" or "12:30", are
+// plain text.
+func (p *queryParser) clause() (QueryNode, error) {
+	if p.input[p.pos] == '-' && p.pos+1 < len(p.input) && !unicode.IsSpace(p.input[p.pos+1]) {
+		p.pos++
+		node, err := p.clause()
+		if err != nil {
+			return nil, err
+		}
+		return NotNode{Node: node}, nil
+	}
+
+	start := p.pos
+	if p.input[p.pos] == '"' {
+		text, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+		return TextNode{Text: text}, nil
+	}
+
+	word := p.word()
+	if p.done() || p.input[p.pos] != ':' {
+		return TextNode{Text: word}, nil
+	}
+	field := QueryField(strings.ToLower(word))
+	kind, ok := queryFields[field]
+	if !ok {
+		for !p.done() && !unicode.IsSpace(p.input[p.pos]) {
+			p.pos++
+		}
+		return TextNode{Text: string(p.input[start:p.pos])}, nil
+	}
+	p.pos++
+	return p.condition(field, kind)
+}
+
+// condition parses the [op]value after field:.
+func (p *queryParser) condition(field QueryField, kind fieldKind) (QueryNode, error) {
+	opStart := p.pos
+	op := OpMatch
+	for _, candidate := range []QueryOp{OpGreaterEqual, OpLessEqual, OpGreater, OpLess} {
+		if strings.HasPrefix(string(p.input[p.pos:]), string(candidate)) {
+			op = candidate
+			p.pos += len(candidate)
+			break
+		}
+	}
+	if op != OpMatch && kind != countField {
+		return nil, p.errorAt(opStart, "%s can't be compared with %s, only %s can", field, op, FieldKilled)
+	}
+
+	valueStart := p.pos
+	if p.done() || unicode.IsSpace(p.input[p.pos]) {
+		return nil, p.errorAt(valueStart, "%s needs a value", field)
+	}
+	var value string
+	if p.input[p.pos] == '"' {
+		var err error
+		if value, err = p.quoted(); err != nil {
+			return nil, err
+		}
+	} else {
+		value = p.word()
+	}
+	if strings.TrimSpace(value) == "" {
+		return nil, p.errorAt(valueStart, "%s needs a value", field)
+	}
+
+	node := FieldNode{Field: field, Op: op, Value: value}
+	switch kind {
+	case boolField:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, p.errorAt(valueStart, "%s must be true or false, not %q", field, value)
+		}
+		node.Bool = b
+	case countField:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return nil, p.errorAt(valueStart, "%s must be a number, not %q", field, value)
+		}
+		node.Number = n
+	}
+	return node, nil
+}
+
+// word reads up to the next space, or the colon after a field name.
+func (p *queryParser) word() string {
+	start := p.pos
+	for !p.done() && !unicode.IsSpace(p.input[p.pos]) {
+		if p.input[p.pos] == ':' && p.pos > start {
+			break
+		}
+		p.pos++
+	}
+	return string(p.input[start:p.pos])
+}
+
+// quoted reads a "quoted value" without the quotes.
+func (p *queryParser) quoted() (string, error) {
+	start := p.pos
+	p.pos++
+	for !p.done() && p.input[p.pos] != '"' {
+		p.pos++
+	}
+	if p.done() {
+		return "", p.errorAt(start, "missing closing quote")
+	}
+	p.pos++
+	return string(p.input[start+1 : p.pos-1]), nil
+}
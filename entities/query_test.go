@@ -0,0 +1,61 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuery(t *testing.T) {
+	node, err := ParseQuery(`house:Stark royal:true killed:>3 actor:"Kit Harington" -alias:Bastard jon snow`)
+	assert.NoError(t, err)
+	assert.Equal(t, AndNode{Nodes: []QueryNode{
+		FieldNode{Field: FieldHouse, Op: OpMatch, Value: "Stark"},
+		FieldNode{Field: FieldRoyal, Op: OpMatch, Value: "true", Bool: true},
+		FieldNode{Field: FieldKilled, Op: OpGreater, Value: "3", Number: 3},
+		FieldNode{Field: FieldActor, Op: OpMatch, Value: "Kit Harington"},
+		NotNode{Node: FieldNode{Field: FieldAlias, Op: OpMatch, Value: "Bastard"}},
+		TextNode{Text: "jon"},
+		TextNode{Text: "snow"},
+	}}, node)
+	assert.Equal(t, "jon snow", QueryText(node))
+
+	node, err = ParseQuery(`"Jaqen H'ghar" killed:<=2 ALIVE:false -snow`)
+	assert.NoError(t, err)
+	assert.Equal(t, AndNode{Nodes: []QueryNode{
+		TextNode{Text: "Jaqen H'ghar"},
+		FieldNode{Field: FieldKilled, Op: OpLessEqual, Value: "2", Number: 2},
+		FieldNode{Field: FieldAlive, Op: OpMatch, Value: "false"},
+		NotNode{Node: TextNode{Text: "snow"}},
+	}}, node)
+	assert.Equal(t, "Jaqen H'ghar", QueryText(node))
+
+	// colons after anything but a field, and a lone "-", are text
+	node, err = ParseQuery(`Ser: hous:Stark 12:30 - -house:Stark`)
+	assert.NoError(t, err)
+	assert.Equal(t, AndNode{Nodes: []QueryNode{
+		TextNode{Text: "Ser:"},
+		TextNode{Text: "hous:Stark"},
+		TextNode{Text: "12:30"},
+		TextNode{Text: "-"},
+		NotNode{Node: FieldNode{Field: FieldHouse, Op: OpMatch, Value: "Stark"}},
+	}}, node)
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	cases := map[string]string{
+		"   ":                  "invalid query at position 1: query is empty",
+		"snow house:":          "invalid query at position 12: house needs a value",
+		`actor:"Kit Harington`: "invalid query at position 7: missing closing quote",
+		"royal:maybe":          `invalid query at position 7: royal must be true or false, not "maybe"`,
+		"killed:>three":        `invalid query at position 9: killed must be a number, not "three"`,
+		"house:>Stark":         "invalid query at position 7: house can't be compared with >, only killed can",
+	}
+	for query, message := range cases {
+		_, err := ParseQuery(query)
+		var parseErr *QueryParseError
+		if assert.ErrorAs(t, err, &parseErr, query) {
+			assert.Equal(t, message, err.Error(), query)
+		}
+	}
+}
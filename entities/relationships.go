@@ -11,5 +11,15 @@ type RelationshipsRepository interface {
 	AddParent(ctx context.Context, characterID int, characterParentId int) error
 	AddSibling(ctx context.Context, characterID int, characterSiblingId int) error
 	AddKilled(ctx context.Context, characterID int, characterKilledId int) error
+	AddKilledBy(ctx context.Context, characterID int, characterKillerId int) error
 	AddMarriedEngaged(ctx context.Context, characterID int, characterMarriedEngagedId int) error
 }
+
+// CharacterTransactor runs fn with repositories that share one transaction,
+// committed when fn returns nil, so a character is never left written
+// without its relationships.
+//
+//go:generate moq -out ./../mocks/character_transactor.go -pkg mocks . CharacterTransactor
+type CharacterTransactor interface {
+	InTx(ctx context.Context, fn func(characters CharactersRepository, relationships RelationshipsRepository) error) error
+}
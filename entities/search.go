@@ -0,0 +1,162 @@
+package entities
+
+import (
+	"context"
+)
+
+// SearchQuery is a full text search over characters. Query is the parsed
+// Term when it is a structured query, see ParseQuery.
+type SearchQuery struct {
+	Term  string
+	Page  int
+	Query QueryNode
+}
+
+// Node is the query to run, the plain Term when it wasn't parsed.
+func (q SearchQuery) Node() QueryNode {
+	if q.Query == nil {
+		return TextNode{Text: q.Term}
+	}
+	return q.Query
+}
+
+// Searcher finds characters by name, alias, actor or house. Elasticsearch
+// and Postgres both implement it, see SEARCH_BACKEND.
+//
+//go:generate moq -out ./../mocks/searcher.go -pkg mocks . Searcher
+type Searcher interface {
+	Search(ctx context.Context, query SearchQuery) ([]CharacterEntry, error)
+}
+
+// CharacterListener is told about every character the API wrote, e.g. to
+// keep an in-memory search index up to date.
+//
+//go:generate moq -out ./../mocks/character_listener.go -pkg mocks . CharacterListener
+type CharacterListener interface {
+	CharacterSaved(ctx context.Context, characterID int) error
+	CharacterDeleted(ctx context.Context, characterID int) error
+}
+
+// Sort orders of ElasticSearchQuery.
+const (
+	SortRelevance = "relevance"
+	SortName      = "name"
+	SortNameDesc  = "-name"
+)
+
+// FacetedSearcher runs faceted searches of the character_details index.
+//
+//go:generate moq -out ./../mocks/faceted_searcher.go -pkg mocks . FacetedSearcher
+type FacetedSearcher interface {
+	Query(ctx context.Context, query ElasticSearchQuery) (ElasticSearchResult, error)
+}
+
+// ElasticSearchQuery is a faceted search of the character_details index.
+// Nil filters don't filter.
+type ElasticSearchQuery struct {
+	Term string
+	// Query, when set, is matched instead of Term.
+	Query QueryNode
+	House string
+	Royal *bool
+	Alive *bool
+	From  int
+	Size  int
+	Sort  string
+	// Highlight marks the matched fragments of every hit.
+	Highlight bool
+	// Explain adds how each hit was scored.
+	Explain bool
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+type ElasticFacets struct {
+	House []FacetCount `json:"house"`
+	Royal []FacetCount `json:"royal"`
+}
+
+// Explanation is how Elasticsearch computed a score, broken down into the
+// scores it is made of.
+type Explanation struct {
+	Value       float64       `json:"value"`
+	Description string        `json:"description"`
+	Details     []Explanation `json:"details,omitempty"`
+}
+
+// ElasticHit is a matching document with its score, the matched fragments
+// by field, e.g. "<em>Arya</em> Stark" for "Aria", and with Explain, the
+// breakdown of its score.
+type ElasticHit struct {
+	CharacterEntryElastic
+	Score       float64             `json:"score"`
+	Highlights  map[string][]string `json:"highlights,omitempty"`
+	Explanation *Explanation        `json:"explanation,omitempty"`
+}
+
+// ElasticSearchResult is a page of hits with the total number of matches
+// and facet counts over all of them.
+type ElasticSearchResult struct {
+	Total  int           `json:"total"`
+	Hits   []ElasticHit  `json:"hits"`
+	Facets ElasticFacets `json:"facets"`
+}
+
+// SearchHit is a matching character in the same shape as /characters
+// answers with, plus its score, the matched fragments by field and with
+// Explain, the breakdown of its score.
+type SearchHit struct {
+	CharacterEntry
+	Score       float64             `json:"score"`
+	Highlights  map[string][]string `json:"highlights,omitempty"`
+	Explanation *Explanation        `json:"explanation,omitempty"`
+}
+
+// SearchResult is ElasticSearchResult with the hits as SearchHit.
+type SearchResult struct {
+	Total  int           `json:"total"`
+	Hits   []SearchHit   `json:"hits"`
+	Facets ElasticFacets `json:"facets"`
+}
+
+// Types of a Suggestion.
+const (
+	SuggestionCharacter = "character"
+	SuggestionAlias     = "alias"
+	SuggestionActor     = "actor"
+	SuggestionHouse     = "house"
+)
+
+// Suggestion is a name completing what the user typed so far. Characters
+// and aliases carry the slug of their character, actors and houses the
+// slug of their name.
+type Suggestion struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Slug string `json:"slug"`
+}
+
+// Suggester completes names as they are typed, best first.
+//
+//go:generate moq -out ./../mocks/suggester.go -pkg mocks . Suggester
+type Suggester interface {
+	Suggest(ctx context.Context, prefix string, size int) ([]Suggestion, error)
+}
+
+// SimilarCharacter is a character like another one, with its score and,
+// when the backend can tell, the reasons, e.g. "shares House Stark".
+type SimilarCharacter struct {
+	CharacterEntry
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// SimilarFinder finds the characters most like a character, best first.
+//
+//go:generate moq -out ./../mocks/similar_finder.go -pkg mocks . SimilarFinder
+type SimilarFinder interface {
+	Similar(ctx context.Context, characterID int, size int) ([]SimilarCharacter, error)
+}
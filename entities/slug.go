@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+var stripMarks = transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+// Slugify turns a display name into a URL-safe slug,
+// e.g. "Jaqen H'ghar" becomes "jaqen-hghar".
+// Keep in sync with the backfill in migrations/20241221120000_character_slugs.sql.
+func Slugify(name string) string {
+	folded, _, err := transform.String(stripMarks, name)
+	if err != nil {
+		folded = name
+	}
+
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(folded) {
+		switch {
+		case r == '\'' || r == '’':
+			continue
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		default:
+			dash = true
+		}
+	}
+	return b.String()
+}
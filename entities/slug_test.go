@@ -0,0 +1,23 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugify(t *testing.T) {
+	cases := map[string]string{
+		"Jon Snow":               "jon-snow",
+		"Jaqen H'ghar":           "jaqen-hghar",
+		"Aerys II Targaryen":     "aerys-ii-targaryen",
+		"Musician #1":            "musician-1",
+		"Three-Eyed Raven":       "three-eyed-raven",
+		"  Daenerys  Stormborn ": "daenerys-stormborn",
+		"Jôn Snöw+%":             "jon-snow",
+		"###":                    "",
+	}
+	for name, want := range cases {
+		assert.Equal(t, want, Slugify(name), name)
+	}
+}
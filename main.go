@@ -1,12 +1,20 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"os"
+
 	"github.com/vitalii-komenda/got/controllers"
+	"github.com/vitalii-komenda/got/entities"
 	"github.com/vitalii-komenda/got/postgres"
+	"github.com/vitalii-komenda/got/services"
+	"github.com/vitalii-komenda/got/utils"
 )
 
 type AllControllers struct {
 	CharactersController controllers.CharactersController
+	ActorsController     controllers.ActorsController
 	SearchController     controllers.SearchController
 }
 
@@ -19,14 +27,61 @@ func main() {
 	actorsRepo := postgres.NewActorsRepository(db)
 	characterRepo := postgres.NewCharacterRepository(db, actorsRepo)
 	relationshipsRepo := postgres.NewRelationshipsRepository(db, characterRepo)
-	charactersController := controllers.NewCharactersController(characterRepo, relationshipsRepo)
-	searchController := controllers.NewSearchController(characterRepo)
+	// one client for all elastic searches, so they share the circuit breaker
+	elasticSearcher := services.NewElasticSearcher(os.Getenv("ELASTICSEARCH_HOST"))
+	searcher, listeners := newSearcher(characterRepo, elasticSearcher)
+	transactor := postgres.NewTransactor(db, characterRepo, relationshipsRepo)
+	charactersController := controllers.NewCharactersController(characterRepo, transactor, listeners...)
+	actorsController := controllers.NewActorsController(actorsRepo)
+	searchController := controllers.NewSearchController(characterRepo, searcher, newSuggester(characterRepo, elasticSearcher), elasticSearcher, newSimilarFinder(characterRepo, elasticSearcher))
 
 	allControllers := AllControllers{
 		CharactersController: *charactersController,
+		ActorsController:     *actorsController,
 		SearchController:     *searchController,
 	}
 
 	r := setupRouter(allControllers)
 	r.Run(":8080")
 }
+
+// newSearcher picks the search backend from SEARCH_BACKEND: "postgres", the
+// default, "elastic", which needs ELASTICSEARCH_HOST, or "memory", an
+// in-memory index that has to hear about character writes.
+func newSearcher(characterRepo *postgres.CharactersRepository, elasticSearcher *services.ElasticSearcher) (entities.Searcher, []entities.CharacterListener) {
+	switch backend := os.Getenv("SEARCH_BACKEND"); backend {
+	case "", "postgres":
+		return characterRepo, nil
+	case "elastic":
+		utils.MustGetEnvOrPanic("ELASTICSEARCH_HOST")
+		return elasticSearcher, nil
+	case "memory":
+		index := services.NewMemoryIndex(characterRepo)
+		if err := index.Build(context.Background()); err != nil {
+			panic(fmt.Errorf("error building the search index: %w", err))
+		}
+		return index, []entities.CharacterListener{index}
+	default:
+		panic(fmt.Errorf("unknown SEARCH_BACKEND %q, use postgres, elastic or memory", backend))
+	}
+}
+
+// newSuggester completes names from Elasticsearch when SEARCH_BACKEND is
+// "elastic", falling back to Postgres when it is unavailable, and from
+// Postgres otherwise.
+func newSuggester(characterRepo *postgres.CharactersRepository, elasticSearcher *services.ElasticSearcher) entities.Suggester {
+	if os.Getenv("SEARCH_BACKEND") == "elastic" {
+		return services.NewFallbackSuggester(elasticSearcher, characterRepo)
+	}
+	return characterRepo
+}
+
+// newSimilarFinder scores similar characters with Elasticsearch when
+// SEARCH_BACKEND is "elastic", falling back to Postgres when it is
+// unavailable, and with Postgres otherwise.
+func newSimilarFinder(characterRepo *postgres.CharactersRepository, elasticSearcher *services.ElasticSearcher) entities.SimilarFinder {
+	if os.Getenv("SEARCH_BACKEND") == "elastic" {
+		return services.NewFallbackSimilarFinder(elasticSearcher, characterRepo)
+	}
+	return characterRepo
+}
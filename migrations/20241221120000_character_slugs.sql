@@ -0,0 +1,51 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+ALTER TABLE characters ADD COLUMN slug VARCHAR(255);
+
+-- The same slugs entities.Slugify and uniqueSlug give: accents stripped,
+-- "character" for names without letters or digits, and -2, -3... in ID
+-- order on collisions.
+DO $$
+DECLARE
+    c RECORD;
+    base TEXT;
+    candidate TEXT;
+    n INT;
+BEGIN
+    FOR c IN SELECT character_id, character_name FROM characters ORDER BY character_id LOOP
+        base := trim(both '-' FROM regexp_replace(
+            lower(unaccent(regexp_replace(c.character_name, '[''’]', '', 'g'))),
+            '[^a-z0-9]+', '-', 'g'
+        ));
+        IF base = '' THEN
+            base := 'character';
+        END IF;
+
+        candidate := base;
+        n := 1;
+        WHILE EXISTS (SELECT 1 FROM characters WHERE slug = candidate) LOOP
+            n := n + 1;
+            candidate := base || '-' || n;
+        END LOOP;
+        UPDATE characters SET slug = candidate WHERE character_id = c.character_id;
+    END LOOP;
+END $$;
+
+ALTER TABLE characters ALTER COLUMN slug SET NOT NULL;
+ALTER TABLE characters ADD CONSTRAINT characters_slug_key UNIQUE (slug);
+
+CREATE TABLE character_slug_history (
+    slug VARCHAR(255) PRIMARY KEY,
+    character_id INT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (character_id) REFERENCES characters(character_id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE character_slug_history;
+ALTER TABLE characters DROP COLUMN slug;
+-- +goose StatementEnd
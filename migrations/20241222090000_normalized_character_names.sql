@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- Folds case, accents, compatibility characters, apostrophes and any run of
+-- whitespace or punctuation so that "Jaqen H’ghar", "jaqen hghar" and
+-- "JAQEN  H'GHAR" compare equal.
+CREATE OR REPLACE FUNCTION normalize_name(name TEXT) RETURNS TEXT
+    LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE
+AS $$
+    SELECT btrim(regexp_replace(
+        regexp_replace(
+            lower(public.unaccent('public.unaccent'::regdictionary, normalize(name, NFKC))),
+            '[''`´‘’ʼ]', '', 'g'
+        ),
+        '[^[:alnum:]]+', ' ', 'g'
+    ))
+$$;
+
+ALTER TABLE characters
+    ADD COLUMN character_name_normalized TEXT
+    GENERATED ALWAYS AS (normalize_name(character_name)) STORED;
+
+CREATE INDEX characters_character_name_normalized_idx ON characters(character_name_normalized);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE characters DROP COLUMN character_name_normalized;
+DROP FUNCTION normalize_name(TEXT);
+-- +goose StatementEnd
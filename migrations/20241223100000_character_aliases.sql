@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE character_aliases (
+    alias_id SERIAL PRIMARY KEY,
+    character_id INT NOT NULL,
+    alias VARCHAR(255) NOT NULL,
+    alias_type VARCHAR(32) NOT NULL CHECK (alias_type IN ('nickname', 'title', 'birth_name')),
+    alias_normalized TEXT GENERATED ALWAYS AS (normalize_name(alias)) STORED,
+    UNIQUE (character_id, alias),
+    FOREIGN KEY (character_id) REFERENCES characters(character_id) ON DELETE CASCADE
+);
+
+CREATE INDEX character_aliases_alias_normalized_idx ON character_aliases(alias_normalized);
+
+INSERT INTO character_aliases (character_id, alias, alias_type)
+SELECT character_id, nickname, 'nickname'
+FROM characters
+WHERE COALESCE(nickname, '') <> '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE character_aliases;
+-- +goose StatementEnd
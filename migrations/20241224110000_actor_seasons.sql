@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+DELETE FROM characters_actors AS ca
+USING characters_actors AS duplicate
+WHERE ca.character_id = duplicate.character_id
+    AND ca.actor_id = duplicate.actor_id
+    AND ca.character_actor_id > duplicate.character_actor_id;
+
+ALTER TABLE characters_actors
+    ADD COLUMN seasons_active INT[] NOT NULL DEFAULT '{}',
+    ADD CONSTRAINT characters_actors_character_id_actor_id_key UNIQUE (character_id, actor_id);
+
+CREATE INDEX characters_actors_actor_id_idx ON characters_actors(actor_id);
+
+ALTER TABLE actors
+    ADD COLUMN actor_name_normalized TEXT
+    GENERATED ALWAYS AS (normalize_name(actor_name)) STORED;
+
+CREATE INDEX actors_actor_name_normalized_idx ON actors(actor_name_normalized);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE actors DROP COLUMN actor_name_normalized;
+DROP INDEX characters_actors_actor_id_idx;
+ALTER TABLE characters_actors
+    DROP CONSTRAINT characters_actors_character_id_actor_id_key,
+    DROP COLUMN seasons_active;
+-- +goose StatementEnd
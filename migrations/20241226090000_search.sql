@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX characters_character_name_trgm_idx ON characters USING GIN (character_name_normalized gin_trgm_ops);
+CREATE INDEX character_aliases_alias_trgm_idx ON character_aliases USING GIN (alias_normalized gin_trgm_ops);
+CREATE INDEX actors_actor_name_trgm_idx ON actors USING GIN (actor_name_normalized gin_trgm_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX actors_actor_name_trgm_idx;
+DROP INDEX character_aliases_alias_trgm_idx;
+DROP INDEX characters_character_name_trgm_idx;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE search_outbox (
+    outbox_id BIGSERIAL PRIMARY KEY,
+    character_id INT NOT NULL,
+    operation VARCHAR(16) NOT NULL CHECK (operation IN ('upsert', 'delete')),
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX search_outbox_attempts_idx ON search_outbox(attempts, outbox_id);
+
+-- index everything that exists already
+INSERT INTO search_outbox (character_id, operation)
+SELECT character_id, 'upsert'
+FROM characters
+ORDER BY character_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE search_outbox;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- attributes of the imported dataset no column maps to, kept as they came
+ALTER TABLE characters
+    ADD COLUMN extra JSONB NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE characters DROP COLUMN extra;
+-- +goose StatementEnd
@@ -0,0 +1,51 @@
+-- +goose Up
+-- +goose StatementBegin
+-- one search document per character out of its name, aliases, nickname,
+-- actors and houses, stored so full text and trigram searches use indexes
+-- rather than building the documents of every character per query
+CREATE TABLE character_search_documents (
+    character_id INT PRIMARY KEY REFERENCES characters(character_id) ON DELETE CASCADE,
+    document TSVECTOR NOT NULL,
+    words TEXT NOT NULL
+);
+
+CREATE INDEX character_search_documents_document_idx ON character_search_documents USING GIN (document);
+CREATE INDEX character_search_documents_words_trgm_idx ON character_search_documents USING GIN (words gin_trgm_ops);
+
+-- refresh_search_documents rebuilds the documents of the characters. Names
+-- and aliases weigh the most, like character_name^2 in the Elasticsearch
+-- query.
+CREATE OR REPLACE FUNCTION refresh_search_documents(character_ids INT[]) RETURNS VOID
+    LANGUAGE sql
+AS $$
+    INSERT INTO character_search_documents (character_id, document, words)
+    SELECT
+        c.character_id,
+        setweight(to_tsvector('simple', normalize_name(c.character_name)), 'A') ||
+        setweight(to_tsvector('simple', normalize_name(COALESCE(string_agg(DISTINCT al.alias, ' '), ''))), 'A') ||
+        setweight(to_tsvector('simple', normalize_name(COALESCE(string_agg(DISTINCT a.actor_name, ' '), ''))), 'B') ||
+        setweight(to_tsvector('simple', normalize_name(COALESCE(c.house_name, ''))), 'C'),
+        concat_ws(' ',
+            c.character_name_normalized,
+            string_agg(DISTINCT al.alias_normalized, ' '),
+            string_agg(DISTINCT a.actor_name_normalized, ' '),
+            normalize_name(COALESCE(c.house_name, ''))
+        )
+    FROM characters AS c
+    LEFT JOIN character_aliases AS al ON al.character_id = c.character_id
+    LEFT JOIN characters_actors AS ca ON ca.character_id = c.character_id
+    LEFT JOIN actors AS a ON a.actor_id = ca.actor_id
+    WHERE c.character_id = ANY(character_ids)
+    GROUP BY c.character_id
+    ON CONFLICT (character_id) DO UPDATE
+        SET document = EXCLUDED.document, words = EXCLUDED.words;
+$$;
+
+SELECT refresh_search_documents(ARRAY(SELECT character_id FROM characters));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP FUNCTION refresh_search_documents(INT[]);
+DROP TABLE character_search_documents;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- names that only differ in case, accents or punctuation resolve to the
+-- same character, so two of them can't be stored. Existing duplicates have
+-- to be merged or renamed by hand first.
+DO $$
+DECLARE
+    duplicates TEXT;
+BEGIN
+    SELECT string_agg(names, '; ') INTO duplicates FROM (
+        SELECT string_agg(character_name, ', ' ORDER BY character_id) AS names
+        FROM characters
+        GROUP BY character_name_normalized
+        HAVING count(*) > 1
+    ) AS d;
+    IF duplicates IS NOT NULL THEN
+        RAISE EXCEPTION 'characters with the same normalized name: %', duplicates;
+    END IF;
+END
+$$;
+
+DROP INDEX characters_character_name_normalized_idx;
+CREATE UNIQUE INDEX characters_character_name_normalized_key ON characters(character_name_normalized);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX characters_character_name_normalized_key;
+CREATE INDEX characters_character_name_normalized_idx ON characters(character_name_normalized);
+-- +goose StatementEnd
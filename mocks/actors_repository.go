@@ -22,12 +22,33 @@ var _ entities.ActorsRepository = &ActorsRepositoryMock{}
 //			CreateFunc: func(ctx context.Context, actorName string, actorLink string) (int, error) {
 //				panic("mock out the Create method")
 //			},
+//			DeleteOrphansFunc: func(ctx context.Context, actorIDs ...int) ([]string, error) {
+//				panic("mock out the DeleteOrphans method")
+//			},
+//			FindDuplicatesFunc: func(ctx context.Context) ([][]entities.ActorEntry, error) {
+//				panic("mock out the FindDuplicates method")
+//			},
+//			GetFunc: func(ctx context.Context, actorName string) (entities.ActorEntry, error) {
+//				panic("mock out the Get method")
+//			},
 //			GetActorIDFunc: func(ctx context.Context, actorName string) (int, error) {
 //				panic("mock out the GetActorID method")
 //			},
-//			LinkActorToCharacterFunc: func(ctx context.Context, actorId int, characterId int) error {
+//			GetAllFunc: func(ctx context.Context, filter entities.ActorFilter, page int) ([]entities.ActorEntry, error) {
+//				panic("mock out the GetAll method")
+//			},
+//			GetOrphansFunc: func(ctx context.Context) ([]entities.ActorEntry, error) {
+//				panic("mock out the GetOrphans method")
+//			},
+//			GetRolesFunc: func(ctx context.Context, actorID int, season int, page int) ([]entities.ActorRole, error) {
+//				panic("mock out the GetRoles method")
+//			},
+//			LinkActorToCharacterFunc: func(ctx context.Context, actorId int, characterId int, seasonsActive []int) error {
 //				panic("mock out the LinkActorToCharacter method")
 //			},
+//			MergeFunc: func(ctx context.Context, keepID int, duplicateID int) error {
+//				panic("mock out the Merge method")
+//			},
 //		}
 //
 //		// use mockedActorsRepository in code that requires entities.ActorsRepository
@@ -38,11 +59,32 @@ type ActorsRepositoryMock struct {
 	// CreateFunc mocks the Create method.
 	CreateFunc func(ctx context.Context, actorName string, actorLink string) (int, error)
 
+	// DeleteOrphansFunc mocks the DeleteOrphans method.
+	DeleteOrphansFunc func(ctx context.Context, actorIDs ...int) ([]string, error)
+
+	// FindDuplicatesFunc mocks the FindDuplicates method.
+	FindDuplicatesFunc func(ctx context.Context) ([][]entities.ActorEntry, error)
+
+	// GetFunc mocks the Get method.
+	GetFunc func(ctx context.Context, actorName string) (entities.ActorEntry, error)
+
 	// GetActorIDFunc mocks the GetActorID method.
 	GetActorIDFunc func(ctx context.Context, actorName string) (int, error)
 
+	// GetAllFunc mocks the GetAll method.
+	GetAllFunc func(ctx context.Context, filter entities.ActorFilter, page int) ([]entities.ActorEntry, error)
+
+	// GetOrphansFunc mocks the GetOrphans method.
+	GetOrphansFunc func(ctx context.Context) ([]entities.ActorEntry, error)
+
+	// GetRolesFunc mocks the GetRoles method.
+	GetRolesFunc func(ctx context.Context, actorID int, season int, page int) ([]entities.ActorRole, error)
+
 	// LinkActorToCharacterFunc mocks the LinkActorToCharacter method.
-	LinkActorToCharacterFunc func(ctx context.Context, actorId int, characterId int) error
+	LinkActorToCharacterFunc func(ctx context.Context, actorId int, characterId int, seasonsActive []int) error
+
+	// MergeFunc mocks the Merge method.
+	MergeFunc func(ctx context.Context, keepID int, duplicateID int) error
 
 	// calls tracks calls to the methods.
 	calls struct {
@@ -55,6 +97,25 @@ type ActorsRepositoryMock struct {
 			// ActorLink is the actorLink argument value.
 			ActorLink string
 		}
+		// DeleteOrphans holds details about calls to the DeleteOrphans method.
+		DeleteOrphans []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ActorIDs is the actorIDs argument value.
+			ActorIDs []int
+		}
+		// FindDuplicates holds details about calls to the FindDuplicates method.
+		FindDuplicates []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// Get holds details about calls to the Get method.
+		Get []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ActorName is the actorName argument value.
+			ActorName string
+		}
 		// GetActorID holds details about calls to the GetActorID method.
 		GetActorID []struct {
 			// Ctx is the ctx argument value.
@@ -62,6 +123,31 @@ type ActorsRepositoryMock struct {
 			// ActorName is the actorName argument value.
 			ActorName string
 		}
+		// GetAll holds details about calls to the GetAll method.
+		GetAll []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter entities.ActorFilter
+			// Page is the page argument value.
+			Page int
+		}
+		// GetOrphans holds details about calls to the GetOrphans method.
+		GetOrphans []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// GetRoles holds details about calls to the GetRoles method.
+		GetRoles []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ActorID is the actorID argument value.
+			ActorID int
+			// Season is the season argument value.
+			Season int
+			// Page is the page argument value.
+			Page int
+		}
 		// LinkActorToCharacter holds details about calls to the LinkActorToCharacter method.
 		LinkActorToCharacter []struct {
 			// Ctx is the ctx argument value.
@@ -70,11 +156,29 @@ type ActorsRepositoryMock struct {
 			ActorId int
 			// CharacterId is the characterId argument value.
 			CharacterId int
+			// SeasonsActive is the seasonsActive argument value.
+			SeasonsActive []int
+		}
+		// Merge holds details about calls to the Merge method.
+		Merge []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// KeepID is the keepID argument value.
+			KeepID int
+			// DuplicateID is the duplicateID argument value.
+			DuplicateID int
 		}
 	}
 	lockCreate               sync.RWMutex
+	lockDeleteOrphans        sync.RWMutex
+	lockFindDuplicates       sync.RWMutex
+	lockGet                  sync.RWMutex
 	lockGetActorID           sync.RWMutex
+	lockGetAll               sync.RWMutex
+	lockGetOrphans           sync.RWMutex
+	lockGetRoles             sync.RWMutex
 	lockLinkActorToCharacter sync.RWMutex
+	lockMerge                sync.RWMutex
 }
 
 // Create calls CreateFunc.
@@ -117,6 +221,110 @@ func (mock *ActorsRepositoryMock) CreateCalls() []struct {
 	return calls
 }
 
+// DeleteOrphans calls DeleteOrphansFunc.
+func (mock *ActorsRepositoryMock) DeleteOrphans(ctx context.Context, actorIDs ...int) ([]string, error) {
+	if mock.DeleteOrphansFunc == nil {
+		panic("ActorsRepositoryMock.DeleteOrphansFunc: method is nil but ActorsRepository.DeleteOrphans was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		ActorIDs []int
+	}{
+		Ctx:      ctx,
+		ActorIDs: actorIDs,
+	}
+	mock.lockDeleteOrphans.Lock()
+	mock.calls.DeleteOrphans = append(mock.calls.DeleteOrphans, callInfo)
+	mock.lockDeleteOrphans.Unlock()
+	return mock.DeleteOrphansFunc(ctx, actorIDs...)
+}
+
+// DeleteOrphansCalls gets all the calls that were made to DeleteOrphans.
+// Check the length with:
+//
+//	len(mockedActorsRepository.DeleteOrphansCalls())
+func (mock *ActorsRepositoryMock) DeleteOrphansCalls() []struct {
+	Ctx      context.Context
+	ActorIDs []int
+} {
+	var calls []struct {
+		Ctx      context.Context
+		ActorIDs []int
+	}
+	mock.lockDeleteOrphans.RLock()
+	calls = mock.calls.DeleteOrphans
+	mock.lockDeleteOrphans.RUnlock()
+	return calls
+}
+
+// FindDuplicates calls FindDuplicatesFunc.
+func (mock *ActorsRepositoryMock) FindDuplicates(ctx context.Context) ([][]entities.ActorEntry, error) {
+	if mock.FindDuplicatesFunc == nil {
+		panic("ActorsRepositoryMock.FindDuplicatesFunc: method is nil but ActorsRepository.FindDuplicates was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockFindDuplicates.Lock()
+	mock.calls.FindDuplicates = append(mock.calls.FindDuplicates, callInfo)
+	mock.lockFindDuplicates.Unlock()
+	return mock.FindDuplicatesFunc(ctx)
+}
+
+// FindDuplicatesCalls gets all the calls that were made to FindDuplicates.
+// Check the length with:
+//
+//	len(mockedActorsRepository.FindDuplicatesCalls())
+func (mock *ActorsRepositoryMock) FindDuplicatesCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockFindDuplicates.RLock()
+	calls = mock.calls.FindDuplicates
+	mock.lockFindDuplicates.RUnlock()
+	return calls
+}
+
+// Get calls GetFunc.
+func (mock *ActorsRepositoryMock) Get(ctx context.Context, actorName string) (entities.ActorEntry, error) {
+	if mock.GetFunc == nil {
+		panic("ActorsRepositoryMock.GetFunc: method is nil but ActorsRepository.Get was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		ActorName string
+	}{
+		Ctx:       ctx,
+		ActorName: actorName,
+	}
+	mock.lockGet.Lock()
+	mock.calls.Get = append(mock.calls.Get, callInfo)
+	mock.lockGet.Unlock()
+	return mock.GetFunc(ctx, actorName)
+}
+
+// GetCalls gets all the calls that were made to Get.
+// Check the length with:
+//
+//	len(mockedActorsRepository.GetCalls())
+func (mock *ActorsRepositoryMock) GetCalls() []struct {
+	Ctx       context.Context
+	ActorName string
+} {
+	var calls []struct {
+		Ctx       context.Context
+		ActorName string
+	}
+	mock.lockGet.RLock()
+	calls = mock.calls.Get
+	mock.lockGet.RUnlock()
+	return calls
+}
+
 // GetActorID calls GetActorIDFunc.
 func (mock *ActorsRepositoryMock) GetActorID(ctx context.Context, actorName string) (int, error) {
 	if mock.GetActorIDFunc == nil {
@@ -153,24 +361,142 @@ func (mock *ActorsRepositoryMock) GetActorIDCalls() []struct {
 	return calls
 }
 
+// GetAll calls GetAllFunc.
+func (mock *ActorsRepositoryMock) GetAll(ctx context.Context, filter entities.ActorFilter, page int) ([]entities.ActorEntry, error) {
+	if mock.GetAllFunc == nil {
+		panic("ActorsRepositoryMock.GetAllFunc: method is nil but ActorsRepository.GetAll was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Filter entities.ActorFilter
+		Page   int
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+		Page:   page,
+	}
+	mock.lockGetAll.Lock()
+	mock.calls.GetAll = append(mock.calls.GetAll, callInfo)
+	mock.lockGetAll.Unlock()
+	return mock.GetAllFunc(ctx, filter, page)
+}
+
+// GetAllCalls gets all the calls that were made to GetAll.
+// Check the length with:
+//
+//	len(mockedActorsRepository.GetAllCalls())
+func (mock *ActorsRepositoryMock) GetAllCalls() []struct {
+	Ctx    context.Context
+	Filter entities.ActorFilter
+	Page   int
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Filter entities.ActorFilter
+		Page   int
+	}
+	mock.lockGetAll.RLock()
+	calls = mock.calls.GetAll
+	mock.lockGetAll.RUnlock()
+	return calls
+}
+
+// GetOrphans calls GetOrphansFunc.
+func (mock *ActorsRepositoryMock) GetOrphans(ctx context.Context) ([]entities.ActorEntry, error) {
+	if mock.GetOrphansFunc == nil {
+		panic("ActorsRepositoryMock.GetOrphansFunc: method is nil but ActorsRepository.GetOrphans was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockGetOrphans.Lock()
+	mock.calls.GetOrphans = append(mock.calls.GetOrphans, callInfo)
+	mock.lockGetOrphans.Unlock()
+	return mock.GetOrphansFunc(ctx)
+}
+
+// GetOrphansCalls gets all the calls that were made to GetOrphans.
+// Check the length with:
+//
+//	len(mockedActorsRepository.GetOrphansCalls())
+func (mock *ActorsRepositoryMock) GetOrphansCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockGetOrphans.RLock()
+	calls = mock.calls.GetOrphans
+	mock.lockGetOrphans.RUnlock()
+	return calls
+}
+
+// GetRoles calls GetRolesFunc.
+func (mock *ActorsRepositoryMock) GetRoles(ctx context.Context, actorID int, season int, page int) ([]entities.ActorRole, error) {
+	if mock.GetRolesFunc == nil {
+		panic("ActorsRepositoryMock.GetRolesFunc: method is nil but ActorsRepository.GetRoles was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		ActorID int
+		Season  int
+		Page    int
+	}{
+		Ctx:     ctx,
+		ActorID: actorID,
+		Season:  season,
+		Page:    page,
+	}
+	mock.lockGetRoles.Lock()
+	mock.calls.GetRoles = append(mock.calls.GetRoles, callInfo)
+	mock.lockGetRoles.Unlock()
+	return mock.GetRolesFunc(ctx, actorID, season, page)
+}
+
+// GetRolesCalls gets all the calls that were made to GetRoles.
+// Check the length with:
+//
+//	len(mockedActorsRepository.GetRolesCalls())
+func (mock *ActorsRepositoryMock) GetRolesCalls() []struct {
+	Ctx     context.Context
+	ActorID int
+	Season  int
+	Page    int
+} {
+	var calls []struct {
+		Ctx     context.Context
+		ActorID int
+		Season  int
+		Page    int
+	}
+	mock.lockGetRoles.RLock()
+	calls = mock.calls.GetRoles
+	mock.lockGetRoles.RUnlock()
+	return calls
+}
+
 // LinkActorToCharacter calls LinkActorToCharacterFunc.
-func (mock *ActorsRepositoryMock) LinkActorToCharacter(ctx context.Context, actorId int, characterId int) error {
+func (mock *ActorsRepositoryMock) LinkActorToCharacter(ctx context.Context, actorId int, characterId int, seasonsActive []int) error {
 	if mock.LinkActorToCharacterFunc == nil {
 		panic("ActorsRepositoryMock.LinkActorToCharacterFunc: method is nil but ActorsRepository.LinkActorToCharacter was just called")
 	}
 	callInfo := struct {
-		Ctx         context.Context
-		ActorId     int
-		CharacterId int
+		Ctx           context.Context
+		ActorId       int
+		CharacterId   int
+		SeasonsActive []int
 	}{
-		Ctx:         ctx,
-		ActorId:     actorId,
-		CharacterId: characterId,
+		Ctx:           ctx,
+		ActorId:       actorId,
+		CharacterId:   characterId,
+		SeasonsActive: seasonsActive,
 	}
 	mock.lockLinkActorToCharacter.Lock()
 	mock.calls.LinkActorToCharacter = append(mock.calls.LinkActorToCharacter, callInfo)
 	mock.lockLinkActorToCharacter.Unlock()
-	return mock.LinkActorToCharacterFunc(ctx, actorId, characterId)
+	return mock.LinkActorToCharacterFunc(ctx, actorId, characterId, seasonsActive)
 }
 
 // LinkActorToCharacterCalls gets all the calls that were made to LinkActorToCharacter.
@@ -178,17 +504,59 @@ func (mock *ActorsRepositoryMock) LinkActorToCharacter(ctx context.Context, acto
 //
 //	len(mockedActorsRepository.LinkActorToCharacterCalls())
 func (mock *ActorsRepositoryMock) LinkActorToCharacterCalls() []struct {
-	Ctx         context.Context
-	ActorId     int
-	CharacterId int
+	Ctx           context.Context
+	ActorId       int
+	CharacterId   int
+	SeasonsActive []int
 } {
 	var calls []struct {
-		Ctx         context.Context
-		ActorId     int
-		CharacterId int
+		Ctx           context.Context
+		ActorId       int
+		CharacterId   int
+		SeasonsActive []int
 	}
 	mock.lockLinkActorToCharacter.RLock()
 	calls = mock.calls.LinkActorToCharacter
 	mock.lockLinkActorToCharacter.RUnlock()
 	return calls
 }
+
+// Merge calls MergeFunc.
+func (mock *ActorsRepositoryMock) Merge(ctx context.Context, keepID int, duplicateID int) error {
+	if mock.MergeFunc == nil {
+		panic("ActorsRepositoryMock.MergeFunc: method is nil but ActorsRepository.Merge was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		KeepID      int
+		DuplicateID int
+	}{
+		Ctx:         ctx,
+		KeepID:      keepID,
+		DuplicateID: duplicateID,
+	}
+	mock.lockMerge.Lock()
+	mock.calls.Merge = append(mock.calls.Merge, callInfo)
+	mock.lockMerge.Unlock()
+	return mock.MergeFunc(ctx, keepID, duplicateID)
+}
+
+// MergeCalls gets all the calls that were made to Merge.
+// Check the length with:
+//
+//	len(mockedActorsRepository.MergeCalls())
+func (mock *ActorsRepositoryMock) MergeCalls() []struct {
+	Ctx         context.Context
+	KeepID      int
+	DuplicateID int
+} {
+	var calls []struct {
+		Ctx         context.Context
+		KeepID      int
+		DuplicateID int
+	}
+	mock.lockMerge.RLock()
+	calls = mock.calls.Merge
+	mock.lockMerge.RUnlock()
+	return calls
+}
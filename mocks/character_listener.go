@@ -0,0 +1,132 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mocks
+
+import (
+	"context"
+	"github.com/vitalii-komenda/got/entities"
+	"sync"
+)
+
+// Ensure, that CharacterListenerMock does implement entities.CharacterListener.
+// If this is not the case, regenerate this file with moq.
+var _ entities.CharacterListener = &CharacterListenerMock{}
+
+// CharacterListenerMock is a mock implementation of entities.CharacterListener.
+//
+//	func TestSomethingThatUsesCharacterListener(t *testing.T) {
+//
+//		// make and configure a mocked entities.CharacterListener
+//		mockedCharacterListener := &CharacterListenerMock{
+//			CharacterDeletedFunc: func(ctx context.Context, characterID int) error {
+//				panic("mock out the CharacterDeleted method")
+//			},
+//			CharacterSavedFunc: func(ctx context.Context, characterID int) error {
+//				panic("mock out the CharacterSaved method")
+//			},
+//		}
+//
+//		// use mockedCharacterListener in code that requires entities.CharacterListener
+//		// and then make assertions.
+//
+//	}
+type CharacterListenerMock struct {
+	// CharacterDeletedFunc mocks the CharacterDeleted method.
+	CharacterDeletedFunc func(ctx context.Context, characterID int) error
+
+	// CharacterSavedFunc mocks the CharacterSaved method.
+	CharacterSavedFunc func(ctx context.Context, characterID int) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// CharacterDeleted holds details about calls to the CharacterDeleted method.
+		CharacterDeleted []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// CharacterID is the characterID argument value.
+			CharacterID int
+		}
+		// CharacterSaved holds details about calls to the CharacterSaved method.
+		CharacterSaved []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// CharacterID is the characterID argument value.
+			CharacterID int
+		}
+	}
+	lockCharacterDeleted sync.RWMutex
+	lockCharacterSaved   sync.RWMutex
+}
+
+// CharacterDeleted calls CharacterDeletedFunc.
+func (mock *CharacterListenerMock) CharacterDeleted(ctx context.Context, characterID int) error {
+	if mock.CharacterDeletedFunc == nil {
+		panic("CharacterListenerMock.CharacterDeletedFunc: method is nil but CharacterListener.CharacterDeleted was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		CharacterID int
+	}{
+		Ctx:         ctx,
+		CharacterID: characterID,
+	}
+	mock.lockCharacterDeleted.Lock()
+	mock.calls.CharacterDeleted = append(mock.calls.CharacterDeleted, callInfo)
+	mock.lockCharacterDeleted.Unlock()
+	return mock.CharacterDeletedFunc(ctx, characterID)
+}
+
+// CharacterDeletedCalls gets all the calls that were made to CharacterDeleted.
+// Check the length with:
+//
+//	len(mockedCharacterListener.CharacterDeletedCalls())
+func (mock *CharacterListenerMock) CharacterDeletedCalls() []struct {
+	Ctx         context.Context
+	CharacterID int
+} {
+	var calls []struct {
+		Ctx         context.Context
+		CharacterID int
+	}
+	mock.lockCharacterDeleted.RLock()
+	calls = mock.calls.CharacterDeleted
+	mock.lockCharacterDeleted.RUnlock()
+	return calls
+}
+
+// CharacterSaved calls CharacterSavedFunc.
+func (mock *CharacterListenerMock) CharacterSaved(ctx context.Context, characterID int) error {
+	if mock.CharacterSavedFunc == nil {
+		panic("CharacterListenerMock.CharacterSavedFunc: method is nil but CharacterListener.CharacterSaved was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		CharacterID int
+	}{
+		Ctx:         ctx,
+		CharacterID: characterID,
+	}
+	mock.lockCharacterSaved.Lock()
+	mock.calls.CharacterSaved = append(mock.calls.CharacterSaved, callInfo)
+	mock.lockCharacterSaved.Unlock()
+	return mock.CharacterSavedFunc(ctx, characterID)
+}
+
+// CharacterSavedCalls gets all the calls that were made to CharacterSaved.
+// Check the length with:
+//
+//	len(mockedCharacterListener.CharacterSavedCalls())
+func (mock *CharacterListenerMock) CharacterSavedCalls() []struct {
+	Ctx         context.Context
+	CharacterID int
+} {
+	var calls []struct {
+		Ctx         context.Context
+		CharacterID int
+	}
+	mock.lockCharacterSaved.RLock()
+	calls = mock.calls.CharacterSaved
+	mock.lockCharacterSaved.RUnlock()
+	return calls
+}
@@ -0,0 +1,82 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mocks
+
+import (
+	"context"
+	"github.com/vitalii-komenda/got/entities"
+	"sync"
+)
+
+// Ensure, that CharacterTransactorMock does implement entities.CharacterTransactor.
+// If this is not the case, regenerate this file with moq.
+var _ entities.CharacterTransactor = &CharacterTransactorMock{}
+
+// CharacterTransactorMock is a mock implementation of entities.CharacterTransactor.
+//
+//	func TestSomethingThatUsesCharacterTransactor(t *testing.T) {
+//
+//		// make and configure a mocked entities.CharacterTransactor
+//		mockedCharacterTransactor := &CharacterTransactorMock{
+//			InTxFunc: func(ctx context.Context, fn func(characters entities.CharactersRepository, relationships entities.RelationshipsRepository) error) error {
+//				panic("mock out the InTx method")
+//			},
+//		}
+//
+//		// use mockedCharacterTransactor in code that requires entities.CharacterTransactor
+//		// and then make assertions.
+//
+//	}
+type CharacterTransactorMock struct {
+	// InTxFunc mocks the InTx method.
+	InTxFunc func(ctx context.Context, fn func(characters entities.CharactersRepository, relationships entities.RelationshipsRepository) error) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// InTx holds details about calls to the InTx method.
+		InTx []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Fn is the fn argument value.
+			Fn func(characters entities.CharactersRepository, relationships entities.RelationshipsRepository) error
+		}
+	}
+	lockInTx sync.RWMutex
+}
+
+// InTx calls InTxFunc.
+func (mock *CharacterTransactorMock) InTx(ctx context.Context, fn func(characters entities.CharactersRepository, relationships entities.RelationshipsRepository) error) error {
+	if mock.InTxFunc == nil {
+		panic("CharacterTransactorMock.InTxFunc: method is nil but CharacterTransactor.InTx was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Fn  func(characters entities.CharactersRepository, relationships entities.RelationshipsRepository) error
+	}{
+		Ctx: ctx,
+		Fn:  fn,
+	}
+	mock.lockInTx.Lock()
+	mock.calls.InTx = append(mock.calls.InTx, callInfo)
+	mock.lockInTx.Unlock()
+	return mock.InTxFunc(ctx, fn)
+}
+
+// InTxCalls gets all the calls that were made to InTx.
+// Check the length with:
+//
+//	len(mockedCharacterTransactor.InTxCalls())
+func (mock *CharacterTransactorMock) InTxCalls() []struct {
+	Ctx context.Context
+	Fn  func(characters entities.CharactersRepository, relationships entities.RelationshipsRepository) error
+} {
+	var calls []struct {
+		Ctx context.Context
+		Fn  func(characters entities.CharactersRepository, relationships entities.RelationshipsRepository) error
+	}
+	mock.lockInTx.RLock()
+	calls = mock.calls.InTx
+	mock.lockInTx.RUnlock()
+	return calls
+}
@@ -25,22 +25,31 @@ var _ entities.CharactersRepository = &CharactersRepositoryMock{}
 //			CreateCharacterAndActorFunc: func(ctx context.Context, characterEntry *entities.CharacterEntry) error {
 //				panic("mock out the CreateCharacterAndActor method")
 //			},
-//			DeleteFunc: func(ctx context.Context, name string) error {
+//			DeleteFunc: func(ctx context.Context, characterID int) error {
 //				panic("mock out the Delete method")
 //			},
-//			GetFunc: func(ctx context.Context, name string) ([]entities.CharacterEntry, error) {
+//			GetFunc: func(ctx context.Context, characterID int) ([]entities.CharacterEntry, error) {
 //				panic("mock out the Get method")
 //			},
+//			GetAfterFunc: func(ctx context.Context, afterID int, limit int) ([]entities.CharacterEntry, error) {
+//				panic("mock out the GetAfter method")
+//			},
 //			GetAllFunc: func(ctx context.Context, page int) ([]entities.CharacterEntry, error) {
 //				panic("mock out the GetAll method")
 //			},
+//			GetByIDsFunc: func(ctx context.Context, characterIDs []int) ([]entities.CharacterEntry, error) {
+//				panic("mock out the GetByIDs method")
+//			},
 //			GetCharacterIDFunc: func(ctx context.Context, characterName string) (int, error) {
 //				panic("mock out the GetCharacterID method")
 //			},
-//			SearchFunc: func(ctx context.Context, term string) ([]entities.CharacterEntry, error) {
-//				panic("mock out the Search method")
+//			ResolveFunc: func(ctx context.Context, key string) (entities.CharacterRef, error) {
+//				panic("mock out the Resolve method")
+//			},
+//			ResolveSlugFunc: func(ctx context.Context, slug string) (entities.CharacterRef, error) {
+//				panic("mock out the ResolveSlug method")
 //			},
-//			UpdateCharacterAndActorFunc: func(ctx context.Context, characterEntryEntry *entities.CharacterEntry, characterName string) (int, error) {
+//			UpdateCharacterAndActorFunc: func(ctx context.Context, characterEntryEntry *entities.CharacterEntry, characterID int) (int, error) {
 //				panic("mock out the UpdateCharacterAndActor method")
 //			},
 //		}
@@ -57,22 +66,31 @@ type CharactersRepositoryMock struct {
 	CreateCharacterAndActorFunc func(ctx context.Context, characterEntry *entities.CharacterEntry) error
 
 	// DeleteFunc mocks the Delete method.
-	DeleteFunc func(ctx context.Context, name string) error
+	DeleteFunc func(ctx context.Context, characterID int) error
 
 	// GetFunc mocks the Get method.
-	GetFunc func(ctx context.Context, name string) ([]entities.CharacterEntry, error)
+	GetFunc func(ctx context.Context, characterID int) ([]entities.CharacterEntry, error)
+
+	// GetAfterFunc mocks the GetAfter method.
+	GetAfterFunc func(ctx context.Context, afterID int, limit int) ([]entities.CharacterEntry, error)
 
 	// GetAllFunc mocks the GetAll method.
 	GetAllFunc func(ctx context.Context, page int) ([]entities.CharacterEntry, error)
 
+	// GetByIDsFunc mocks the GetByIDs method.
+	GetByIDsFunc func(ctx context.Context, characterIDs []int) ([]entities.CharacterEntry, error)
+
 	// GetCharacterIDFunc mocks the GetCharacterID method.
 	GetCharacterIDFunc func(ctx context.Context, characterName string) (int, error)
 
-	// SearchFunc mocks the Search method.
-	SearchFunc func(ctx context.Context, term string) ([]entities.CharacterEntry, error)
+	// ResolveFunc mocks the Resolve method.
+	ResolveFunc func(ctx context.Context, key string) (entities.CharacterRef, error)
+
+	// ResolveSlugFunc mocks the ResolveSlug method.
+	ResolveSlugFunc func(ctx context.Context, slug string) (entities.CharacterRef, error)
 
 	// UpdateCharacterAndActorFunc mocks the UpdateCharacterAndActor method.
-	UpdateCharacterAndActorFunc func(ctx context.Context, characterEntryEntry *entities.CharacterEntry, characterName string) (int, error)
+	UpdateCharacterAndActorFunc func(ctx context.Context, characterEntryEntry *entities.CharacterEntry, characterID int) (int, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
@@ -96,15 +114,24 @@ type CharactersRepositoryMock struct {
 		Delete []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
-			// Name is the name argument value.
-			Name string
+			// CharacterID is the characterID argument value.
+			CharacterID int
 		}
 		// Get holds details about calls to the Get method.
 		Get []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
-			// Name is the name argument value.
-			Name string
+			// CharacterID is the characterID argument value.
+			CharacterID int
+		}
+		// GetAfter holds details about calls to the GetAfter method.
+		GetAfter []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// AfterID is the afterID argument value.
+			AfterID int
+			// Limit is the limit argument value.
+			Limit int
 		}
 		// GetAll holds details about calls to the GetAll method.
 		GetAll []struct {
@@ -113,6 +140,13 @@ type CharactersRepositoryMock struct {
 			// Page is the page argument value.
 			Page int
 		}
+		// GetByIDs holds details about calls to the GetByIDs method.
+		GetByIDs []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// CharacterIDs is the characterIDs argument value.
+			CharacterIDs []int
+		}
 		// GetCharacterID holds details about calls to the GetCharacterID method.
 		GetCharacterID []struct {
 			// Ctx is the ctx argument value.
@@ -120,12 +154,19 @@ type CharactersRepositoryMock struct {
 			// CharacterName is the characterName argument value.
 			CharacterName string
 		}
-		// Search holds details about calls to the Search method.
-		Search []struct {
+		// Resolve holds details about calls to the Resolve method.
+		Resolve []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Key is the key argument value.
+			Key string
+		}
+		// ResolveSlug holds details about calls to the ResolveSlug method.
+		ResolveSlug []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
-			// Term is the term argument value.
-			Term string
+			// Slug is the slug argument value.
+			Slug string
 		}
 		// UpdateCharacterAndActor holds details about calls to the UpdateCharacterAndActor method.
 		UpdateCharacterAndActor []struct {
@@ -133,17 +174,20 @@ type CharactersRepositoryMock struct {
 			Ctx context.Context
 			// CharacterEntryEntry is the characterEntryEntry argument value.
 			CharacterEntryEntry *entities.CharacterEntry
-			// CharacterName is the characterName argument value.
-			CharacterName string
+			// CharacterID is the characterID argument value.
+			CharacterID int
 		}
 	}
 	lockCreateCharacter         sync.RWMutex
 	lockCreateCharacterAndActor sync.RWMutex
 	lockDelete                  sync.RWMutex
 	lockGet                     sync.RWMutex
+	lockGetAfter                sync.RWMutex
 	lockGetAll                  sync.RWMutex
+	lockGetByIDs                sync.RWMutex
 	lockGetCharacterID          sync.RWMutex
-	lockSearch                  sync.RWMutex
+	lockResolve                 sync.RWMutex
+	lockResolveSlug             sync.RWMutex
 	lockUpdateCharacterAndActor sync.RWMutex
 }
 
@@ -224,21 +268,21 @@ func (mock *CharactersRepositoryMock) CreateCharacterAndActorCalls() []struct {
 }
 
 // Delete calls DeleteFunc.
-func (mock *CharactersRepositoryMock) Delete(ctx context.Context, name string) error {
+func (mock *CharactersRepositoryMock) Delete(ctx context.Context, characterID int) error {
 	if mock.DeleteFunc == nil {
 		panic("CharactersRepositoryMock.DeleteFunc: method is nil but CharactersRepository.Delete was just called")
 	}
 	callInfo := struct {
-		Ctx  context.Context
-		Name string
+		Ctx         context.Context
+		CharacterID int
 	}{
-		Ctx:  ctx,
-		Name: name,
+		Ctx:         ctx,
+		CharacterID: characterID,
 	}
 	mock.lockDelete.Lock()
 	mock.calls.Delete = append(mock.calls.Delete, callInfo)
 	mock.lockDelete.Unlock()
-	return mock.DeleteFunc(ctx, name)
+	return mock.DeleteFunc(ctx, characterID)
 }
 
 // DeleteCalls gets all the calls that were made to Delete.
@@ -246,12 +290,12 @@ func (mock *CharactersRepositoryMock) Delete(ctx context.Context, name string) e
 //
 //	len(mockedCharactersRepository.DeleteCalls())
 func (mock *CharactersRepositoryMock) DeleteCalls() []struct {
-	Ctx  context.Context
-	Name string
+	Ctx         context.Context
+	CharacterID int
 } {
 	var calls []struct {
-		Ctx  context.Context
-		Name string
+		Ctx         context.Context
+		CharacterID int
 	}
 	mock.lockDelete.RLock()
 	calls = mock.calls.Delete
@@ -260,21 +304,21 @@ func (mock *CharactersRepositoryMock) DeleteCalls() []struct {
 }
 
 // Get calls GetFunc.
-func (mock *CharactersRepositoryMock) Get(ctx context.Context, name string) ([]entities.CharacterEntry, error) {
+func (mock *CharactersRepositoryMock) Get(ctx context.Context, characterID int) ([]entities.CharacterEntry, error) {
 	if mock.GetFunc == nil {
 		panic("CharactersRepositoryMock.GetFunc: method is nil but CharactersRepository.Get was just called")
 	}
 	callInfo := struct {
-		Ctx  context.Context
-		Name string
+		Ctx         context.Context
+		CharacterID int
 	}{
-		Ctx:  ctx,
-		Name: name,
+		Ctx:         ctx,
+		CharacterID: characterID,
 	}
 	mock.lockGet.Lock()
 	mock.calls.Get = append(mock.calls.Get, callInfo)
 	mock.lockGet.Unlock()
-	return mock.GetFunc(ctx, name)
+	return mock.GetFunc(ctx, characterID)
 }
 
 // GetCalls gets all the calls that were made to Get.
@@ -282,12 +326,12 @@ func (mock *CharactersRepositoryMock) Get(ctx context.Context, name string) ([]e
 //
 //	len(mockedCharactersRepository.GetCalls())
 func (mock *CharactersRepositoryMock) GetCalls() []struct {
-	Ctx  context.Context
-	Name string
+	Ctx         context.Context
+	CharacterID int
 } {
 	var calls []struct {
-		Ctx  context.Context
-		Name string
+		Ctx         context.Context
+		CharacterID int
 	}
 	mock.lockGet.RLock()
 	calls = mock.calls.Get
@@ -295,6 +339,46 @@ func (mock *CharactersRepositoryMock) GetCalls() []struct {
 	return calls
 }
 
+// GetAfter calls GetAfterFunc.
+func (mock *CharactersRepositoryMock) GetAfter(ctx context.Context, afterID int, limit int) ([]entities.CharacterEntry, error) {
+	if mock.GetAfterFunc == nil {
+		panic("CharactersRepositoryMock.GetAfterFunc: method is nil but CharactersRepository.GetAfter was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		AfterID int
+		Limit   int
+	}{
+		Ctx:     ctx,
+		AfterID: afterID,
+		Limit:   limit,
+	}
+	mock.lockGetAfter.Lock()
+	mock.calls.GetAfter = append(mock.calls.GetAfter, callInfo)
+	mock.lockGetAfter.Unlock()
+	return mock.GetAfterFunc(ctx, afterID, limit)
+}
+
+// GetAfterCalls gets all the calls that were made to GetAfter.
+// Check the length with:
+//
+//	len(mockedCharactersRepository.GetAfterCalls())
+func (mock *CharactersRepositoryMock) GetAfterCalls() []struct {
+	Ctx     context.Context
+	AfterID int
+	Limit   int
+} {
+	var calls []struct {
+		Ctx     context.Context
+		AfterID int
+		Limit   int
+	}
+	mock.lockGetAfter.RLock()
+	calls = mock.calls.GetAfter
+	mock.lockGetAfter.RUnlock()
+	return calls
+}
+
 // GetAll calls GetAllFunc.
 func (mock *CharactersRepositoryMock) GetAll(ctx context.Context, page int) ([]entities.CharacterEntry, error) {
 	if mock.GetAllFunc == nil {
@@ -331,6 +415,42 @@ func (mock *CharactersRepositoryMock) GetAllCalls() []struct {
 	return calls
 }
 
+// GetByIDs calls GetByIDsFunc.
+func (mock *CharactersRepositoryMock) GetByIDs(ctx context.Context, characterIDs []int) ([]entities.CharacterEntry, error) {
+	if mock.GetByIDsFunc == nil {
+		panic("CharactersRepositoryMock.GetByIDsFunc: method is nil but CharactersRepository.GetByIDs was just called")
+	}
+	callInfo := struct {
+		Ctx          context.Context
+		CharacterIDs []int
+	}{
+		Ctx:          ctx,
+		CharacterIDs: characterIDs,
+	}
+	mock.lockGetByIDs.Lock()
+	mock.calls.GetByIDs = append(mock.calls.GetByIDs, callInfo)
+	mock.lockGetByIDs.Unlock()
+	return mock.GetByIDsFunc(ctx, characterIDs)
+}
+
+// GetByIDsCalls gets all the calls that were made to GetByIDs.
+// Check the length with:
+//
+//	len(mockedCharactersRepository.GetByIDsCalls())
+func (mock *CharactersRepositoryMock) GetByIDsCalls() []struct {
+	Ctx          context.Context
+	CharacterIDs []int
+} {
+	var calls []struct {
+		Ctx          context.Context
+		CharacterIDs []int
+	}
+	mock.lockGetByIDs.RLock()
+	calls = mock.calls.GetByIDs
+	mock.lockGetByIDs.RUnlock()
+	return calls
+}
+
 // GetCharacterID calls GetCharacterIDFunc.
 func (mock *CharactersRepositoryMock) GetCharacterID(ctx context.Context, characterName string) (int, error) {
 	if mock.GetCharacterIDFunc == nil {
@@ -367,60 +487,96 @@ func (mock *CharactersRepositoryMock) GetCharacterIDCalls() []struct {
 	return calls
 }
 
-// Search calls SearchFunc.
-func (mock *CharactersRepositoryMock) Search(ctx context.Context, term string) ([]entities.CharacterEntry, error) {
-	if mock.SearchFunc == nil {
-		panic("CharactersRepositoryMock.SearchFunc: method is nil but CharactersRepository.Search was just called")
+// Resolve calls ResolveFunc.
+func (mock *CharactersRepositoryMock) Resolve(ctx context.Context, key string) (entities.CharacterRef, error) {
+	if mock.ResolveFunc == nil {
+		panic("CharactersRepositoryMock.ResolveFunc: method is nil but CharactersRepository.Resolve was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Key string
+	}{
+		Ctx: ctx,
+		Key: key,
+	}
+	mock.lockResolve.Lock()
+	mock.calls.Resolve = append(mock.calls.Resolve, callInfo)
+	mock.lockResolve.Unlock()
+	return mock.ResolveFunc(ctx, key)
+}
+
+// ResolveCalls gets all the calls that were made to Resolve.
+// Check the length with:
+//
+//	len(mockedCharactersRepository.ResolveCalls())
+func (mock *CharactersRepositoryMock) ResolveCalls() []struct {
+	Ctx context.Context
+	Key string
+} {
+	var calls []struct {
+		Ctx context.Context
+		Key string
+	}
+	mock.lockResolve.RLock()
+	calls = mock.calls.Resolve
+	mock.lockResolve.RUnlock()
+	return calls
+}
+
+// ResolveSlug calls ResolveSlugFunc.
+func (mock *CharactersRepositoryMock) ResolveSlug(ctx context.Context, slug string) (entities.CharacterRef, error) {
+	if mock.ResolveSlugFunc == nil {
+		panic("CharactersRepositoryMock.ResolveSlugFunc: method is nil but CharactersRepository.ResolveSlug was just called")
 	}
 	callInfo := struct {
 		Ctx  context.Context
-		Term string
+		Slug string
 	}{
 		Ctx:  ctx,
-		Term: term,
+		Slug: slug,
 	}
-	mock.lockSearch.Lock()
-	mock.calls.Search = append(mock.calls.Search, callInfo)
-	mock.lockSearch.Unlock()
-	return mock.SearchFunc(ctx, term)
+	mock.lockResolveSlug.Lock()
+	mock.calls.ResolveSlug = append(mock.calls.ResolveSlug, callInfo)
+	mock.lockResolveSlug.Unlock()
+	return mock.ResolveSlugFunc(ctx, slug)
 }
 
-// SearchCalls gets all the calls that were made to Search.
+// ResolveSlugCalls gets all the calls that were made to ResolveSlug.
 // Check the length with:
 //
-//	len(mockedCharactersRepository.SearchCalls())
-func (mock *CharactersRepositoryMock) SearchCalls() []struct {
+//	len(mockedCharactersRepository.ResolveSlugCalls())
+func (mock *CharactersRepositoryMock) ResolveSlugCalls() []struct {
 	Ctx  context.Context
-	Term string
+	Slug string
 } {
 	var calls []struct {
 		Ctx  context.Context
-		Term string
+		Slug string
 	}
-	mock.lockSearch.RLock()
-	calls = mock.calls.Search
-	mock.lockSearch.RUnlock()
+	mock.lockResolveSlug.RLock()
+	calls = mock.calls.ResolveSlug
+	mock.lockResolveSlug.RUnlock()
 	return calls
 }
 
 // UpdateCharacterAndActor calls UpdateCharacterAndActorFunc.
-func (mock *CharactersRepositoryMock) UpdateCharacterAndActor(ctx context.Context, characterEntryEntry *entities.CharacterEntry, characterName string) (int, error) {
+func (mock *CharactersRepositoryMock) UpdateCharacterAndActor(ctx context.Context, characterEntryEntry *entities.CharacterEntry, characterID int) (int, error) {
 	if mock.UpdateCharacterAndActorFunc == nil {
 		panic("CharactersRepositoryMock.UpdateCharacterAndActorFunc: method is nil but CharactersRepository.UpdateCharacterAndActor was just called")
 	}
 	callInfo := struct {
 		Ctx                 context.Context
 		CharacterEntryEntry *entities.CharacterEntry
-		CharacterName       string
+		CharacterID         int
 	}{
 		Ctx:                 ctx,
 		CharacterEntryEntry: characterEntryEntry,
-		CharacterName:       characterName,
+		CharacterID:         characterID,
 	}
 	mock.lockUpdateCharacterAndActor.Lock()
 	mock.calls.UpdateCharacterAndActor = append(mock.calls.UpdateCharacterAndActor, callInfo)
 	mock.lockUpdateCharacterAndActor.Unlock()
-	return mock.UpdateCharacterAndActorFunc(ctx, characterEntryEntry, characterName)
+	return mock.UpdateCharacterAndActorFunc(ctx, characterEntryEntry, characterID)
 }
 
 // UpdateCharacterAndActorCalls gets all the calls that were made to UpdateCharacterAndActor.
@@ -430,12 +586,12 @@ func (mock *CharactersRepositoryMock) UpdateCharacterAndActor(ctx context.Contex
 func (mock *CharactersRepositoryMock) UpdateCharacterAndActorCalls() []struct {
 	Ctx                 context.Context
 	CharacterEntryEntry *entities.CharacterEntry
-	CharacterName       string
+	CharacterID         int
 } {
 	var calls []struct {
 		Ctx                 context.Context
 		CharacterEntryEntry *entities.CharacterEntry
-		CharacterName       string
+		CharacterID         int
 	}
 	mock.lockUpdateCharacterAndActor.RLock()
 	calls = mock.calls.UpdateCharacterAndActor
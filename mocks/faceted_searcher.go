@@ -0,0 +1,82 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mocks
+
+import (
+	"context"
+	"github.com/vitalii-komenda/got/entities"
+	"sync"
+)
+
+// Ensure, that FacetedSearcherMock does implement entities.FacetedSearcher.
+// If this is not the case, regenerate this file with moq.
+var _ entities.FacetedSearcher = &FacetedSearcherMock{}
+
+// FacetedSearcherMock is a mock implementation of entities.FacetedSearcher.
+//
+//	func TestSomethingThatUsesFacetedSearcher(t *testing.T) {
+//
+//		// make and configure a mocked entities.FacetedSearcher
+//		mockedFacetedSearcher := &FacetedSearcherMock{
+//			QueryFunc: func(ctx context.Context, query entities.ElasticSearchQuery) (entities.ElasticSearchResult, error) {
+//				panic("mock out the Query method")
+//			},
+//		}
+//
+//		// use mockedFacetedSearcher in code that requires entities.FacetedSearcher
+//		// and then make assertions.
+//
+//	}
+type FacetedSearcherMock struct {
+	// QueryFunc mocks the Query method.
+	QueryFunc func(ctx context.Context, query entities.ElasticSearchQuery) (entities.ElasticSearchResult, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Query holds details about calls to the Query method.
+		Query []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Query is the query argument value.
+			Query entities.ElasticSearchQuery
+		}
+	}
+	lockQuery sync.RWMutex
+}
+
+// Query calls QueryFunc.
+func (mock *FacetedSearcherMock) Query(ctx context.Context, query entities.ElasticSearchQuery) (entities.ElasticSearchResult, error) {
+	if mock.QueryFunc == nil {
+		panic("FacetedSearcherMock.QueryFunc: method is nil but FacetedSearcher.Query was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Query entities.ElasticSearchQuery
+	}{
+		Ctx:   ctx,
+		Query: query,
+	}
+	mock.lockQuery.Lock()
+	mock.calls.Query = append(mock.calls.Query, callInfo)
+	mock.lockQuery.Unlock()
+	return mock.QueryFunc(ctx, query)
+}
+
+// QueryCalls gets all the calls that were made to Query.
+// Check the length with:
+//
+//	len(mockedFacetedSearcher.QueryCalls())
+func (mock *FacetedSearcherMock) QueryCalls() []struct {
+	Ctx   context.Context
+	Query entities.ElasticSearchQuery
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Query entities.ElasticSearchQuery
+	}
+	mock.lockQuery.RLock()
+	calls = mock.calls.Query
+	mock.lockQuery.RUnlock()
+	return calls
+}
@@ -25,6 +25,9 @@ var _ entities.RelationshipsRepository = &RelationshipsRepositoryMock{}
 //			AddKilledFunc: func(ctx context.Context, characterID int, characterKilledId int) error {
 //				panic("mock out the AddKilled method")
 //			},
+//			AddKilledByFunc: func(ctx context.Context, characterID int, characterKillerId int) error {
+//				panic("mock out the AddKilledBy method")
+//			},
 //			AddMarriedEngagedFunc: func(ctx context.Context, characterID int, characterMarriedEngagedId int) error {
 //				panic("mock out the AddMarriedEngaged method")
 //			},
@@ -50,6 +53,9 @@ type RelationshipsRepositoryMock struct {
 	// AddKilledFunc mocks the AddKilled method.
 	AddKilledFunc func(ctx context.Context, characterID int, characterKilledId int) error
 
+	// AddKilledByFunc mocks the AddKilledBy method.
+	AddKilledByFunc func(ctx context.Context, characterID int, characterKillerId int) error
+
 	// AddMarriedEngagedFunc mocks the AddMarriedEngaged method.
 	AddMarriedEngagedFunc func(ctx context.Context, characterID int, characterMarriedEngagedId int) error
 
@@ -80,6 +86,15 @@ type RelationshipsRepositoryMock struct {
 			// CharacterKilledId is the characterKilledId argument value.
 			CharacterKilledId int
 		}
+		// AddKilledBy holds details about calls to the AddKilledBy method.
+		AddKilledBy []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// CharacterID is the characterID argument value.
+			CharacterID int
+			// CharacterKillerId is the characterKillerId argument value.
+			CharacterKillerId int
+		}
 		// AddMarriedEngaged holds details about calls to the AddMarriedEngaged method.
 		AddMarriedEngaged []struct {
 			// Ctx is the ctx argument value.
@@ -117,6 +132,7 @@ type RelationshipsRepositoryMock struct {
 	}
 	lockAddAll            sync.RWMutex
 	lockAddKilled         sync.RWMutex
+	lockAddKilledBy       sync.RWMutex
 	lockAddMarriedEngaged sync.RWMutex
 	lockAddParent         sync.RWMutex
 	lockAddSibling        sync.RWMutex
@@ -199,6 +215,46 @@ func (mock *RelationshipsRepositoryMock) AddKilledCalls() []struct {
 	return calls
 }
 
+// AddKilledBy calls AddKilledByFunc.
+func (mock *RelationshipsRepositoryMock) AddKilledBy(ctx context.Context, characterID int, characterKillerId int) error {
+	if mock.AddKilledByFunc == nil {
+		panic("RelationshipsRepositoryMock.AddKilledByFunc: method is nil but RelationshipsRepository.AddKilledBy was just called")
+	}
+	callInfo := struct {
+		Ctx               context.Context
+		CharacterID       int
+		CharacterKillerId int
+	}{
+		Ctx:               ctx,
+		CharacterID:       characterID,
+		CharacterKillerId: characterKillerId,
+	}
+	mock.lockAddKilledBy.Lock()
+	mock.calls.AddKilledBy = append(mock.calls.AddKilledBy, callInfo)
+	mock.lockAddKilledBy.Unlock()
+	return mock.AddKilledByFunc(ctx, characterID, characterKillerId)
+}
+
+// AddKilledByCalls gets all the calls that were made to AddKilledBy.
+// Check the length with:
+//
+//	len(mockedRelationshipsRepository.AddKilledByCalls())
+func (mock *RelationshipsRepositoryMock) AddKilledByCalls() []struct {
+	Ctx               context.Context
+	CharacterID       int
+	CharacterKillerId int
+} {
+	var calls []struct {
+		Ctx               context.Context
+		CharacterID       int
+		CharacterKillerId int
+	}
+	mock.lockAddKilledBy.RLock()
+	calls = mock.calls.AddKilledBy
+	mock.lockAddKilledBy.RUnlock()
+	return calls
+}
+
 // AddMarriedEngaged calls AddMarriedEngagedFunc.
 func (mock *RelationshipsRepositoryMock) AddMarriedEngaged(ctx context.Context, characterID int, characterMarriedEngagedId int) error {
 	if mock.AddMarriedEngagedFunc == nil {
@@ -0,0 +1,194 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mocks
+
+import (
+	"context"
+	"github.com/vitalii-komenda/got/entities"
+	"sync"
+)
+
+// Ensure, that SearchOutboxMock does implement entities.SearchOutbox.
+// If this is not the case, regenerate this file with moq.
+var _ entities.SearchOutbox = &SearchOutboxMock{}
+
+// SearchOutboxMock is a mock implementation of entities.SearchOutbox.
+//
+//	func TestSomethingThatUsesSearchOutbox(t *testing.T) {
+//
+//		// make and configure a mocked entities.SearchOutbox
+//		mockedSearchOutbox := &SearchOutboxMock{
+//			DoneFunc: func(ctx context.Context, eventIDs []int64) error {
+//				panic("mock out the Done method")
+//			},
+//			FailedFunc: func(ctx context.Context, eventIDs []int64, reason string) error {
+//				panic("mock out the Failed method")
+//			},
+//			PendingFunc: func(ctx context.Context, limit int, maxAttempts int) ([]entities.OutboxEvent, error) {
+//				panic("mock out the Pending method")
+//			},
+//		}
+//
+//		// use mockedSearchOutbox in code that requires entities.SearchOutbox
+//		// and then make assertions.
+//
+//	}
+type SearchOutboxMock struct {
+	// DoneFunc mocks the Done method.
+	DoneFunc func(ctx context.Context, eventIDs []int64) error
+
+	// FailedFunc mocks the Failed method.
+	FailedFunc func(ctx context.Context, eventIDs []int64, reason string) error
+
+	// PendingFunc mocks the Pending method.
+	PendingFunc func(ctx context.Context, limit int, maxAttempts int) ([]entities.OutboxEvent, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Done holds details about calls to the Done method.
+		Done []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventIDs is the eventIDs argument value.
+			EventIDs []int64
+		}
+		// Failed holds details about calls to the Failed method.
+		Failed []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventIDs is the eventIDs argument value.
+			EventIDs []int64
+			// Reason is the reason argument value.
+			Reason string
+		}
+		// Pending holds details about calls to the Pending method.
+		Pending []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Limit is the limit argument value.
+			Limit int
+			// MaxAttempts is the maxAttempts argument value.
+			MaxAttempts int
+		}
+	}
+	lockDone    sync.RWMutex
+	lockFailed  sync.RWMutex
+	lockPending sync.RWMutex
+}
+
+// Done calls DoneFunc.
+func (mock *SearchOutboxMock) Done(ctx context.Context, eventIDs []int64) error {
+	if mock.DoneFunc == nil {
+		panic("SearchOutboxMock.DoneFunc: method is nil but SearchOutbox.Done was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		EventIDs []int64
+	}{
+		Ctx:      ctx,
+		EventIDs: eventIDs,
+	}
+	mock.lockDone.Lock()
+	mock.calls.Done = append(mock.calls.Done, callInfo)
+	mock.lockDone.Unlock()
+	return mock.DoneFunc(ctx, eventIDs)
+}
+
+// DoneCalls gets all the calls that were made to Done.
+// Check the length with:
+//
+//	len(mockedSearchOutbox.DoneCalls())
+func (mock *SearchOutboxMock) DoneCalls() []struct {
+	Ctx      context.Context
+	EventIDs []int64
+} {
+	var calls []struct {
+		Ctx      context.Context
+		EventIDs []int64
+	}
+	mock.lockDone.RLock()
+	calls = mock.calls.Done
+	mock.lockDone.RUnlock()
+	return calls
+}
+
+// Failed calls FailedFunc.
+func (mock *SearchOutboxMock) Failed(ctx context.Context, eventIDs []int64, reason string) error {
+	if mock.FailedFunc == nil {
+		panic("SearchOutboxMock.FailedFunc: method is nil but SearchOutbox.Failed was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		EventIDs []int64
+		Reason   string
+	}{
+		Ctx:      ctx,
+		EventIDs: eventIDs,
+		Reason:   reason,
+	}
+	mock.lockFailed.Lock()
+	mock.calls.Failed = append(mock.calls.Failed, callInfo)
+	mock.lockFailed.Unlock()
+	return mock.FailedFunc(ctx, eventIDs, reason)
+}
+
+// FailedCalls gets all the calls that were made to Failed.
+// Check the length with:
+//
+//	len(mockedSearchOutbox.FailedCalls())
+func (mock *SearchOutboxMock) FailedCalls() []struct {
+	Ctx      context.Context
+	EventIDs []int64
+	Reason   string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		EventIDs []int64
+		Reason   string
+	}
+	mock.lockFailed.RLock()
+	calls = mock.calls.Failed
+	mock.lockFailed.RUnlock()
+	return calls
+}
+
+// Pending calls PendingFunc.
+func (mock *SearchOutboxMock) Pending(ctx context.Context, limit int, maxAttempts int) ([]entities.OutboxEvent, error) {
+	if mock.PendingFunc == nil {
+		panic("SearchOutboxMock.PendingFunc: method is nil but SearchOutbox.Pending was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		Limit       int
+		MaxAttempts int
+	}{
+		Ctx:         ctx,
+		Limit:       limit,
+		MaxAttempts: maxAttempts,
+	}
+	mock.lockPending.Lock()
+	mock.calls.Pending = append(mock.calls.Pending, callInfo)
+	mock.lockPending.Unlock()
+	return mock.PendingFunc(ctx, limit, maxAttempts)
+}
+
+// PendingCalls gets all the calls that were made to Pending.
+// Check the length with:
+//
+//	len(mockedSearchOutbox.PendingCalls())
+func (mock *SearchOutboxMock) PendingCalls() []struct {
+	Ctx         context.Context
+	Limit       int
+	MaxAttempts int
+} {
+	var calls []struct {
+		Ctx         context.Context
+		Limit       int
+		MaxAttempts int
+	}
+	mock.lockPending.RLock()
+	calls = mock.calls.Pending
+	mock.lockPending.RUnlock()
+	return calls
+}
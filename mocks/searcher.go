@@ -0,0 +1,82 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mocks
+
+import (
+	"context"
+	"github.com/vitalii-komenda/got/entities"
+	"sync"
+)
+
+// Ensure, that SearcherMock does implement entities.Searcher.
+// If this is not the case, regenerate this file with moq.
+var _ entities.Searcher = &SearcherMock{}
+
+// SearcherMock is a mock implementation of entities.Searcher.
+//
+//	func TestSomethingThatUsesSearcher(t *testing.T) {
+//
+//		// make and configure a mocked entities.Searcher
+//		mockedSearcher := &SearcherMock{
+//			SearchFunc: func(ctx context.Context, query entities.SearchQuery) ([]entities.CharacterEntry, error) {
+//				panic("mock out the Search method")
+//			},
+//		}
+//
+//		// use mockedSearcher in code that requires entities.Searcher
+//		// and then make assertions.
+//
+//	}
+type SearcherMock struct {
+	// SearchFunc mocks the Search method.
+	SearchFunc func(ctx context.Context, query entities.SearchQuery) ([]entities.CharacterEntry, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Search holds details about calls to the Search method.
+		Search []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Query is the query argument value.
+			Query entities.SearchQuery
+		}
+	}
+	lockSearch sync.RWMutex
+}
+
+// Search calls SearchFunc.
+func (mock *SearcherMock) Search(ctx context.Context, query entities.SearchQuery) ([]entities.CharacterEntry, error) {
+	if mock.SearchFunc == nil {
+		panic("SearcherMock.SearchFunc: method is nil but Searcher.Search was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Query entities.SearchQuery
+	}{
+		Ctx:   ctx,
+		Query: query,
+	}
+	mock.lockSearch.Lock()
+	mock.calls.Search = append(mock.calls.Search, callInfo)
+	mock.lockSearch.Unlock()
+	return mock.SearchFunc(ctx, query)
+}
+
+// SearchCalls gets all the calls that were made to Search.
+// Check the length with:
+//
+//	len(mockedSearcher.SearchCalls())
+func (mock *SearcherMock) SearchCalls() []struct {
+	Ctx   context.Context
+	Query entities.SearchQuery
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Query entities.SearchQuery
+	}
+	mock.lockSearch.RLock()
+	calls = mock.calls.Search
+	mock.lockSearch.RUnlock()
+	return calls
+}
@@ -0,0 +1,88 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mocks
+
+import (
+	"context"
+	"github.com/vitalii-komenda/got/entities"
+	"sync"
+)
+
+// Ensure, that SimilarFinderMock does implement entities.SimilarFinder.
+// If this is not the case, regenerate this file with moq.
+var _ entities.SimilarFinder = &SimilarFinderMock{}
+
+// SimilarFinderMock is a mock implementation of entities.SimilarFinder.
+//
+//	func TestSomethingThatUsesSimilarFinder(t *testing.T) {
+//
+//		// make and configure a mocked entities.SimilarFinder
+//		mockedSimilarFinder := &SimilarFinderMock{
+//			SimilarFunc: func(ctx context.Context, characterID int, size int) ([]entities.SimilarCharacter, error) {
+//				panic("mock out the Similar method")
+//			},
+//		}
+//
+//		// use mockedSimilarFinder in code that requires entities.SimilarFinder
+//		// and then make assertions.
+//
+//	}
+type SimilarFinderMock struct {
+	// SimilarFunc mocks the Similar method.
+	SimilarFunc func(ctx context.Context, characterID int, size int) ([]entities.SimilarCharacter, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Similar holds details about calls to the Similar method.
+		Similar []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// CharacterID is the characterID argument value.
+			CharacterID int
+			// Size is the size argument value.
+			Size int
+		}
+	}
+	lockSimilar sync.RWMutex
+}
+
+// Similar calls SimilarFunc.
+func (mock *SimilarFinderMock) Similar(ctx context.Context, characterID int, size int) ([]entities.SimilarCharacter, error) {
+	if mock.SimilarFunc == nil {
+		panic("SimilarFinderMock.SimilarFunc: method is nil but SimilarFinder.Similar was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		CharacterID int
+		Size        int
+	}{
+		Ctx:         ctx,
+		CharacterID: characterID,
+		Size:        size,
+	}
+	mock.lockSimilar.Lock()
+	mock.calls.Similar = append(mock.calls.Similar, callInfo)
+	mock.lockSimilar.Unlock()
+	return mock.SimilarFunc(ctx, characterID, size)
+}
+
+// SimilarCalls gets all the calls that were made to Similar.
+// Check the length with:
+//
+//	len(mockedSimilarFinder.SimilarCalls())
+func (mock *SimilarFinderMock) SimilarCalls() []struct {
+	Ctx         context.Context
+	CharacterID int
+	Size        int
+} {
+	var calls []struct {
+		Ctx         context.Context
+		CharacterID int
+		Size        int
+	}
+	mock.lockSimilar.RLock()
+	calls = mock.calls.Similar
+	mock.lockSimilar.RUnlock()
+	return calls
+}
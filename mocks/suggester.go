@@ -0,0 +1,88 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mocks
+
+import (
+	"context"
+	"github.com/vitalii-komenda/got/entities"
+	"sync"
+)
+
+// Ensure, that SuggesterMock does implement entities.Suggester.
+// If this is not the case, regenerate this file with moq.
+var _ entities.Suggester = &SuggesterMock{}
+
+// SuggesterMock is a mock implementation of entities.Suggester.
+//
+//	func TestSomethingThatUsesSuggester(t *testing.T) {
+//
+//		// make and configure a mocked entities.Suggester
+//		mockedSuggester := &SuggesterMock{
+//			SuggestFunc: func(ctx context.Context, prefix string, size int) ([]entities.Suggestion, error) {
+//				panic("mock out the Suggest method")
+//			},
+//		}
+//
+//		// use mockedSuggester in code that requires entities.Suggester
+//		// and then make assertions.
+//
+//	}
+type SuggesterMock struct {
+	// SuggestFunc mocks the Suggest method.
+	SuggestFunc func(ctx context.Context, prefix string, size int) ([]entities.Suggestion, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Suggest holds details about calls to the Suggest method.
+		Suggest []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Prefix is the prefix argument value.
+			Prefix string
+			// Size is the size argument value.
+			Size int
+		}
+	}
+	lockSuggest sync.RWMutex
+}
+
+// Suggest calls SuggestFunc.
+func (mock *SuggesterMock) Suggest(ctx context.Context, prefix string, size int) ([]entities.Suggestion, error) {
+	if mock.SuggestFunc == nil {
+		panic("SuggesterMock.SuggestFunc: method is nil but Suggester.Suggest was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Prefix string
+		Size   int
+	}{
+		Ctx:    ctx,
+		Prefix: prefix,
+		Size:   size,
+	}
+	mock.lockSuggest.Lock()
+	mock.calls.Suggest = append(mock.calls.Suggest, callInfo)
+	mock.lockSuggest.Unlock()
+	return mock.SuggestFunc(ctx, prefix, size)
+}
+
+// SuggestCalls gets all the calls that were made to Suggest.
+// Check the length with:
+//
+//	len(mockedSuggester.SuggestCalls())
+func (mock *SuggesterMock) SuggestCalls() []struct {
+	Ctx    context.Context
+	Prefix string
+	Size   int
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Prefix string
+		Size   int
+	}
+	mock.lockSuggest.RLock()
+	calls = mock.calls.Suggest
+	mock.lockSuggest.RUnlock()
+	return calls
+}
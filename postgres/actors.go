@@ -2,10 +2,13 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/lib/pq"
 	entities "github.com/vitalii-komenda/got/entities"
 )
 
@@ -51,7 +54,7 @@ func (r *ActorsRepository) Create(ctx context.Context, actorName string, actorLi
 	var id int
 	err = r.getExecutor().QueryRow(ctx, sql, args...).Scan(&id)
 	if err != nil {
-		return 0, fmt.Errorf("%w: %v", ErrActorsRepoPersistenceFailure, err)
+		return 0, fmt.Errorf("%w: %w", ErrActorsRepoPersistenceFailure, toDomainError(err))
 	}
 	return id, nil
 }
@@ -68,24 +71,35 @@ func (r *ActorsRepository) GetActorID(ctx context.Context, actorName string) (in
 	row := r.getExecutor().QueryRow(ctx, sql, args...)
 	var actorId int
 	err = row.Scan(&actorId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, entities.NewNotFoundError("actor %q not found", actorName)
+	}
 	if err != nil {
-		return 0, fmt.Errorf("error scanning row: %w", err)
+		return 0, fmt.Errorf("error scanning row: %w", toDomainError(err))
 	}
 	return actorId, nil
 }
 
-func (r *ActorsRepository) LinkActorToCharacter(ctx context.Context, actorId int, characterId int) error {
+// LinkActorToCharacter links the actor to the character. Linking an
+// already linked actor merges in the given seasons.
+func (r *ActorsRepository) LinkActorToCharacter(ctx context.Context, actorId int, characterId int, seasonsActive []int) error {
+	if seasonsActive == nil {
+		seasonsActive = []int{}
+	}
 	sql, args, err := Psql.
 		Insert("characters_actors").
-		Columns("actor_id", "character_id").
-		Values(actorId, characterId).
+		Columns("actor_id", "character_id", "seasons_active").
+		Values(actorId, characterId, seasonsActive).
+		Suffix(`ON CONFLICT (character_id, actor_id) DO UPDATE SET seasons_active = ARRAY(
+			SELECT DISTINCT season FROM unnest(characters_actors.seasons_active || EXCLUDED.seasons_active) AS season ORDER BY season
+		)`).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("%w: %v", ErrCharacterRepoPersistenceFailure, err)
 	}
 	_, err = r.getExecutor().Exec(ctx, sql, args...)
 	if err != nil {
-		return fmt.Errorf("%w: %v", ErrCharacterRepoPersistenceFailure, err)
+		return fmt.Errorf("%w: %w", ErrCharacterRepoPersistenceFailure, toDomainError(err))
 	}
 	return nil
 }
@@ -100,7 +114,362 @@ func (r *ActorsRepository) UnlinkActorFromCharacter(ctx context.Context, charact
 	}
 	_, err = r.getExecutor().Exec(ctx, sql, args...)
 	if err != nil {
-		return fmt.Errorf("%w: %v", ErrCharacterRepoPersistenceFailure, err)
+		return fmt.Errorf("%w: %w", ErrCharacterRepoPersistenceFailure, toDomainError(err))
 	}
 	return nil
 }
+
+// Get finds an actor by name, ignoring case, accents and punctuation, so
+// slugs such as "kit-harington" resolve too.
+func (r *ActorsRepository) Get(ctx context.Context, actorName string) (entities.ActorEntry, error) {
+	sql, args, err := actorsQuery().
+		Where(sq.Or{
+			sq.Eq{"a.actor_name": actorName},
+			sq.Expr("a.actor_name_normalized = normalize_name(?)", actorName),
+		}).
+		OrderByClause("(a.actor_name = ?) DESC, a.actor_id", actorName).
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return entities.ActorEntry{}, fmt.Errorf("error building sql: %w", err)
+	}
+	actors, err := r.queryActors(ctx, sql, args)
+	if err != nil {
+		return entities.ActorEntry{}, err
+	}
+	if len(actors) == 0 {
+		return entities.ActorEntry{}, entities.NewNotFoundError("actor %q not found", actorName)
+	}
+	return actors[0], nil
+}
+
+// GetAll lists actors by name, 25 per page.
+func (r *ActorsRepository) GetAll(ctx context.Context, filter entities.ActorFilter, page int) ([]entities.ActorEntry, error) {
+	query := actorsQuery()
+	if filter.Name != "" {
+		query = query.Where("a.actor_name_normalized LIKE '%' || normalize_name(?) || '%'", filter.Name)
+	}
+	if filter.House != "" {
+		query = query.Where(`EXISTS(
+			SELECT 1
+			FROM characters_actors AS hca
+			JOIN characters AS hc ON hc.character_id = hca.character_id
+			CROSS JOIN unnest(string_to_array(hc.house_name, ',')) AS house
+			WHERE hca.actor_id = a.actor_id AND normalize_name(house) = normalize_name(?)
+		)`, filter.House)
+	}
+	if filter.Season != 0 {
+		query = query.Where(`EXISTS(
+			SELECT 1 FROM characters_actors AS sca
+			WHERE sca.actor_id = a.actor_id AND ? = ANY(sca.seasons_active)
+		)`, filter.Season)
+	}
+	sql, args, err := query.
+		OrderBy("a.actor_name").
+		Limit(25).
+		Offset(uint64(page) * 25).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building sql: %w", err)
+	}
+	return r.queryActors(ctx, sql, args)
+}
+
+// GetRoles lists the characters the actor played and in which seasons,
+// optionally only those played in the given season.
+func (r *ActorsRepository) GetRoles(ctx context.Context, actorID int, season int, page int) ([]entities.ActorRole, error) {
+	query := Psql.
+		Select(`
+			c.character_id,
+			c.character_name,
+			c.slug,
+			COALESCE(string_to_array(c.house_name, ','), '{}') AS house_name,
+			ca.seasons_active
+		`).
+		From("characters_actors AS ca").
+		Join("characters AS c ON c.character_id = ca.character_id").
+		Where("ca.actor_id = ?", actorID)
+	if season != 0 {
+		query = query.Where("? = ANY(ca.seasons_active)", season)
+	}
+	sql, args, err := query.
+		OrderBy("c.character_name").
+		Limit(25).
+		Offset(uint64(page) * 25).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building sql: %w", err)
+	}
+
+	rows, err := r.getExecutor().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing query: %w", toDomainError(err))
+	}
+	defer rows.Close()
+
+	roles := []entities.ActorRole{}
+	for rows.Next() {
+		var role entities.ActorRole
+		err := rows.Scan(
+			&role.CharacterID,
+			&role.CharacterName,
+			&role.Slug,
+			pq.Array(&role.HouseName),
+			&role.SeasonsActive,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", toDomainError(err))
+		}
+		roles = append(roles, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading rows: %w", toDomainError(err))
+	}
+	return roles, nil
+}
+
+// actorsQuery selects actors with every season they appear in and the
+// number of characters they played.
+func actorsQuery() sq.SelectBuilder {
+	return Psql.
+		Select(`
+			a.actor_id,
+			a.actor_name,
+			COALESCE(a.actor_link, '') AS actor_link,
+			COALESCE((
+				SELECT array_agg(DISTINCT season ORDER BY season)
+				FROM characters_actors AS sca
+				CROSS JOIN unnest(sca.seasons_active) AS season
+				WHERE sca.actor_id = a.actor_id
+			), '{}') AS seasons_active,
+			(SELECT COUNT(*) FROM characters_actors AS cca WHERE cca.actor_id = a.actor_id) AS characters
+		`).
+		From("actors AS a")
+}
+
+func (r *ActorsRepository) queryActors(ctx context.Context, sql string, args []interface{}) ([]entities.ActorEntry, error) {
+	rows, err := r.getExecutor().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing query: %w", toDomainError(err))
+	}
+	defer rows.Close()
+
+	actors := []entities.ActorEntry{}
+	for rows.Next() {
+		var a entities.ActorEntry
+		err := rows.Scan(
+			&a.ActorID,
+			&a.ActorName,
+			&a.ActorLink,
+			&a.SeasonsActive,
+			&a.Characters,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", toDomainError(err))
+		}
+		actors = append(actors, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading rows: %w", toDomainError(err))
+	}
+	return actors, nil
+}
+
+// GetOrphans lists actors no character links to anymore.
+func (r *ActorsRepository) GetOrphans(ctx context.Context) ([]entities.ActorEntry, error) {
+	sql, args, err := actorsQuery().
+		Where("NOT EXISTS(SELECT 1 FROM characters_actors AS oca WHERE oca.actor_id = a.actor_id)").
+		OrderBy("a.actor_name").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building sql: %w", err)
+	}
+	return r.queryActors(ctx, sql, args)
+}
+
+// DeleteOrphans deletes the given actors when no character links to them
+// anymore, or every such actor when no IDs are given, and returns the
+// names of the deleted actors.
+func (r *ActorsRepository) DeleteOrphans(ctx context.Context, actorIDs ...int) ([]string, error) {
+	query := Psql.
+		Delete("actors AS a").
+		Where("NOT EXISTS(SELECT 1 FROM characters_actors AS oca WHERE oca.actor_id = a.actor_id)").
+		Suffix("RETURNING a.actor_name")
+	if len(actorIDs) > 0 {
+		query = query.Where(sq.Eq{"a.actor_id": actorIDs})
+	}
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrActorsRepoPersistenceFailure, err)
+	}
+
+	rows, err := r.getExecutor().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrActorsRepoPersistenceFailure, toDomainError(err))
+	}
+	defer rows.Close()
+
+	deleted := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrActorsRepoPersistenceFailure, toDomainError(err))
+		}
+		deleted = append(deleted, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrActorsRepoPersistenceFailure, toDomainError(err))
+	}
+	return deleted, nil
+}
+
+// FindDuplicates groups actors whose names differ only in case, accents or
+// punctuation. Each group starts with the actor to keep: the one playing
+// the most characters, then the oldest.
+func (r *ActorsRepository) FindDuplicates(ctx context.Context) ([][]entities.ActorEntry, error) {
+	sql, args, err := actorsQuery().
+		Column("a.actor_name_normalized").
+		Where(`a.actor_name_normalized IN (
+			SELECT actor_name_normalized FROM actors
+			GROUP BY actor_name_normalized
+			HAVING COUNT(*) > 1
+		)`).
+		OrderBy("a.actor_name_normalized", "characters DESC", "a.actor_id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building sql: %w", err)
+	}
+
+	rows, err := r.getExecutor().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing query: %w", toDomainError(err))
+	}
+	defer rows.Close()
+
+	groups := [][]entities.ActorEntry{}
+	var previous string
+	for rows.Next() {
+		var a entities.ActorEntry
+		var normalized string
+		err := rows.Scan(
+			&a.ActorID,
+			&a.ActorName,
+			&a.ActorLink,
+			&a.SeasonsActive,
+			&a.Characters,
+			&normalized,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", toDomainError(err))
+		}
+		if len(groups) == 0 || normalized != previous {
+			groups = append(groups, []entities.ActorEntry{})
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], a)
+		previous = normalized
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading rows: %w", toDomainError(err))
+	}
+	return groups, nil
+}
+
+// Merge moves every role of the duplicate actor to the kept one, merging
+// seasons of characters both played, fills in a missing link and deletes
+// the duplicate. Run it in a transaction.
+func (r *ActorsRepository) Merge(ctx context.Context, keepID int, duplicateID int) error {
+	if keepID == duplicateID {
+		return entities.NewValidationError("cannot merge actor %d into itself", keepID)
+	}
+
+	sql, args, err := Psql.
+		Update("actors AS a").
+		Set("actor_link", sq.Expr("COALESCE(NULLIF(a.actor_link, ''), d.actor_link)")).
+		From("actors AS d").
+		Where("a.actor_id = ? AND d.actor_id = ?", keepID, duplicateID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrActorsRepoPersistenceFailure, err)
+	}
+	tag, err := r.getExecutor().Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrActorsRepoPersistenceFailure, toDomainError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return entities.NewNotFoundError("actors %d and %d must both exist", keepID, duplicateID)
+	}
+
+	sql, args, err = Psql.
+		Insert("characters_actors").
+		Columns("actor_id", "character_id", "seasons_active").
+		Select(Psql.
+			Select().
+			Column(sq.Expr("?::INT", keepID)).
+			Columns("character_id", "seasons_active").
+			From("characters_actors").
+			Where("actor_id = ?", duplicateID)).
+		Suffix(`ON CONFLICT (character_id, actor_id) DO UPDATE SET seasons_active = ARRAY(
+			SELECT DISTINCT season FROM unnest(characters_actors.seasons_active || EXCLUDED.seasons_active) AS season ORDER BY season
+		)`).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrActorsRepoPersistenceFailure, err)
+	}
+	_, err = r.getExecutor().Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrActorsRepoPersistenceFailure, toDomainError(err))
+	}
+
+	sql, args, err = Psql.
+		Delete("actors").
+		Where("actor_id = ?", duplicateID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrActorsRepoPersistenceFailure, err)
+	}
+	_, err = r.getExecutor().Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrActorsRepoPersistenceFailure, toDomainError(err))
+	}
+
+	characterIDs, err := r.characterIDs(ctx, keepID)
+	if err != nil {
+		return err
+	}
+	for _, characterID := range characterIDs {
+		err = enqueueSearchUpdate(ctx, r.getExecutor(), characterID, entities.OutboxUpsert)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ActorsRepository) characterIDs(ctx context.Context, actorID int) ([]int, error) {
+	sql, args, err := Psql.
+		Select("character_id").
+		From("characters_actors").
+		Where("actor_id = ?", actorID).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrActorsRepoPersistenceFailure, err)
+	}
+	rows, err := r.getExecutor().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrActorsRepoPersistenceFailure, toDomainError(err))
+	}
+	defer rows.Close()
+
+	var characterIDs []int
+	for rows.Next() {
+		var characterID int
+		if err := rows.Scan(&characterID); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrActorsRepoPersistenceFailure, toDomainError(err))
+		}
+		characterIDs = append(characterIDs, characterID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrActorsRepoPersistenceFailure, toDomainError(err))
+	}
+	return characterIDs, nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/stretchr/testify/suite"
+	"github.com/vitalii-komenda/got/entities"
 )
 
 type ActorsTestSuite struct {
@@ -41,6 +42,7 @@ func (s *ActorsTestSuite) SetupTest() {
 	 (
 		character_id,
 		character_name,
+		slug,
 		house_name,
 		character_image_thumb,
 		character_image_full,
@@ -51,6 +53,7 @@ func (s *ActorsTestSuite) SetupTest() {
 	 VALUES (
 	 1,
 	 'Test Character',
+	 'test-character',
 	 'Test House',
 	 'http://test.com/thumb',
 	 'http://test.com/full',
@@ -99,7 +102,7 @@ func (s *ActorsTestSuite) TestLinkActorToCharacter() {
 	actorId, err := s.repo.Create(ctx, actorName, actorLink)
 	s.Require().NoError(err)
 
-	err = s.repo.LinkActorToCharacter(ctx, actorId, characterId)
+	err = s.repo.LinkActorToCharacter(ctx, actorId, characterId, []int{1, 2})
 	s.Require().NoError(err)
 
 	var exists bool
@@ -117,7 +120,7 @@ func (s *ActorsTestSuite) TestUnlinkActorFromCharacter() {
 	actorId, err := s.repo.Create(ctx, actorName, actorLink)
 	s.Require().NoError(err)
 
-	err = s.repo.LinkActorToCharacter(ctx, actorId, characterId)
+	err = s.repo.LinkActorToCharacter(ctx, actorId, characterId, []int{1, 2})
 	s.Require().NoError(err)
 
 	err = s.repo.UnlinkActorFromCharacter(ctx, characterId)
@@ -129,6 +132,97 @@ func (s *ActorsTestSuite) TestUnlinkActorFromCharacter() {
 	s.Require().False(exists)
 }
 
+func (s *ActorsTestSuite) TestGetRoles() {
+	ctx := context.Background()
+	characterId := 1
+
+	actorId, err := s.repo.Create(ctx, "Test Actor", "http://testactor.com")
+	s.Require().NoError(err)
+	err = s.repo.LinkActorToCharacter(ctx, actorId, characterId, []int{2, 1})
+	s.Require().NoError(err)
+	err = s.repo.LinkActorToCharacter(ctx, actorId, characterId, []int{3, 2})
+	s.Require().NoError(err)
+
+	actor, err := s.repo.Get(ctx, "test-actor")
+	s.Require().NoError(err)
+	s.Require().Equal(entities.ActorEntry{
+		ActorID:       actorId,
+		ActorName:     "Test Actor",
+		ActorLink:     "http://testactor.com",
+		SeasonsActive: []int{1, 2, 3},
+		Characters:    1,
+	}, actor)
+
+	actors, err := s.repo.GetAll(ctx, entities.ActorFilter{Name: "test", House: "test house", Season: 3}, 0)
+	s.Require().NoError(err)
+	s.Require().Len(actors, 1)
+
+	roles, err := s.repo.GetRoles(ctx, actorId, 1, 0)
+	s.Require().NoError(err)
+	s.Require().Equal([]entities.ActorRole{{
+		CharacterID:   characterId,
+		CharacterName: "Test Character",
+		Slug:          "test-character",
+		HouseName:     []string{"Test House"},
+		SeasonsActive: []int{1, 2, 3},
+	}}, roles)
+
+	roles, err = s.repo.GetRoles(ctx, actorId, 4, 0)
+	s.Require().NoError(err)
+	s.Require().Empty(roles)
+
+	_, err = s.repo.Get(ctx, "Nobody")
+	s.Require().ErrorIs(err, entities.ErrNotFound)
+}
+
+func (s *ActorsTestSuite) TestDeleteOrphans() {
+	ctx := context.Background()
+
+	linkedId, err := s.repo.Create(ctx, "Linked Actor", "")
+	s.Require().NoError(err)
+	err = s.repo.LinkActorToCharacter(ctx, linkedId, 1, nil)
+	s.Require().NoError(err)
+	orphanId, err := s.repo.Create(ctx, "Orphaned Actor", "")
+	s.Require().NoError(err)
+
+	deleted, err := s.repo.DeleteOrphans(ctx, linkedId, orphanId)
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"Orphaned Actor"}, deleted)
+
+	_, err = s.repo.GetActorID(ctx, "Linked Actor")
+	s.Require().NoError(err)
+}
+
+func (s *ActorsTestSuite) TestMerge() {
+	ctx := context.Background()
+
+	keepId, err := s.repo.Create(ctx, "Jaqen H'ghar Actor", "")
+	s.Require().NoError(err)
+	err = s.repo.LinkActorToCharacter(ctx, keepId, 1, []int{1})
+	s.Require().NoError(err)
+	duplicateId, err := s.repo.Create(ctx, "jaqen hghar actor", "http://test.com/actor")
+	s.Require().NoError(err)
+	err = s.repo.LinkActorToCharacter(ctx, duplicateId, 1, []int{2})
+	s.Require().NoError(err)
+
+	groups, err := s.repo.FindDuplicates(ctx)
+	s.Require().NoError(err)
+	s.Require().Len(groups, 1)
+	s.Require().Equal(keepId, groups[0][0].ActorID)
+
+	err = s.repo.Merge(ctx, keepId, duplicateId)
+	s.Require().NoError(err)
+
+	actor, err := s.repo.Get(ctx, "Jaqen H'ghar Actor")
+	s.Require().NoError(err)
+	s.Require().Equal(keepId, actor.ActorID)
+	s.Require().Equal("http://test.com/actor", actor.ActorLink)
+	s.Require().Equal([]int{1, 2}, actor.SeasonsActive)
+
+	_, err = s.repo.GetActorID(ctx, "jaqen hghar actor")
+	s.Require().ErrorIs(err, entities.ErrNotFound)
+}
+
 func TestRunActorsTestSuite(t *testing.T) {
 	suite.Run(t, &ActorsTestSuite{})
 }
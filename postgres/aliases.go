@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	entities "github.com/vitalii-komenda/got/entities"
+)
+
+// ReplaceAliases swaps the character's aliases for the given ones.
+func (r *CharactersRepository) ReplaceAliases(ctx context.Context, characterID int, aliases []entities.Alias) error {
+	for _, alias := range aliases {
+		if alias.Name == "" {
+			return entities.NewValidationError("alias name is required")
+		}
+		if !alias.Type.Valid() {
+			return entities.NewValidationError("alias type %q is not one of nickname, title, birth_name", alias.Type)
+		}
+	}
+
+	sql, args, err := Psql.
+		Delete("character_aliases").
+		Where("character_id = ?", characterID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCharacterRepoPersistenceFailure, err)
+	}
+	_, err = r.getExecutor().Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCharacterRepoPersistenceFailure, toDomainError(err))
+	}
+
+	if len(aliases) == 0 {
+		return nil
+	}
+
+	insert := Psql.
+		Insert("character_aliases").
+		Columns("character_id", "alias", "alias_type").
+		Suffix("ON CONFLICT (character_id, alias) DO NOTHING")
+	for _, alias := range aliases {
+		insert = insert.Values(characterID, alias.Name, string(alias.Type))
+	}
+	sql, args, err = insert.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCharacterRepoPersistenceFailure, err)
+	}
+	_, err = r.getExecutor().Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCharacterRepoPersistenceFailure, toDomainError(err))
+	}
+	return nil
+}
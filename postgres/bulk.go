@@ -0,0 +1,478 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	entities "github.com/vitalii-komenda/got/entities"
+)
+
+var ErrBulkLoadFailure = fmt.Errorf("bulk load failure")
+
+// BulkLoadReport tells what a bulk load wrote and how long it took.
+type BulkLoadReport struct {
+	Characters int
+	Created    int
+	// Updated counts the stored characters the dataset overwrote.
+	Updated int
+	// Actors counts the links of characters to actors.
+	Actors        int
+	Aliases       int
+	Relationships int
+	// Unresolved counts the relationships whose target matched no
+	// character or alias and were skipped.
+	Unresolved int
+	Duration   time.Duration
+}
+
+// CharactersPerSecond is the throughput of the load.
+func (r BulkLoadReport) CharactersPerSecond() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return float64(r.Characters) / r.Duration.Seconds()
+}
+
+func NewBulkLoader(dbpool *pgxpool.Pool) *BulkLoader {
+	return &BulkLoader{
+		dbPool: dbpool,
+	}
+}
+
+// BulkLoader loads whole datasets with a handful of statements instead of
+// a few per character: the rows are copied into temporary staging tables
+// and names are resolved to IDs with joins. Characters are matched by
+// normalized name, updated or created, and their aliases, actors and
+// relationships replaced, so loading the same data twice changes nothing.
+type BulkLoader struct {
+	dbPool *pgxpool.Pool
+	tx     *pgx.Tx
+}
+
+func (l *BulkLoader) WithTX(tx *pgx.Tx) *BulkLoader {
+	return &BulkLoader{
+		dbPool: l.dbPool,
+		tx:     tx,
+	}
+}
+
+func (l *BulkLoader) Load(ctx context.Context, characters []entities.CharacterEntry) (BulkLoadReport, error) {
+	var report BulkLoadReport
+	err := inTx(ctx, l.dbPool, l.tx, func(tx *pgx.Tx) error {
+		var err error
+		report, err = l.load(ctx, *tx, characters)
+		return err
+	})
+	return report, err
+}
+
+// stagingTables hold the dataset while it is resolved. position ties the
+// actors, aliases and relationships to the row of their character. They
+// are dropped first in case of an earlier load in the same transaction.
+const stagingTables = `
+DROP TABLE IF EXISTS import_characters, import_actors, import_aliases, import_relationships, import_replaced_actors;
+CREATE TEMPORARY TABLE import_characters (
+	position INT PRIMARY KEY,
+	character_name TEXT NOT NULL,
+	slug TEXT NOT NULL,
+	house_name TEXT NOT NULL,
+	character_image_thumb TEXT NOT NULL,
+	character_image_full TEXT NOT NULL,
+	character_link TEXT NOT NULL,
+	nickname TEXT NOT NULL,
+	royal BOOLEAN NOT NULL,
+	extra TEXT NOT NULL,
+	name_normalized TEXT,
+	character_id INT
+) ON COMMIT DROP;
+CREATE TEMPORARY TABLE import_actors (
+	position INT NOT NULL,
+	actor_name TEXT NOT NULL,
+	actor_link TEXT NOT NULL,
+	seasons_active INT[] NOT NULL,
+	actor_id INT
+) ON COMMIT DROP;
+CREATE TEMPORARY TABLE import_aliases (
+	position INT NOT NULL,
+	alias TEXT NOT NULL,
+	alias_type TEXT NOT NULL
+) ON COMMIT DROP;
+CREATE TEMPORARY TABLE import_relationships (
+	position INT NOT NULL,
+	related_name TEXT NOT NULL,
+	relationship_type TEXT NOT NULL,
+	related_id INT
+) ON COMMIT DROP;
+CREATE TEMPORARY TABLE import_replaced_actors (
+	actor_id INT NOT NULL
+) ON COMMIT DROP;
+`
+
+// resolveCharacters matches the staged characters to the stored ones by
+// normalized name, as GetCharacterID does.
+const resolveCharacters = `
+UPDATE import_characters SET name_normalized = normalize_name(character_name);
+CREATE INDEX ON import_characters (name_normalized);
+ANALYZE import_characters;
+
+UPDATE import_characters AS s
+SET character_id = c.character_id
+FROM characters AS c
+WHERE c.character_name_normalized = s.name_normalized;
+`
+
+// updateCharacters overwrites the stored characters with their first row
+// in the dataset, keeping their names and slugs.
+const updateCharacters = `
+UPDATE characters AS c
+SET house_name = s.house_name,
+	character_image_thumb = s.character_image_thumb,
+	character_image_full = s.character_image_full,
+	character_link = s.character_link,
+	nickname = s.nickname,
+	royal = s.royal,
+	extra = s.extra::jsonb,
+	updated_at = CURRENT_TIMESTAMP
+FROM (
+	SELECT DISTINCT ON (character_id) * FROM import_characters
+	WHERE character_id IS NOT NULL
+	ORDER BY character_id, position
+) AS s
+WHERE c.character_id = s.character_id
+`
+
+// newCharacters are the first rows of the characters to create, in
+// dataset order.
+const newCharacters = `
+SELECT position, slug FROM (
+	SELECT DISTINCT ON (name_normalized) position, slug FROM import_characters
+	WHERE character_id IS NULL
+	ORDER BY name_normalized, position
+) AS new
+ORDER BY position
+`
+
+// takenSlugs are the slugs, now or in the past, that collide with the bases
+// or their -2, -3 variants.
+const takenSlugs = `
+SELECT slug FROM characters
+WHERE slug = ANY($1) OR regexp_replace(slug, '-[0-9]+$', '') = ANY($1)
+UNION
+SELECT slug FROM character_slug_history
+WHERE slug = ANY($1) OR regexp_replace(slug, '-[0-9]+$', '') = ANY($1)
+`
+
+const setSlugs = `
+UPDATE import_characters AS s
+SET slug = v.slug
+FROM unnest($1::int[], $2::text[]) AS v(position, slug)
+WHERE s.position = v.position
+`
+
+// createCharacters inserts the others with the slugs assignSlugs picked.
+const createCharacters = `
+INSERT INTO characters (
+	character_name, slug, house_name, character_image_thumb,
+	character_image_full, character_link, nickname, royal, extra
+)
+SELECT character_name, slug, house_name, character_image_thumb,
+	character_image_full, character_link, nickname, royal, extra::jsonb
+FROM (
+	SELECT DISTINCT ON (name_normalized) * FROM import_characters
+	WHERE character_id IS NULL
+	ORDER BY name_normalized, position
+) AS new
+ORDER BY position
+`
+
+const resolveCreated = `
+UPDATE import_characters AS s
+SET character_id = c.character_id
+FROM characters AS c
+WHERE s.character_id IS NULL AND c.character_name_normalized = s.name_normalized
+`
+
+// replaceAliases, replaceActors and replaceRelationships swap what is
+// stored for the loaded characters with what the dataset says.
+const replaceAliases = `
+DELETE FROM character_aliases AS al
+USING import_characters AS s
+WHERE al.character_id = s.character_id;
+
+INSERT INTO character_aliases (character_id, alias, alias_type)
+SELECT DISTINCT ON (s.character_id, a.alias) s.character_id, a.alias, a.alias_type
+FROM import_aliases AS a
+JOIN import_characters AS s ON s.position = a.position
+ORDER BY s.character_id, a.alias, a.position;
+`
+
+const replaceActors = `
+INSERT INTO actors (actor_name, actor_link)
+SELECT DISTINCT ON (actor_name) actor_name, actor_link
+FROM import_actors
+ORDER BY actor_name, position
+ON CONFLICT (actor_name) DO NOTHING;
+
+UPDATE import_actors AS ia
+SET actor_id = a.actor_id
+FROM actors AS a
+WHERE a.actor_name = ia.actor_name;
+
+WITH replaced AS (
+	DELETE FROM characters_actors AS ca
+	USING import_characters AS s
+	WHERE ca.character_id = s.character_id
+	RETURNING ca.actor_id
+)
+INSERT INTO import_replaced_actors SELECT actor_id FROM replaced;
+
+INSERT INTO characters_actors (character_id, actor_id, seasons_active)
+SELECT DISTINCT ON (s.character_id, ia.actor_id) s.character_id, ia.actor_id, ia.seasons_active
+FROM import_actors AS ia
+JOIN import_characters AS s ON s.position = ia.position
+ORDER BY s.character_id, ia.actor_id, ia.position;
+`
+
+// deleteOrphans deletes the actors the loaded characters no longer have
+// and nobody else plays, as deleteOrphanedActors does after an update.
+const deleteOrphans = `
+DELETE FROM actors AS a
+USING (SELECT DISTINCT actor_id FROM import_replaced_actors) AS r
+WHERE a.actor_id = r.actor_id
+	AND NOT EXISTS(SELECT 1 FROM characters_actors AS oca WHERE oca.actor_id = a.actor_id)
+`
+
+// resolveRelationships finds the targets by exact name, then by normalized
+// name and then by alias, like GetCharacterID.
+const resolveRelationships = `
+UPDATE import_relationships AS r
+SET related_id = c.character_id
+FROM characters AS c
+WHERE c.character_name = r.related_name;
+
+UPDATE import_relationships AS r
+SET related_id = c.character_id
+FROM characters AS c
+WHERE r.related_id IS NULL AND c.character_name_normalized = normalize_name(r.related_name);
+
+UPDATE import_relationships AS r
+SET related_id = al.character_id
+FROM character_aliases AS al
+WHERE r.related_id IS NULL AND al.alias_normalized = normalize_name(r.related_name);
+`
+
+const replaceRelationships = `
+DELETE FROM relationships AS r
+USING import_characters AS s
+WHERE r.character_id = s.character_id;
+
+INSERT INTO relationships (character_id, character_relationship_id, relationship_type)
+SELECT DISTINCT s.character_id, r.related_id, r.relationship_type
+FROM import_relationships AS r
+JOIN import_characters AS s ON s.position = r.position
+WHERE r.related_id IS NOT NULL;
+`
+
+const enqueueLoaded = `
+SELECT refresh_search_documents(ARRAY(SELECT DISTINCT character_id FROM import_characters));
+INSERT INTO search_outbox (character_id, operation)
+SELECT DISTINCT character_id, 'upsert' FROM import_characters
+`
+
+func (l *BulkLoader) load(ctx context.Context, tx pgx.Tx, characters []entities.CharacterEntry) (BulkLoadReport, error) {
+	start := time.Now()
+	report := BulkLoadReport{}
+
+	if _, err := tx.Exec(ctx, stagingTables); err != nil {
+		return report, fmt.Errorf("create staging tables %w: %w", ErrBulkLoadFailure, toDomainError(err))
+	}
+	if err := copyStaging(ctx, tx, characters); err != nil {
+		return report, err
+	}
+
+	err := runSteps(ctx, tx, []bulkStep{
+		{"resolve characters", resolveCharacters, nil},
+		{"update characters", updateCharacters, &report.Updated},
+	})
+	if err != nil {
+		return report, err
+	}
+	if err := assignSlugs(ctx, tx); err != nil {
+		return report, err
+	}
+	err = runSteps(ctx, tx, []bulkStep{
+		{"create characters", createCharacters, &report.Created},
+		{"resolve created characters", resolveCreated, nil},
+		{"replace aliases", replaceAliases, &report.Aliases},
+		{"replace actors", replaceActors, &report.Actors},
+		{"delete orphaned actors", deleteOrphans, nil},
+		{"resolve relationships", resolveRelationships, nil},
+		{"replace relationships", replaceRelationships, &report.Relationships},
+		{"enqueue search updates", enqueueLoaded, &report.Characters},
+	})
+	if err != nil {
+		return report, err
+	}
+
+	err = tx.QueryRow(ctx, "SELECT count(*) FROM import_relationships WHERE related_id IS NULL").Scan(&report.Unresolved)
+	if err != nil {
+		return report, fmt.Errorf("count unresolved %w: %w", ErrBulkLoadFailure, toDomainError(err))
+	}
+	report.Duration = time.Since(start)
+	return report, nil
+}
+
+type bulkStep struct {
+	name  string
+	sql   string
+	count *int
+}
+
+func runSteps(ctx context.Context, tx pgx.Tx, steps []bulkStep) error {
+	for _, step := range steps {
+		// the count of a script is the one of its last statement
+		tag, err := tx.Exec(ctx, step.sql)
+		if err != nil {
+			return fmt.Errorf("%s %w: %w", step.name, ErrBulkLoadFailure, toDomainError(err))
+		}
+		if step.count != nil {
+			*step.count = int(tag.RowsAffected())
+		}
+	}
+	return nil
+}
+
+// assignSlugs gives the characters to create free slugs the way uniqueSlug
+// does, in dataset order: the slug of the name, else with -2, -3 and so on.
+// The slugs taken are loaded once rather than checked per candidate.
+func assignSlugs(ctx context.Context, tx pgx.Tx) error {
+	rows, err := tx.Query(ctx, newCharacters)
+	if err != nil {
+		return fmt.Errorf("new characters %w: %w", ErrBulkLoadFailure, toDomainError(err))
+	}
+	var positions []int32
+	var slugs []string
+	for rows.Next() {
+		var position int32
+		var slug string
+		if err := rows.Scan(&position, &slug); err != nil {
+			rows.Close()
+			return fmt.Errorf("new characters %w: %w", ErrBulkLoadFailure, toDomainError(err))
+		}
+		positions = append(positions, position)
+		slugs = append(slugs, slug)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("new characters %w: %w", ErrBulkLoadFailure, toDomainError(err))
+	}
+	if len(positions) == 0 {
+		return nil
+	}
+
+	taken := map[string]bool{}
+	rows, err = tx.Query(ctx, takenSlugs, slices.Compact(slices.Sorted(slices.Values(slugs))))
+	if err != nil {
+		return fmt.Errorf("taken slugs %w: %w", ErrBulkLoadFailure, toDomainError(err))
+	}
+	for rows.Next() {
+		var slug string
+		if err := rows.Scan(&slug); err != nil {
+			rows.Close()
+			return fmt.Errorf("taken slugs %w: %w", ErrBulkLoadFailure, toDomainError(err))
+		}
+		taken[slug] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("taken slugs %w: %w", ErrBulkLoadFailure, toDomainError(err))
+	}
+
+	for i := range slugs {
+		for n := 1; ; n++ {
+			if candidate := slugCandidate(slugs[i], n); !taken[candidate] {
+				slugs[i] = candidate
+				taken[candidate] = true
+				break
+			}
+		}
+	}
+
+	if _, err := tx.Exec(ctx, setSlugs, positions, slugs); err != nil {
+		return fmt.Errorf("set slugs %w: %w", ErrBulkLoadFailure, toDomainError(err))
+	}
+	return nil
+}
+
+// copyStaging copies the dataset into the staging tables.
+func copyStaging(ctx context.Context, tx pgx.Tx, characters []entities.CharacterEntry) error {
+	var characterRows, actorRows, aliasRows, relationshipRows [][]interface{}
+	for position, character := range characters {
+		slug := entities.Slugify(character.CharacterName)
+		if slug == "" {
+			slug = "character"
+		}
+		extra := "{}"
+		if len(character.Extra) > 0 {
+			data, err := json.Marshal(character.Extra)
+			if err != nil {
+				return fmt.Errorf("extra of %s %w: %w", character.CharacterName, ErrBulkLoadFailure, err)
+			}
+			extra = string(data)
+		}
+		characterRows = append(characterRows, []interface{}{
+			position, character.CharacterName, slug, strings.Join(character.HouseName, ","),
+			character.CharacterImageThumb, character.CharacterImageFull, character.CharacterLink,
+			character.Nickname, character.Royal, extra,
+		})
+
+		for _, actor := range character.AllActors() {
+			seasons := make([]int32, len(actor.SeasonsActive))
+			for i, season := range actor.SeasonsActive {
+				seasons[i] = int32(season)
+			}
+			actorRows = append(actorRows, []interface{}{position, actor.ActorName, actor.ActorLink, seasons})
+		}
+		for _, alias := range character.AllAliases() {
+			aliasRows = append(aliasRows, []interface{}{position, alias.Name, string(alias.Type)})
+		}
+		for relationshipType, names := range map[string][]string{
+			"parent":          character.Parents,
+			"sibling":         character.Siblings,
+			"killed":          character.Killed,
+			"killed_by":       character.KilledBy,
+			"married_engaged": character.MarriedEngaged,
+		} {
+			for _, name := range names {
+				relationshipRows = append(relationshipRows, []interface{}{position, name, relationshipType})
+			}
+		}
+	}
+
+	copies := []struct {
+		table   string
+		columns []string
+		rows    [][]interface{}
+	}{
+		{"import_characters", []string{
+			"position", "character_name", "slug", "house_name", "character_image_thumb",
+			"character_image_full", "character_link", "nickname", "royal", "extra",
+		}, characterRows},
+		{"import_actors", []string{"position", "actor_name", "actor_link", "seasons_active"}, actorRows},
+		{"import_aliases", []string{"position", "alias", "alias_type"}, aliasRows},
+		{"import_relationships", []string{"position", "related_name", "relationship_type"}, relationshipRows},
+	}
+	for _, c := range copies {
+		_, err := tx.CopyFrom(ctx, pgx.Identifier{c.table}, c.columns, pgx.CopyFromRows(c.rows))
+		if err != nil {
+			return fmt.Errorf("copy into %s %w: %w", c.table, ErrBulkLoadFailure, toDomainError(err))
+		}
+	}
+	return nil
+}
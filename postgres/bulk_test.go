@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/vitalii-komenda/got/entities"
+)
+
+func (s *CharsetTestSuite) TestBulkLoad() {
+	ctx := context.Background()
+	loader := NewBulkLoader(s.dbpool).WithTX(s.tx)
+	characters := []entities.CharacterEntry{
+		{CharacterName: "Test Character", HouseName: entities.HouseNameType{"Stark"}, Siblings: []string{"Arya Stark", "Nobody"}},
+		{CharacterName: "Arya Stark", Nickname: "Arry", ActorName: "Maisie Williams", Actors: []entities.ActorEntry{{ActorName: "Maisie Williams", SeasonsActive: []int{1, 2}}}},
+		{CharacterName: "Arya Stark", ActorName: "Someone Else", Siblings: []string{"Test Character"}},
+		{CharacterName: "Sandor Clegane", KilledBy: []string{"Arry"}},
+	}
+
+	report, err := loader.Load(ctx, characters)
+	s.Require().NoError(err)
+	s.Require().Equal(3, report.Characters)
+	s.Require().Equal(2, report.Created)
+	s.Require().Equal(1, report.Updated)
+	s.Require().Equal(2, report.Actors)
+	s.Require().Equal(1, report.Aliases)
+	s.Require().Equal(3, report.Relationships)
+	s.Require().Equal(1, report.Unresolved)
+
+	// loading again replaces instead of adding twice
+	report, err = loader.Load(ctx, characters)
+	s.Require().NoError(err)
+	s.Require().Equal(0, report.Created)
+	s.Require().Equal(3, report.Updated)
+
+	var relationships int
+	err = (*s.tx).QueryRow(ctx, "SELECT count(*) FROM relationships").Scan(&relationships)
+	s.Require().NoError(err)
+	s.Require().Equal(3, relationships)
+
+	character, err := s.repo.Get(ctx, 1)
+	s.Require().NoError(err)
+	s.Require().Equal(entities.HouseNameType{"Stark"}, character[0].HouseName)
+
+	aryaID, err := s.repo.GetCharacterID(ctx, "Arya Stark")
+	s.Require().NoError(err)
+	arya, err := s.repo.Get(ctx, aryaID)
+	s.Require().NoError(err)
+	s.Require().Equal("arya-stark", arya[0].Slug)
+
+	var actors int
+	err = (*s.tx).QueryRow(ctx, "SELECT count(*) FROM characters_actors WHERE character_id = $1", aryaID).Scan(&actors)
+	s.Require().NoError(err)
+	s.Require().Equal(2, actors)
+
+	// an actor nobody plays anymore is gone, one still playing someone stays
+	characters[2].ActorName = "Maisie Williams"
+	characters[2].Actors = nil
+	_, err = loader.Load(ctx, characters)
+	s.Require().NoError(err)
+	actorsRepo := NewActorsRepository(s.dbpool).WithTX(s.tx)
+	_, err = actorsRepo.Get(ctx, "Someone Else")
+	s.Require().ErrorIs(err, entities.ErrNotFound)
+	_, err = actorsRepo.Get(ctx, "Maisie Williams")
+	s.Require().NoError(err)
+}
+
+func (s *CharsetTestSuite) TestBulkLoadSlugs() {
+	ctx := context.Background()
+	_, err := (*s.tx).Exec(ctx, `INSERT INTO character_slug_history (slug, character_id) VALUES ('sandor-clegane', 1)`)
+	s.Require().NoError(err)
+	_, err = (*s.tx).Exec(ctx, `INSERT INTO characters (character_name, slug) VALUES ('The Hound', 'sandor-clegane-2')`)
+	s.Require().NoError(err)
+
+	// the slug is taken in the past and -2 now, like for uniqueSlug
+	_, err = NewBulkLoader(s.dbpool).WithTX(s.tx).Load(ctx, []entities.CharacterEntry{{CharacterName: "Sandor Clegane"}})
+	s.Require().NoError(err)
+
+	id, err := s.repo.GetCharacterID(ctx, "Sandor Clegane")
+	s.Require().NoError(err)
+	character, err := s.repo.Get(ctx, id)
+	s.Require().NoError(err)
+	s.Require().Equal("sandor-clegane-3", character[0].Slug)
+}
+
+// BenchmarkBulkLoad loads a generated dataset of 100k characters with
+// actors, aliases and relationships into the test database, rolled back
+// after each run. The target is under a minute per run:
+//
+//	go test ./postgres -run '^$' -bench BulkLoad -benchtime 1x
+func BenchmarkBulkLoad(b *testing.B) {
+	ctx := context.Background()
+	dbpool, err := NewDBPool()
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer dbpool.Close()
+	characters := generateCharacters(100_000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tx, err := dbpool.Begin(ctx)
+		if err != nil {
+			b.Fatal(err)
+		}
+		report, err := NewBulkLoader(dbpool).WithTX(&tx).Load(ctx, characters)
+		tx.Rollback(ctx)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ReportMetric(report.CharactersPerSecond(), "characters/s")
+	}
+}
+
+// generateCharacters makes n characters in 50 houses, each with an actor,
+// a nickname and a parent, a sibling and a killer among the others.
+func generateCharacters(n int) []entities.CharacterEntry {
+	characters := make([]entities.CharacterEntry, n)
+	name := func(i int) string { return fmt.Sprintf("Character %06d", i) }
+	for i := range characters {
+		characters[i] = entities.CharacterEntry{
+			CharacterName:      name(i),
+			HouseName:          entities.HouseNameType{fmt.Sprintf("House %02d", i%50)},
+			Nickname:           fmt.Sprintf("Nickname %06d", i),
+			CharacterImageFull: fmt.Sprintf("https://example.com/%06d.jpg", i),
+			ActorName:          fmt.Sprintf("Actor %06d", i/2),
+			Actors:             []entities.ActorEntry{{ActorName: fmt.Sprintf("Actor %06d", i/2), SeasonsActive: []int{1 + i%8}}},
+			Parents:            []string{name((i + n - 1) % n)},
+			Siblings:           []string{name((i + 1) % n)},
+			KilledBy:           []string{name((i * 7) % n)},
+		}
+	}
+	return characters
+}
@@ -2,10 +2,13 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"net/url"
+	"strconv"
 	"strings"
 
+	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgtype"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
@@ -34,7 +37,7 @@ func (r *CharactersRepository) WithTX(tx *pgx.Tx) *CharactersRepository {
 	return &CharactersRepository{
 		dbPool:     r.dbPool,
 		tx:         tx,
-		actorsRepo: r.actorsRepo,
+		actorsRepo: r.actorsRepo.WithTX(tx),
 	}
 }
 
@@ -45,57 +48,132 @@ func (r *CharactersRepository) getExecutor() PGXExecutor {
 	return r.dbPool
 }
 
-// Gets or creates an actor first, then creates character
+// Creates character, then gets or creates its actor and links them.
+// Returns a conflict error if a character with the same name exists.
 func (r *CharactersRepository) CreateCharacterAndActor(ctx context.Context, characterEntryEntry *entities.CharacterEntry) error {
-	var actorId int
-	var err error
-	var characterId int
-	if characterEntryEntry.ActorName != "" {
-		actorId, err = r.actorsRepo.GetActorID(ctx, characterEntryEntry.ActorName)
-		if err != nil {
-			actorId, err = r.actorsRepo.Create(ctx, characterEntryEntry.ActorName, characterEntryEntry.ActorLink)
-			if err != nil {
-				return fmt.Errorf("%w: %v", ErrCharacterRepoPersistenceFailure, err)
-			}
-		}
+	return inTx(ctx, r.dbPool, r.tx, func(tx *pgx.Tx) error {
+		return r.WithTX(tx).createCharacterAndActor(ctx, characterEntryEntry)
+	})
+}
+
+func (r *CharactersRepository) createCharacterAndActor(ctx context.Context, characterEntryEntry *entities.CharacterEntry) error {
+	_, err := r.characterIDByName(ctx, characterEntryEntry.CharacterName)
+	if err == nil {
+		return entities.NewConflictError("character %q already exists", characterEntryEntry.CharacterName)
+	}
+	if !errors.Is(err, entities.ErrNotFound) {
+		return fmt.Errorf("%w: %w", ErrCharacterRepoPersistenceFailure, err)
 	}
 
-	existingCharacter, err := r.GetCharacterID(ctx, characterEntryEntry.CharacterName)
-	if err != nil || existingCharacter == 0 {
-		houseNames := strings.Join(characterEntryEntry.HouseName, ",")
-		characterId, err = r.CreateCharacter(ctx, characterEntryEntry, houseNames)
-		if err != nil {
-			return fmt.Errorf("%w: %v", ErrCharacterRepoPersistenceFailure, err)
-		}
-	} else {
-		characterId = existingCharacter
+	houseNames := strings.Join(characterEntryEntry.HouseName, ",")
+	characterId, err := r.CreateCharacter(ctx, characterEntryEntry, houseNames)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCharacterRepoPersistenceFailure, err)
+	}
+	characterEntryEntry.CharacterID = characterId
+
+	characterEntryEntry.Aliases = characterEntryEntry.AllAliases()
+	err = r.ReplaceAliases(ctx, characterId, characterEntryEntry.Aliases)
+	if err != nil {
+		return err
+	}
+
+	err = r.addActors(ctx, characterId, characterEntryEntry.AllActors())
+	if err != nil {
+		return err
+	}
+	return enqueueSearchUpdate(ctx, r.getExecutor(), characterId, entities.OutboxUpsert)
+}
+
+// Gets or creates an actor and links it to the character
+func (r *CharactersRepository) AddActor(ctx context.Context, characterId int, actorName string, actorLink string, seasonsActive []int) error {
+	actorId, err := r.actorsRepo.GetActorID(ctx, actorName)
+	if errors.Is(err, entities.ErrNotFound) {
+		actorId, err = r.actorsRepo.Create(ctx, actorName, actorLink)
+	}
+	if err != nil {
+		return fmt.Errorf("create actor %w: %w", ErrCharacterRepoPersistenceFailure, err)
+	}
+
+	err = r.actorsRepo.LinkActorToCharacter(ctx, actorId, characterId, seasonsActive)
+	if err != nil {
+		return fmt.Errorf("link to character %w: %w", ErrCharacterRepoPersistenceFailure, err)
 	}
+	return nil
+}
 
-	if characterEntryEntry.ActorName != "" {
-		return r.actorsRepo.LinkActorToCharacter(ctx, actorId, characterId)
+func (r *CharactersRepository) addActors(ctx context.Context, characterId int, actors []entities.ActorEntry) error {
+	for _, actor := range actors {
+		err := r.AddActor(ctx, characterId, actor.ActorName, actor.ActorLink, actor.SeasonsActive)
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-func (r *CharactersRepository) UpdateCharacterAndActor(ctx context.Context, characterEntryEntry *entities.CharacterEntry, characterName string) (int, error) {
-	existingCharacter, err := r.GetCharacterID(ctx, characterName)
+// Updates the character and relinks its actor. A different CharacterName
+// renames the character: it gets a new slug and the old one is kept in
+// character_slug_history so existing links keep resolving.
+func (r *CharactersRepository) UpdateCharacterAndActor(ctx context.Context, characterEntryEntry *entities.CharacterEntry, characterID int) (int, error) {
+	var id int
+	err := inTx(ctx, r.dbPool, r.tx, func(tx *pgx.Tx) error {
+		var err error
+		id, err = r.WithTX(tx).updateCharacterAndActor(ctx, characterEntryEntry, characterID)
+		return err
+	})
+	return id, err
+}
+
+func (r *CharactersRepository) updateCharacterAndActor(ctx context.Context, characterEntryEntry *entities.CharacterEntry, characterID int) (int, error) {
+	var currentName, currentSlug string
+	sql, args, err := Psql.
+		Select("character_name", "slug").
+		From("characters").
+		Where("character_id = ?", characterID).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrCharacterRepoPersistenceFailure, err)
+	}
+	err = r.getExecutor().QueryRow(ctx, sql, args...).Scan(&currentName, &currentSlug)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, entities.NewNotFoundError("character %d not found", characterID)
+	}
 	if err != nil {
-		return 0, fmt.Errorf("GetCharacterID %w: %v", ErrCharacterRepoPersistenceFailure, err)
+		return 0, fmt.Errorf("%w: %w", ErrCharacterRepoPersistenceFailure, toDomainError(err))
+	}
+
+	if characterEntryEntry.CharacterName == "" {
+		characterEntryEntry.CharacterName = currentName
+	}
+	slug := currentSlug
+	if characterEntryEntry.CharacterName != currentName {
+		slug, err = r.uniqueSlug(ctx, characterEntryEntry.CharacterName, characterID)
+		if err != nil {
+			return 0, err
+		}
 	}
-	if existingCharacter == 0 {
-		return 0, fmt.Errorf("%w: character not found", ErrCharacterRepoPersistenceFailure)
+	if slug != currentSlug {
+		err = r.moveSlug(ctx, characterID, currentSlug, slug)
+		if err != nil {
+			return 0, err
+		}
 	}
 
 	houseNames := strings.Join(characterEntryEntry.HouseName, ",")
-	sql, args, err := Psql.
+	sql, args, err = Psql.
 		Update("characters").
+		Set("character_name", characterEntryEntry.CharacterName).
+		Set("slug", slug).
 		Set("house_name", houseNames).
 		Set("character_image_thumb", characterEntryEntry.CharacterImageThumb).
 		Set("character_image_full", characterEntryEntry.CharacterImageFull).
 		Set("character_link", characterEntryEntry.CharacterLink).
 		Set("nickname", characterEntryEntry.Nickname).
 		Set("royal", characterEntryEntry.Royal).
-		Where("character_name = ?", characterName).
+		Set("extra", extraJSON(characterEntryEntry.Extra)).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where("character_id = ?", characterID).
 		Suffix("RETURNING character_id").
 		ToSql()
 	if err != nil {
@@ -104,54 +182,83 @@ func (r *CharactersRepository) UpdateCharacterAndActor(ctx context.Context, char
 	var id int
 	err = r.getExecutor().QueryRow(ctx, sql, args...).Scan(&id)
 	if err != nil {
-		return 0, fmt.Errorf("update query %w: %v", ErrCharacterRepoPersistenceFailure, err)
+		return 0, fmt.Errorf("update query %w: %w", ErrCharacterRepoPersistenceFailure, toDomainError(err))
+	}
+	characterEntryEntry.CharacterID = id
+	characterEntryEntry.Slug = slug
+
+	characterEntryEntry.Aliases = characterEntryEntry.AllAliases()
+	err = r.ReplaceAliases(ctx, id, characterEntryEntry.Aliases)
+	if err != nil {
+		return 0, err
+	}
+
+	previousActorIDs, err := r.linkedActorIDs(ctx, id)
+	if err != nil {
+		return 0, err
 	}
 
 	// unlink actor from character
-	err = r.actorsRepo.UnlinkActorFromCharacter(ctx, existingCharacter)
+	err = r.actorsRepo.UnlinkActorFromCharacter(ctx, id)
 	if err != nil {
-		return 0, fmt.Errorf("unlink actor %w: %v", ErrCharacterRepoPersistenceFailure, err)
+		return 0, fmt.Errorf("unlink actor %w: %w", ErrCharacterRepoPersistenceFailure, err)
 	}
 
-	var actorId int
-	if characterEntryEntry.ActorName != "" {
-		actorId, err = r.actorsRepo.GetActorID(ctx, characterEntryEntry.ActorName)
-		if err != nil {
-			actorId, err = r.actorsRepo.Create(ctx, characterEntryEntry.ActorName, characterEntryEntry.ActorLink)
-			if err != nil {
-				return 0, fmt.Errorf("create actor %w: %v", ErrCharacterRepoPersistenceFailure, err)
-			}
-		}
+	err = r.addActors(ctx, id, characterEntryEntry.AllActors())
+	if err != nil {
+		return 0, err
+	}
 
-		err = r.actorsRepo.LinkActorToCharacter(ctx, actorId, existingCharacter)
-		if err != nil {
-			return 0, fmt.Errorf("link to character %w: %v", ErrCharacterRepoPersistenceFailure, err)
-		}
+	err = r.deleteOrphanedActors(ctx, previousActorIDs)
+	if err != nil {
+		return 0, err
 	}
 
+	err = enqueueSearchUpdate(ctx, r.getExecutor(), id, entities.OutboxUpsert)
+	if err != nil {
+		return 0, err
+	}
+	// the characters related to it show its name, which may have changed
+	referringIDs, err := r.referringCharacterIDs(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	err = r.enqueueUpserts(ctx, referringIDs)
+	if err != nil {
+		return 0, err
+	}
 	return id, nil
 }
 
 func (r *CharactersRepository) CreateCharacter(ctx context.Context, characterEntryEntry *entities.CharacterEntry, houseNames string) (int, error) {
+	slug, err := r.uniqueSlug(ctx, characterEntryEntry.CharacterName, 0)
+	if err != nil {
+		return 0, err
+	}
+
 	sql, args, err := Psql.
 		Insert("characters").
 		Columns(
 			"character_name",
+			"slug",
 			"house_name",
 			"character_image_thumb",
 			"character_image_full",
 			"character_link",
 			"nickname",
 			"royal",
+			"extra",
 		).
 		Values(
 			characterEntryEntry.CharacterName,
+			slug,
 			houseNames,
 			characterEntryEntry.CharacterImageThumb,
 			characterEntryEntry.CharacterImageFull,
 			characterEntryEntry.CharacterLink,
 			characterEntryEntry.Nickname,
 			characterEntryEntry.Royal,
+			extraJSON(characterEntryEntry.Extra),
 		).
 		Suffix("RETURNING character_id").
 		ToSql()
@@ -162,145 +269,417 @@ func (r *CharactersRepository) CreateCharacter(ctx context.Context, characterEnt
 	var id int
 	err = r.getExecutor().QueryRow(ctx, sql, args...).Scan(&id)
 	if err != nil {
-		return 0, fmt.Errorf("%w: %v", ErrCharacterRepoPersistenceFailure, err)
+		return 0, fmt.Errorf("%w: %w", ErrCharacterRepoPersistenceFailure, toDomainError(err))
 	}
+	characterEntryEntry.Slug = slug
 	return id, nil
 }
 
-func (r *CharactersRepository) GetCharacterID(ctx context.Context, characterName string) (int, error) {
-	decodedName, err := url.QueryUnescape(characterName)
+// extraJSON is the extra column value, an empty object when there is nothing.
+func extraJSON(extra map[string]json.RawMessage) sq.Sqlizer {
+	if len(extra) == 0 {
+		return sq.Expr("'{}'::jsonb")
+	}
+	data, _ := json.Marshal(extra)
+	return sq.Expr("?::jsonb", string(data))
+}
+
+// uniqueSlug derives a slug from name that no other character uses now or
+// used in the past, appending -2, -3... on collisions.
+func (r *CharactersRepository) uniqueSlug(ctx context.Context, name string, characterID int) (string, error) {
+	base := entities.Slugify(name)
+	if base == "" {
+		base = "character"
+	}
+
+	for i := 1; i < 100; i++ {
+		candidate := slugCandidate(base, i)
+
+		sql, args, err := Psql.
+			Select().
+			Column(sq.Expr(
+				`EXISTS(SELECT 1 FROM characters WHERE slug = ? AND character_id <> ?)
+				OR EXISTS(SELECT 1 FROM character_slug_history WHERE slug = ? AND character_id <> ?)`,
+				candidate, characterID, candidate, characterID,
+			)).
+			ToSql()
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrCharacterRepoPersistenceFailure, err)
+		}
+
+		var taken bool
+		err = r.getExecutor().QueryRow(ctx, sql, args...).Scan(&taken)
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrCharacterRepoPersistenceFailure, toDomainError(err))
+		}
+		if !taken {
+			return candidate, nil
+		}
+	}
+
+	return "", entities.NewConflictError("unable to find a free slug for %q", name)
+}
+
+// slugCandidate is the i-th slug tried for base: base itself, then base-2,
+// base-3 and so on.
+func slugCandidate(base string, i int) string {
+	if i == 1 {
+		return base
+	}
+	return base + "-" + strconv.Itoa(i)
+}
+
+// moveSlug records oldSlug as a redirect to the character and releases
+// newSlug from its history in case the character is reclaiming it.
+func (r *CharactersRepository) moveSlug(ctx context.Context, characterID int, oldSlug string, newSlug string) error {
+	sql, args, err := Psql.
+		Insert("character_slug_history").
+		Columns("slug", "character_id").
+		Values(oldSlug, characterID).
+		Suffix("ON CONFLICT (slug) DO UPDATE SET character_id = EXCLUDED.character_id").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCharacterRepoPersistenceFailure, err)
+	}
+	_, err = r.getExecutor().Exec(ctx, sql, args...)
 	if err != nil {
-		return 0, fmt.Errorf("error decoding character name: %w", err)
+		return fmt.Errorf("%w: %w", ErrCharacterRepoPersistenceFailure, toDomainError(err))
 	}
 
+	sql, args, err = Psql.
+		Delete("character_slug_history").
+		Where(sq.Eq{"slug": newSlug, "character_id": characterID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCharacterRepoPersistenceFailure, err)
+	}
+	_, err = r.getExecutor().Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCharacterRepoPersistenceFailure, toDomainError(err))
+	}
+	return nil
+}
+
+// GetCharacterID looks a character up by name, preferring the exact
+// spelling and falling back to a case, accent and punctuation insensitive
+// match, so "jon snow" or "Jaqen H’ghar" still resolve, and finally to the
+// character's aliases, so "The Hound" resolves to Sandor Clegane.
+func (r *CharactersRepository) GetCharacterID(ctx context.Context, characterName string) (int, error) {
+	sql, args, err := Psql.
+		Select("c.character_id").
+		From("characters AS c").
+		Where(sq.Or{
+			sq.Eq{"c.character_name": characterName},
+			sq.Expr("c.character_name_normalized = normalize_name(?)", characterName),
+			sq.Expr(`EXISTS(
+				SELECT 1 FROM character_aliases AS al
+				WHERE al.character_id = c.character_id AND al.alias_normalized = normalize_name(?)
+			)`, characterName),
+		}).
+		OrderByClause(`CASE
+			WHEN c.character_name = ? THEN 0
+			WHEN c.character_name_normalized = normalize_name(?) THEN 1
+			ELSE 2
+		END, c.character_id`, characterName, characterName).
+		Limit(1).
+		ToSql()
+	return r.scanCharacterID(ctx, sql, args, characterName, err)
+}
+
+// characterIDByName is GetCharacterID without the alias fallback, for
+// checking whether a name is already taken.
+func (r *CharactersRepository) characterIDByName(ctx context.Context, characterName string) (int, error) {
 	sql, args, err := Psql.
 		Select("character_id").
 		From("characters").
-		Where("character_name = ?", decodedName).
+		Where(sq.Or{
+			sq.Eq{"character_name": characterName},
+			sq.Expr("character_name_normalized = normalize_name(?)", characterName),
+		}).
+		OrderByClause("(character_name = ?) DESC", characterName).
+		Limit(1).
 		ToSql()
+	return r.scanCharacterID(ctx, sql, args, characterName, err)
+}
+
+func (r *CharactersRepository) scanCharacterID(ctx context.Context, sql string, args []interface{}, characterName string, err error) (int, error) {
 	if err != nil {
 		return 0, fmt.Errorf("error building sql: %w", err)
 	}
 	row := r.getExecutor().QueryRow(ctx, sql, args...)
 	var characterId int
 	err = row.Scan(&characterId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, entities.NewNotFoundError("character %q not found", characterName)
+	}
 	if err != nil {
-		return 0, fmt.Errorf("error scanning row: %w", err)
+		return 0, fmt.Errorf("error scanning row: %w", toDomainError(err))
 	}
 	return characterId, nil
 }
 
-func (r *CharactersRepository) Delete(ctx context.Context, name string) error {
+// Resolve finds a character by its current slug, a previous slug, its
+// exact name, its normalized name or one of its aliases. The returned ref
+// carries the current slug so callers can redirect to the canonical URL.
+func (r *CharactersRepository) Resolve(ctx context.Context, key string) (entities.CharacterRef, error) {
 	sql, args, err := Psql.
-		Delete("characters").
-		Where("character_name = ?", name).
+		Select("c.character_id", "c.slug").
+		From("characters AS c").
+		LeftJoin("character_slug_history AS h ON h.character_id = c.character_id AND h.slug = ?", key).
+		LeftJoin("character_aliases AS al ON al.character_id = c.character_id AND al.alias_normalized = normalize_name(?)", key).
+		Where(sq.Or{
+			sq.Eq{"c.slug": key},
+			sq.NotEq{"h.slug": nil},
+			sq.Eq{"c.character_name": key},
+			sq.Expr("c.character_name_normalized = normalize_name(?)", key),
+			sq.NotEq{"al.alias_id": nil},
+		}).
+		OrderByClause(`CASE
+			WHEN c.slug = ? THEN 0
+			WHEN h.slug IS NOT NULL THEN 1
+			WHEN c.character_name = ? THEN 2
+			WHEN c.character_name_normalized = normalize_name(?) THEN 3
+			ELSE 4
+		END, c.character_id`, key, key, key).
+		Limit(1).
 		ToSql()
 	if err != nil {
-		return fmt.Errorf("%w: %v", ErrCharacterRepoPersistenceFailure, err)
+		return entities.CharacterRef{}, fmt.Errorf("error building sql: %w", err)
+	}
+
+	var ref entities.CharacterRef
+	err = r.getExecutor().QueryRow(ctx, sql, args...).Scan(&ref.ID, &ref.Slug)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entities.CharacterRef{}, entities.NewNotFoundError("character %q not found", key)
 	}
-	_, err = r.getExecutor().Exec(ctx, sql, args...)
 	if err != nil {
-		return fmt.Errorf("%w: %v", ErrCharacterRepoPersistenceFailure, err)
+		return entities.CharacterRef{}, fmt.Errorf("error scanning row: %w", toDomainError(err))
 	}
+	return ref, nil
+}
 
-	return nil
+// ResolveSlug finds a character by its current or a previous slug only,
+// for writes, which must not reach a character through a name or alias.
+func (r *CharactersRepository) ResolveSlug(ctx context.Context, slug string) (entities.CharacterRef, error) {
+	sql, args, err := Psql.
+		Select("c.character_id", "c.slug").
+		From("characters AS c").
+		Where(sq.Or{
+			sq.Eq{"c.slug": slug},
+			sq.Expr("c.character_id = (SELECT h.character_id FROM character_slug_history AS h WHERE h.slug = ?)", slug),
+		}).
+		OrderByClause("c.slug = ? DESC", slug).
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return entities.CharacterRef{}, fmt.Errorf("error building sql: %w", err)
+	}
+
+	var ref entities.CharacterRef
+	err = r.getExecutor().QueryRow(ctx, sql, args...).Scan(&ref.ID, &ref.Slug)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entities.CharacterRef{}, entities.NewNotFoundError("character %q not found", slug)
+	}
+	if err != nil {
+		return entities.CharacterRef{}, fmt.Errorf("error scanning row: %w", toDomainError(err))
+	}
+	return ref, nil
+}
+
+// Deletes the character and the actors that played only this character.
+func (r *CharactersRepository) Delete(ctx context.Context, characterID int) error {
+	return inTx(ctx, r.dbPool, r.tx, func(tx *pgx.Tx) error {
+		return r.WithTX(tx).delete(ctx, characterID)
+	})
 }
 
-func (r *CharactersRepository) Get(ctx context.Context, name string) ([]entities.CharacterEntry, error) {
-	decodedName, err := url.QueryUnescape(name)
+func (r *CharactersRepository) delete(ctx context.Context, characterID int) error {
+	actorIDs, err := r.linkedActorIDs(ctx, characterID)
 	if err != nil {
-		return nil, fmt.Errorf("error decoding character name: %w", err)
+		return err
+	}
+	// read before the cascade drops their relationships rows
+	referringIDs, err := r.referringCharacterIDs(ctx, characterID)
+	if err != nil {
+		return err
 	}
-	var houseName pgtype.TextArray
 
 	sql, args, err := Psql.
-		Select(`
-			c.character_id,
-			c.character_name,
-			string_to_array(c.house_name, '-') AS house_name,
-			c.character_image_thumb,
-			c.character_image_full,
-			c.character_link,
-			c.nickname,
-			c.royal,
-			COALESCE(a.actor_name, '') AS actor_name,
-			COALESCE(a.actor_link, '') AS actor_link,
-			COALESCE(array_remove(array_agg(DISTINCT CASE WHEN r.relationship_type = 'parent' THEN related_character.character_name END), NULL), '{}') AS parents,
-			COALESCE(array_remove(array_agg(DISTINCT CASE WHEN r.relationship_type = 'sibling' THEN related_character.character_name END), NULL), '{}') AS siblings,
-			COALESCE(array_remove(array_agg(DISTINCT CASE WHEN r.relationship_type = 'killed' THEN related_character.character_name END), NULL), '{}') AS killed,
-			COALESCE(array_remove(array_agg(DISTINCT CASE WHEN r.relationship_type = 'killed_by' THEN related_character.character_name END), NULL), '{}') AS killed_by,
-			COALESCE(array_remove(array_agg(DISTINCT CASE WHEN r.relationship_type = 'married_engaged' THEN related_character.character_name END), NULL), '{}') AS married_engaged
-	`).
-		From("characters AS c").
-		LeftJoin("characters_actors AS ca ON c.character_id = ca.character_id").
-		LeftJoin("actors AS a ON ca.actor_id = a.actor_id").
-		LeftJoin("relationships AS r ON c.character_id = r.character_id").
-		LeftJoin("characters AS related_character ON r.character_relationship_id = related_character.character_id").
-		Where("c.character_name = ?", decodedName).
-		GroupBy("c.character_id, a.actor_name, a.actor_link").
+		Delete("characters").
+		Where("character_id = ?", characterID).
 		ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("error building sql: %w", err)
+		return fmt.Errorf("%w: %v", ErrCharacterRepoPersistenceFailure, err)
+	}
+	tag, err := r.getExecutor().Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCharacterRepoPersistenceFailure, toDomainError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return entities.NewNotFoundError("character %d not found", characterID)
+	}
+
+	err = r.deleteOrphanedActors(ctx, actorIDs)
+	if err != nil {
+		return err
+	}
+	err = r.enqueueUpserts(ctx, referringIDs)
+	if err != nil {
+		return err
+	}
+	return enqueueSearchUpdate(ctx, r.getExecutor(), characterID, entities.OutboxDelete)
+}
+
+// referringCharacterIDs are the other characters with a relationship to
+// the character, whose search documents name it.
+func (r *CharactersRepository) referringCharacterIDs(ctx context.Context, characterID int) ([]int, error) {
+	sql, args, err := Psql.
+		Select("DISTINCT character_id").
+		From("relationships").
+		Where("character_relationship_id = ? AND character_id <> ?", characterID, characterID).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCharacterRepoPersistenceFailure, err)
 	}
 	rows, err := r.getExecutor().Query(ctx, sql, args...)
 	if err != nil {
-		return nil, fmt.Errorf("error executing query: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrCharacterRepoPersistenceFailure, toDomainError(err))
 	}
 	defer rows.Close()
-	var characters []entities.CharacterEntry
+
+	var characterIDs []int
 	for rows.Next() {
-		var c entities.CharacterEntry
-		err := rows.Scan(
-			&c.CharacterID,
-			&c.CharacterName,
-			&houseName,
-			&c.CharacterImageThumb,
-			&c.CharacterImageFull,
-			&c.CharacterLink,
-			&c.Nickname,
-			&c.Royal,
-			&c.ActorName,
-			&c.ActorLink,
-			pq.Array(&c.Parents),
-			pq.Array(&c.Siblings),
-			pq.Array(&c.Killed),
-			pq.Array(&c.KilledBy),
-			pq.Array(&c.MarriedEngaged),
-		)
-		if err != nil {
-			return nil, fmt.Errorf("error scanning row: %w", err)
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrCharacterRepoPersistenceFailure, toDomainError(err))
 		}
+		characterIDs = append(characterIDs, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCharacterRepoPersistenceFailure, toDomainError(err))
+	}
+	return characterIDs, nil
+}
 
-		if houseName.Status == pgtype.Present {
-			c.HouseName = make(entities.HouseNameType, len(houseName.Elements))
-			for i, elem := range houseName.Elements {
-				c.HouseName[i] = elem.String
-			}
-		} else {
-			c.HouseName = entities.HouseNameType{}
+func (r *CharactersRepository) enqueueUpserts(ctx context.Context, characterIDs []int) error {
+	for _, id := range characterIDs {
+		if err := enqueueSearchUpdate(ctx, r.getExecutor(), id, entities.OutboxUpsert); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		characters = append(characters, c)
+func (r *CharactersRepository) linkedActorIDs(ctx context.Context, characterID int) ([]int, error) {
+	sql, args, err := Psql.
+		Select("actor_id").
+		From("characters_actors").
+		Where("character_id = ?", characterID).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCharacterRepoPersistenceFailure, err)
+	}
+	rows, err := r.getExecutor().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCharacterRepoPersistenceFailure, toDomainError(err))
 	}
+	defer rows.Close()
 
-	return characters, nil
+	var actorIDs []int
+	for rows.Next() {
+		var actorID int
+		if err := rows.Scan(&actorID); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrCharacterRepoPersistenceFailure, toDomainError(err))
+		}
+		actorIDs = append(actorIDs, actorID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCharacterRepoPersistenceFailure, toDomainError(err))
+	}
+	return actorIDs, nil
+}
+
+// deleteOrphanedActors removes the given actors once no character links to
+// them, so unlinking an actor doesn't leave a stale actors row behind.
+func (r *CharactersRepository) deleteOrphanedActors(ctx context.Context, actorIDs []int) error {
+	if len(actorIDs) == 0 {
+		return nil
+	}
+	_, err := r.actorsRepo.DeleteOrphans(ctx, actorIDs...)
+	if err != nil {
+		return fmt.Errorf("delete orphaned actors %w: %w", ErrCharacterRepoPersistenceFailure, err)
+	}
+	return nil
+}
+
+func (r *CharactersRepository) Get(ctx context.Context, characterID int) ([]entities.CharacterEntry, error) {
+	sql, args, err := characterDetailsQuery().
+		Where("c.character_id = ?", characterID).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building sql: %w", err)
+	}
+	return r.queryCharacterDetails(ctx, sql, args)
 }
 
 func (r *CharactersRepository) GetAll(ctx context.Context, page int) ([]entities.CharacterEntry, error) {
-	var houseName pgtype.TextArray
+	// ordered, so the rows of a character don't move between pages
+	sql, args, err := characterDetailsQuery().
+		OrderBy("c.character_id", "a.actor_name").
+		Limit(25).
+		Offset(uint64(page) * 25).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building sql: %w", err)
+	}
+	return r.queryCharacterDetails(ctx, sql, args)
+}
 
-	sql, args, err := Psql.
+// GetAfter loads the next limit characters by ID after afterID, so walking
+// every character costs the same per batch however far in it is.
+func (r *CharactersRepository) GetAfter(ctx context.Context, afterID int, limit int) ([]entities.CharacterEntry, error) {
+	sql, args, err := characterDetailsQuery().
+		Where("c.character_id IN (SELECT character_id FROM characters WHERE character_id > ? ORDER BY character_id LIMIT ?)", afterID, limit).
+		OrderBy("c.character_id", "a.actor_name").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building sql: %w", err)
+	}
+	return r.queryCharacterDetails(ctx, sql, args)
+}
+
+// characterDetailsQuery selects characters with their actor and
+// relationships aggregated into one row per character and actor.
+func characterDetailsQuery() sq.SelectBuilder {
+	return Psql.
 		Select(`
 			c.character_id,
 			c.character_name,
-			string_to_array(c.house_name, '-') AS house_name,
-			c.character_image_thumb,
-			c.character_image_full,
-			c.character_link,
-			c.nickname,
-			c.royal,
+			c.slug,
+			string_to_array(c.house_name, ',') AS house_name,
+			COALESCE(c.character_image_thumb, '') AS character_image_thumb,
+			COALESCE(c.character_image_full, '') AS character_image_full,
+			COALESCE(c.character_link, '') AS character_link,
+			COALESCE(c.nickname, '') AS nickname,
+			COALESCE((
+				SELECT json_agg(json_build_object('name', al.alias, 'type', al.alias_type) ORDER BY al.alias_id)
+				FROM character_aliases AS al
+				WHERE al.character_id = c.character_id
+			), '[]') AS aliases,
+			COALESCE(c.royal, false) AS royal,
+			c.extra,
 			COALESCE(a.actor_name, '') AS actor_name,
 			COALESCE(a.actor_link, '') AS actor_link,
+			COALESCE((
+				SELECT json_agg(json_build_object(
+					'actorName', aa.actor_name,
+					'actorLink', COALESCE(aa.actor_link, ''),
+					'seasonsActive', aca.seasons_active
+				) ORDER BY aa.actor_name)
+				FROM characters_actors AS aca
+				JOIN actors AS aa ON aa.actor_id = aca.actor_id
+				WHERE aca.character_id = c.character_id
+			), '[]') AS actors,
 			COALESCE(array_remove(array_agg(DISTINCT CASE WHEN r.relationship_type = 'parent' THEN related_character.character_name END), NULL), '{}') AS parents,
 			COALESCE(array_remove(array_agg(DISTINCT CASE WHEN r.relationship_type = 'sibling' THEN related_character.character_name END), NULL), '{}') AS siblings,
 			COALESCE(array_remove(array_agg(DISTINCT CASE WHEN r.relationship_type = 'killed' THEN related_character.character_name END), NULL), '{}') AS killed,
@@ -312,33 +691,35 @@ func (r *CharactersRepository) GetAll(ctx context.Context, page int) ([]entities
 		LeftJoin("actors AS a ON ca.actor_id = a.actor_id").
 		LeftJoin("relationships AS r ON c.character_id = r.character_id").
 		LeftJoin("characters AS related_character ON r.character_relationship_id = related_character.character_id").
-		GroupBy("c.character_id, a.actor_name, a.actor_link").
-		Limit(25).
-		Offset(uint64(page) * 25).
-		ToSql()
-	if err != nil {
-		return nil, fmt.Errorf("error building sql: %w", err)
-	}
+		GroupBy("c.character_id, a.actor_name, a.actor_link")
+}
 
+func (r *CharactersRepository) queryCharacterDetails(ctx context.Context, sql string, args []interface{}) ([]entities.CharacterEntry, error) {
 	rows, err := r.getExecutor().Query(ctx, sql, args...)
 	if err != nil {
-		return nil, fmt.Errorf("error executing query: %w", err)
+		return nil, fmt.Errorf("error executing query: %w", toDomainError(err))
 	}
 	defer rows.Close()
+
+	var houseName pgtype.TextArray
 	var characters []entities.CharacterEntry
 	for rows.Next() {
 		var c entities.CharacterEntry
 		err := rows.Scan(
 			&c.CharacterID,
 			&c.CharacterName,
+			&c.Slug,
 			&houseName,
 			&c.CharacterImageThumb,
 			&c.CharacterImageFull,
 			&c.CharacterLink,
 			&c.Nickname,
+			&c.Aliases,
 			&c.Royal,
+			&c.Extra,
 			&c.ActorName,
 			&c.ActorLink,
+			&c.Actors,
 			pq.Array(&c.Parents),
 			pq.Array(&c.Siblings),
 			pq.Array(&c.Killed),
@@ -346,7 +727,7 @@ func (r *CharactersRepository) GetAll(ctx context.Context, page int) ([]entities
 			pq.Array(&c.MarriedEngaged),
 		)
 		if err != nil {
-			return nil, fmt.Errorf("error scanning row: %w", err)
+			return nil, fmt.Errorf("error scanning row: %w", toDomainError(err))
 		}
 
 		if houseName.Status == pgtype.Present {
@@ -360,6 +741,9 @@ func (r *CharactersRepository) GetAll(ctx context.Context, page int) ([]entities
 
 		characters = append(characters, c)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading rows: %w", toDomainError(err))
+	}
 
 	return characters, nil
 }
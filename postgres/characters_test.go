@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/jackc/pgx/v4"
@@ -43,6 +44,7 @@ func (s *CharsetTestSuite) SetupTest() {
 	 (
 		character_id,
 		character_name,
+		slug,
 		house_name,
 		character_image_thumb,
 		character_image_full,
@@ -53,6 +55,7 @@ func (s *CharsetTestSuite) SetupTest() {
 	 VALUES (
 	 1,
 	 'Test Character',
+	 'test-character',
 	 'Test House',
 	 'http://test.com/thumb',
 	 'http://test.com/full',
@@ -60,6 +63,7 @@ func (s *CharsetTestSuite) SetupTest() {
 	 'Test Nickname',
 	 true
 	 )`)
+	tx.Exec(ctx, `SELECT refresh_search_documents(ARRAY[1])`)
 }
 
 func (s *CharsetTestSuite) TearDownTest() {
@@ -76,7 +80,7 @@ func (s *CharsetTestSuite) TestDelete() {
 	err := (*s.tx).QueryRow(ctx, "SELECT count(*) FROM characters").Scan(&count)
 	s.Require().NoError(err)
 	s.Require().Equal(1, count)
-	err = s.repo.Delete(ctx, "Test Character")
+	err = s.repo.Delete(ctx, 1)
 	s.Require().NoError(err)
 
 	err = (*s.tx).QueryRow(ctx, "SELECT count(*) FROM characters").Scan(&count)
@@ -84,6 +88,65 @@ func (s *CharsetTestSuite) TestDelete() {
 	s.Require().Equal(0, count)
 }
 
+func (s *CharsetTestSuite) TestDeleteRemovesOrphanedActor() {
+	ctx := context.Background()
+
+	err := s.repo.AddActor(ctx, 1, "Test Actor", "", nil)
+	s.Require().NoError(err)
+	err = s.repo.Delete(ctx, 1)
+	s.Require().NoError(err)
+
+	var count int
+	err = (*s.tx).QueryRow(ctx, "SELECT count(*) FROM actors WHERE actor_name = 'Test Actor'").Scan(&count)
+	s.Require().NoError(err)
+	s.Require().Equal(0, count)
+}
+
+func (s *CharsetTestSuite) TestDeleteNotFound() {
+	ctx := context.Background()
+
+	err := s.repo.Delete(ctx, 999)
+	s.Require().ErrorIs(err, entities.ErrNotFound)
+}
+
+func (s *CharsetTestSuite) TestGetCharacterIDNotFound() {
+	ctx := context.Background()
+
+	_, err := s.repo.GetCharacterID(ctx, "Missing Character")
+	s.Require().ErrorIs(err, entities.ErrNotFound)
+}
+
+func (s *CharsetTestSuite) TestCreateCharacterDuplicateName() {
+	ctx := context.Background()
+	characterEntryEntry := entities.CharacterEntry{
+		CharacterName: "Test Character",
+	}
+
+	_, err := s.repo.CreateCharacter(ctx, &characterEntryEntry, "")
+	s.Require().ErrorIs(err, entities.ErrConflict)
+}
+
+func (s *CharsetTestSuite) TestExtra() {
+	ctx := context.Background()
+	characterEntryEntry := entities.CharacterEntry{
+		CharacterName: "Eddard Stark",
+		Extra:         map[string]json.RawMessage{"parentOf": json.RawMessage(`["Arya Stark"]`)},
+	}
+	err := s.repo.CreateCharacterAndActor(ctx, &characterEntryEntry)
+	s.Require().NoError(err)
+
+	characters, err := s.repo.Get(ctx, characterEntryEntry.CharacterID)
+	s.Require().NoError(err)
+	s.Require().JSONEq(`["Arya Stark"]`, string(characters[0].Extra["parentOf"]))
+
+	characterEntryEntry.Extra = nil
+	_, err = s.repo.UpdateCharacterAndActor(ctx, &characterEntryEntry, characterEntryEntry.CharacterID)
+	s.Require().NoError(err)
+	characters, err = s.repo.Get(ctx, characterEntryEntry.CharacterID)
+	s.Require().NoError(err)
+	s.Require().Empty(characters[0].Extra)
+}
+
 func (s *CharsetTestSuite) TestGetCharacterID() {
 	ctx := context.Background()
 	characterName := "Test Character"
@@ -93,6 +156,16 @@ func (s *CharsetTestSuite) TestGetCharacterID() {
 	s.Require().Equal(1, id)
 }
 
+func (s *CharsetTestSuite) TestGetCharacterIDNormalized() {
+	ctx := context.Background()
+
+	for _, name := range []string{"test character", "TEST  CHARACTER", "Tést Character", "Test-Character", "Test Charac’ter"} {
+		id, err := s.repo.GetCharacterID(ctx, name)
+		s.Require().NoError(err, name)
+		s.Require().Equal(1, id, name)
+	}
+}
+
 func (s *CharsetTestSuite) TestCreateCharacter() {
 	ctx := context.Background()
 	characterEntryEntry := entities.CharacterEntry{
@@ -148,6 +221,17 @@ func (s *CharsetTestSuite) TestCreateCharacterAndActor() {
 	s.Require().Equal(linkedActorId, actorId)
 }
 
+func (s *CharsetTestSuite) TestCreateCharacterAndActorConflict() {
+	ctx := context.Background()
+	characterEntryEntry := entities.CharacterEntry{
+		CharacterName: "Test Character",
+		ActorName:     "Test Actor",
+	}
+
+	err := s.repo.CreateCharacterAndActor(ctx, &characterEntryEntry)
+	s.Require().ErrorIs(err, entities.ErrConflict)
+}
+
 func (s *CharsetTestSuite) TestUpdateCharacterAndActor() {
 	ctx := context.Background()
 	characterEntryEntry := entities.CharacterEntry{
@@ -162,7 +246,7 @@ func (s *CharsetTestSuite) TestUpdateCharacterAndActor() {
 		HouseName:           []string{"Test House 1", "Test House 2"},
 	}
 
-	id, err := s.repo.UpdateCharacterAndActor(ctx, &characterEntryEntry, "Test Character")
+	id, err := s.repo.UpdateCharacterAndActor(ctx, &characterEntryEntry, 1)
 	s.Require().NoError(err)
 	s.Require().Equal(1, id)
 
@@ -176,6 +260,125 @@ func (s *CharsetTestSuite) TestUpdateCharacterAndActor() {
 	s.Require().False(royal)
 }
 
+func (s *CharsetTestSuite) TestCreateCharacterSlug() {
+	ctx := context.Background()
+	characterEntryEntry := entities.CharacterEntry{
+		CharacterName: "Test  Character!",
+	}
+
+	_, err := s.repo.CreateCharacter(ctx, &characterEntryEntry, "")
+	s.Require().NoError(err)
+	s.Require().Equal("test-character-2", characterEntryEntry.Slug)
+}
+
+func (s *CharsetTestSuite) TestResolve() {
+	ctx := context.Background()
+
+	ref, err := s.repo.Resolve(ctx, "test-character")
+	s.Require().NoError(err)
+	s.Require().Equal(entities.CharacterRef{ID: 1, Slug: "test-character"}, ref)
+
+	ref, err = s.repo.Resolve(ctx, "Test Character")
+	s.Require().NoError(err)
+	s.Require().Equal(entities.CharacterRef{ID: 1, Slug: "test-character"}, ref)
+
+	ref, err = s.repo.Resolve(ctx, "test character")
+	s.Require().NoError(err)
+	s.Require().Equal(entities.CharacterRef{ID: 1, Slug: "test-character"}, ref)
+
+	_, err = s.repo.Resolve(ctx, "missing-character")
+	s.Require().ErrorIs(err, entities.ErrNotFound)
+}
+
+func (s *CharsetTestSuite) TestResolveSlug() {
+	ctx := context.Background()
+
+	ref, err := s.repo.ResolveSlug(ctx, "test-character")
+	s.Require().NoError(err)
+	s.Require().Equal(entities.CharacterRef{ID: 1, Slug: "test-character"}, ref)
+
+	_, err = s.repo.ResolveSlug(ctx, "Test Character")
+	s.Require().ErrorIs(err, entities.ErrNotFound)
+
+	characterEntryEntry := entities.CharacterEntry{CharacterName: "Renamed Character"}
+	_, err = s.repo.UpdateCharacterAndActor(ctx, &characterEntryEntry, 1)
+	s.Require().NoError(err)
+
+	ref, err = s.repo.ResolveSlug(ctx, "test-character")
+	s.Require().NoError(err)
+	s.Require().Equal(entities.CharacterRef{ID: 1, Slug: "renamed-character"}, ref)
+
+	characterEntryEntry.CharacterName = "Test Character"
+	_, err = s.repo.UpdateCharacterAndActor(ctx, &characterEntryEntry, 1)
+	s.Require().NoError(err)
+}
+
+func (s *CharsetTestSuite) TestUpdateCharacterAndActorRename() {
+	ctx := context.Background()
+	characterEntryEntry := entities.CharacterEntry{
+		CharacterName: "Renamed Character",
+	}
+
+	id, err := s.repo.UpdateCharacterAndActor(ctx, &characterEntryEntry, 1)
+	s.Require().NoError(err)
+	s.Require().Equal(1, id)
+	s.Require().Equal("renamed-character", characterEntryEntry.Slug)
+
+	ref, err := s.repo.Resolve(ctx, "test-character")
+	s.Require().NoError(err)
+	s.Require().Equal(entities.CharacterRef{ID: 1, Slug: "renamed-character"}, ref)
+
+	characterEntryEntry.CharacterName = "Test Character"
+	_, err = s.repo.UpdateCharacterAndActor(ctx, &characterEntryEntry, 1)
+	s.Require().NoError(err)
+	s.Require().Equal("test-character", characterEntryEntry.Slug)
+}
+
+func (s *CharsetTestSuite) TestUpdateCharacterAndActorRenameToNormalizedDuplicate() {
+	ctx := context.Background()
+	other := entities.CharacterEntry{CharacterName: "Other Character"}
+	s.Require().NoError(s.repo.CreateCharacterAndActor(ctx, &other))
+
+	other.CharacterName = "TEST  CHARACTER"
+	_, err := s.repo.UpdateCharacterAndActor(ctx, &other, other.CharacterID)
+	s.Require().ErrorIs(err, entities.ErrConflict)
+
+	_, err = s.repo.CreateCharacter(ctx, &entities.CharacterEntry{CharacterName: "Tést Character"}, "")
+	s.Require().ErrorIs(err, entities.ErrConflict)
+}
+
+func (s *CharsetTestSuite) TestAliases() {
+	ctx := context.Background()
+	characterEntryEntry := entities.CharacterEntry{
+		CharacterName: "Sandor Clegane",
+		Nickname:      "The Hound",
+		Aliases: []entities.Alias{
+			{Name: "Dog", Type: entities.AliasNickname},
+		},
+	}
+
+	err := s.repo.CreateCharacterAndActor(ctx, &characterEntryEntry)
+	s.Require().NoError(err)
+
+	id, err := s.repo.GetCharacterID(ctx, "the hound")
+	s.Require().NoError(err)
+	s.Require().Equal(characterEntryEntry.CharacterID, id)
+
+	ref, err := s.repo.Resolve(ctx, "Dog")
+	s.Require().NoError(err)
+	s.Require().Equal(entities.CharacterRef{ID: id, Slug: "sandor-clegane"}, ref)
+
+	characters, err := s.repo.Get(ctx, id)
+	s.Require().NoError(err)
+	s.Require().ElementsMatch([]entities.Alias{
+		{Name: "Dog", Type: entities.AliasNickname},
+		{Name: "The Hound", Type: entities.AliasNickname},
+	}, characters[0].Aliases)
+
+	err = s.repo.ReplaceAliases(ctx, id, []entities.Alias{{Name: "Ser", Type: "rank"}})
+	s.Require().ErrorIs(err, entities.ErrValidation)
+}
+
 func TestRunCharsetTestSuite(t *testing.T) {
 	suite.Run(t, &CharsetTestSuite{})
 }
@@ -20,6 +20,12 @@ func NewDBPool() (*pgxpool.Pool, error) {
 	}
 
 	dbUri := fmt.Sprintf("postgres://%v:%v@%v:%v/%v?sslmode=disable", pgUser, pgPass, pgHost, pgPort, pgName)
+	return NewDBPoolFromURL(dbUri)
+}
+
+// NewDBPoolFromURL connects to the database at a postgres:// URL, for
+// commands that take the connection as a flag.
+func NewDBPoolFromURL(dbUri string) (*pgxpool.Pool, error) {
 	dbPool, err := pgxpool.Connect(context.Background(),
 		dbUri,
 	)
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/vitalii-komenda/got/entities"
+)
+
+// SQLSTATE codes we translate into domain errors.
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgNotNullViolation    = "23502"
+	pgCheckViolation      = "23514"
+	pgStringTooLong       = "22001"
+	pgTooManyConnections  = "53300"
+	pgAdminShutdown       = "57P01"
+	pgCrashShutdown       = "57P02"
+	pgCannotConnectNow    = "57P03"
+	pgConnectionException = "08"
+)
+
+// toDomainError maps driver errors onto the entities error kinds. The
+// original error is kept in the chain for logging, but the client-facing
+// detail never contains driver text.
+func toDomainError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var domainErr *entities.DomainError
+	if errors.As(err, &domainErr) {
+		return err
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%w: %v", entities.NewNotFoundError("record not found"), err)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == pgUniqueViolation:
+			return fmt.Errorf("%w: %v", entities.NewConflictError("%s", conflictDetail(pgErr)), err)
+		case pgErr.Code == pgForeignKeyViolation:
+			return fmt.Errorf("%w: %v", entities.NewValidationError("referenced record does not exist"), err)
+		case pgErr.Code == pgNotNullViolation:
+			return fmt.Errorf("%w: %v", entities.NewValidationError("%s is required", pgErr.ColumnName), err)
+		case pgErr.Code == pgCheckViolation:
+			return fmt.Errorf("%w: %v", entities.NewValidationError("value is not allowed"), err)
+		case pgErr.Code == pgStringTooLong:
+			return fmt.Errorf("%w: %v", entities.NewValidationError("value is too long"), err)
+		case pgErr.Code == pgTooManyConnections,
+			pgErr.Code == pgAdminShutdown,
+			pgErr.Code == pgCrashShutdown,
+			pgErr.Code == pgCannotConnectNow,
+			strings.HasPrefix(pgErr.Code, pgConnectionException):
+			return fmt.Errorf("%w: %v", entities.NewUnavailableError("database is unavailable"), err)
+		}
+		return err
+	}
+
+	var netErr net.Error
+	if pgconn.Timeout(err) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.As(err, &netErr) {
+		return fmt.Errorf("%w: %v", entities.NewUnavailableError("database is unavailable"), err)
+	}
+
+	return err
+}
+
+func conflictDetail(pgErr *pgconn.PgError) string {
+	switch pgErr.ConstraintName {
+	case "characters_character_name_key", "characters_character_name_normalized_key":
+		return "a character with this name already exists"
+	case "actors_actor_name_key":
+		return "an actor with this name already exists"
+	}
+	return "record already exists"
+}
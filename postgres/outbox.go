@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	entities "github.com/vitalii-komenda/got/entities"
+)
+
+var ErrOutboxRepoPersistenceFailure = fmt.Errorf("outbox repo persistence failure")
+
+var _ entities.SearchOutbox = &OutboxRepository{}
+
+func NewOutboxRepository(dbpool *pgxpool.Pool) *OutboxRepository {
+	return &OutboxRepository{
+		dbPool: dbpool,
+	}
+}
+
+// OutboxRepository reads the search_outbox table the other repositories
+// write to, see enqueueSearchUpdate.
+type OutboxRepository struct {
+	dbPool *pgxpool.Pool
+	tx     *pgx.Tx
+}
+
+func (r *OutboxRepository) WithTX(tx *pgx.Tx) *OutboxRepository {
+	return &OutboxRepository{
+		dbPool: r.dbPool,
+		tx:     tx,
+	}
+}
+
+func (r *OutboxRepository) getExecutor() PGXExecutor {
+	if r.tx != nil {
+		return *r.tx
+	}
+	return r.dbPool
+}
+
+func (r *OutboxRepository) Pending(ctx context.Context, limit int, maxAttempts int) ([]entities.OutboxEvent, error) {
+	sql, args, err := Psql.
+		Select("outbox_id", "character_id", "operation", "attempts").
+		From("search_outbox").
+		Where("attempts < ?", maxAttempts).
+		OrderBy("outbox_id").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building sql: %w", err)
+	}
+
+	rows, err := r.getExecutor().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing query: %w", toDomainError(err))
+	}
+	defer rows.Close()
+
+	events := []entities.OutboxEvent{}
+	for rows.Next() {
+		var event entities.OutboxEvent
+		err := rows.Scan(&event.ID, &event.CharacterID, &event.Operation, &event.Attempts)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", toDomainError(err))
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading rows: %w", toDomainError(err))
+	}
+	return events, nil
+}
+
+func (r *OutboxRepository) Done(ctx context.Context, eventIDs []int64) error {
+	sql, args, err := Psql.
+		Delete("search_outbox").
+		Where(sq.Eq{"outbox_id": eventIDs}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOutboxRepoPersistenceFailure, err)
+	}
+	_, err = r.getExecutor().Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrOutboxRepoPersistenceFailure, toDomainError(err))
+	}
+	return nil
+}
+
+func (r *OutboxRepository) Failed(ctx context.Context, eventIDs []int64, reason string) error {
+	sql, args, err := Psql.
+		Update("search_outbox").
+		Set("attempts", sq.Expr("attempts + 1")).
+		Set("last_error", reason).
+		Where(sq.Eq{"outbox_id": eventIDs}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOutboxRepoPersistenceFailure, err)
+	}
+	_, err = r.getExecutor().Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrOutboxRepoPersistenceFailure, toDomainError(err))
+	}
+	return nil
+}
+
+// enqueueSearchUpdate records that the character changed so the indexer
+// updates the search index, and refreshes its stored search document. Call
+// it with the executor of the write, after it, so all commit or roll back
+// together.
+func enqueueSearchUpdate(ctx context.Context, executor PGXExecutor, characterID int, operation entities.OutboxOperation) error {
+	if operation == entities.OutboxUpsert {
+		// deleted characters lose theirs by cascade
+		_, err := executor.Exec(ctx, "SELECT refresh_search_documents($1)", []int{characterID})
+		if err != nil {
+			return fmt.Errorf("refresh search document %w: %w", ErrOutboxRepoPersistenceFailure, toDomainError(err))
+		}
+	}
+
+	sql, args, err := Psql.
+		Insert("search_outbox").
+		Columns("character_id", "operation").
+		Values(characterID, string(operation)).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOutboxRepoPersistenceFailure, err)
+	}
+	_, err = executor.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrOutboxRepoPersistenceFailure, toDomainError(err))
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/vitalii-komenda/got/entities"
+)
+
+func (s *CharsetTestSuite) TestSearchOutbox() {
+	ctx := context.Background()
+	outbox := NewOutboxRepository(s.dbpool).WithTX(s.tx)
+	_, err := (*s.tx).Exec(ctx, "DELETE FROM search_outbox")
+	s.Require().NoError(err)
+
+	characterEntryEntry := entities.CharacterEntry{CharacterName: "Test Character 2"}
+	err = s.repo.CreateCharacterAndActor(ctx, &characterEntryEntry)
+	s.Require().NoError(err)
+	err = s.repo.Delete(ctx, 1)
+	s.Require().NoError(err)
+
+	events, err := outbox.Pending(ctx, 10, 3)
+	s.Require().NoError(err)
+	s.Require().Len(events, 2)
+	s.Require().Equal(characterEntryEntry.CharacterID, events[0].CharacterID)
+	s.Require().Equal(entities.OutboxUpsert, events[0].Operation)
+	s.Require().Equal(1, events[1].CharacterID)
+	s.Require().Equal(entities.OutboxDelete, events[1].Operation)
+
+	err = outbox.Failed(ctx, []int64{events[0].ID}, "boom")
+	s.Require().NoError(err)
+	err = outbox.Done(ctx, []int64{events[1].ID})
+	s.Require().NoError(err)
+
+	events, err = outbox.Pending(ctx, 10, 1)
+	s.Require().NoError(err)
+	s.Require().Empty(events)
+}
+
+func (s *CharsetTestSuite) TestSearchOutboxRelatedCharacters() {
+	ctx := context.Background()
+	outbox := NewOutboxRepository(s.dbpool).WithTX(s.tx)
+	relationshipsRepo := NewRelationshipsRepository(s.dbpool, s.repo).WithTX(s.tx)
+
+	child := entities.CharacterEntry{CharacterName: "Test Child"}
+	err := s.repo.CreateCharacterAndActor(ctx, &child)
+	s.Require().NoError(err)
+	err = relationshipsRepo.AddParent(ctx, child.CharacterID, 1)
+	s.Require().NoError(err)
+
+	pending := func() []entities.OutboxEvent {
+		events, err := outbox.Pending(ctx, 10, 3)
+		s.Require().NoError(err)
+		return events
+	}
+
+	_, err = (*s.tx).Exec(ctx, "DELETE FROM search_outbox")
+	s.Require().NoError(err)
+	parent := entities.CharacterEntry{CharacterName: "Renamed Parent"}
+	_, err = s.repo.UpdateCharacterAndActor(ctx, &parent, 1)
+	s.Require().NoError(err)
+	events := pending()
+	s.Require().Len(events, 2)
+	s.Require().Equal(1, events[0].CharacterID)
+	s.Require().Equal(child.CharacterID, events[1].CharacterID)
+	s.Require().Equal(entities.OutboxUpsert, events[1].Operation)
+
+	_, err = (*s.tx).Exec(ctx, "DELETE FROM search_outbox")
+	s.Require().NoError(err)
+	err = s.repo.Delete(ctx, 1)
+	s.Require().NoError(err)
+	events = pending()
+	s.Require().Len(events, 2)
+	s.Require().Equal(child.CharacterID, events[0].CharacterID)
+	s.Require().Equal(entities.OutboxUpsert, events[0].Operation)
+	s.Require().Equal(1, events[1].CharacterID)
+	s.Require().Equal(entities.OutboxDelete, events[1].Operation)
+}
@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	entities "github.com/vitalii-komenda/got/entities"
+)
+
+// containsWords matches normalized text containing the value as whole words,
+// like a match_phrase query in Elasticsearch.
+const containsWords = "(' ' || %s || ' ') LIKE '%% ' || normalize_name(?) || ' %%'"
+
+var comparisons = map[entities.QueryOp]string{
+	entities.OpMatch:        "=",
+	entities.OpGreater:      ">",
+	entities.OpGreaterEqual: ">=",
+	entities.OpLess:         "<",
+	entities.OpLessEqual:    "<=",
+}
+
+// textCondition matches the text against the search document d, as whole
+// words or with misspellings. Both operators are served by the indexes of
+// character_search_documents; <% is word_similarity above
+// pg_trgm.word_similarity_threshold, like in Suggest.
+func textCondition(text string) sq.Sqlizer {
+	return sq.Or{
+		sq.Expr("d.document @@ plainto_tsquery('simple', normalize_name(?))", text),
+		sq.Expr("normalize_name(?) <% d.words", text),
+	}
+}
+
+// queryCondition compiles a structured query to a condition on the search
+// document d and its character c. The free text of an AndNode is matched
+// as a whole, the same as a plain search term.
+func queryCondition(node entities.QueryNode) (sq.Sqlizer, error) {
+	switch n := node.(type) {
+	case entities.TextNode:
+		return textCondition(n.Text), nil
+	case entities.AndNode:
+		conditions := sq.And{}
+		if text := entities.QueryText(n); text != "" {
+			conditions = append(conditions, textCondition(text))
+		}
+		for _, child := range n.Nodes {
+			if _, ok := child.(entities.TextNode); ok {
+				continue
+			}
+			condition, err := queryCondition(child)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, condition)
+		}
+		return conditions, nil
+	case entities.NotNode:
+		condition, err := queryCondition(n.Node)
+		if err != nil {
+			return nil, err
+		}
+		return sq.Expr("NOT (?)", condition), nil
+	case entities.FieldNode:
+		return fieldCondition(n)
+	default:
+		return nil, fmt.Errorf("unknown query node %T", node)
+	}
+}
+
+func fieldCondition(n entities.FieldNode) (sq.Sqlizer, error) {
+	switch n.Field {
+	case entities.FieldName:
+		return sq.Expr(fmt.Sprintf(containsWords, "c.character_name_normalized"), n.Value), nil
+	case entities.FieldAlias:
+		return sq.Expr(`EXISTS(
+			SELECT 1 FROM character_aliases AS qal
+			WHERE qal.character_id = c.character_id AND `+fmt.Sprintf(containsWords, "qal.alias_normalized")+`
+		)`, n.Value), nil
+	case entities.FieldHouse:
+		return sq.Expr(`EXISTS(
+			SELECT 1 FROM unnest(string_to_array(c.house_name, ',')) AS qh(house)
+			WHERE `+fmt.Sprintf(containsWords, "normalize_name(qh.house)")+`
+		)`, n.Value), nil
+	case entities.FieldActor:
+		return sq.Expr(`EXISTS(
+			SELECT 1 FROM characters_actors AS qca
+			JOIN actors AS qa ON qa.actor_id = qca.actor_id
+			WHERE qca.character_id = c.character_id AND `+fmt.Sprintf(containsWords, "qa.actor_name_normalized")+`
+		)`, n.Value), nil
+	case entities.FieldRoyal:
+		return sq.Expr("COALESCE(c.royal, false) = ?", n.Bool), nil
+	case entities.FieldAlive:
+		// the dead are the characters someone killed
+		return sq.Expr(`EXISTS(
+			SELECT 1 FROM relationships AS qr
+			WHERE qr.character_id = c.character_id AND qr.relationship_type = 'killed_by'
+		) = ?`, !n.Bool), nil
+	case entities.FieldKilled:
+		return sq.Expr(fmt.Sprintf(`(
+			SELECT count(DISTINCT qr.character_relationship_id) FROM relationships AS qr
+			WHERE qr.character_id = c.character_id AND qr.relationship_type = 'killed'
+		) %s ?`, comparisons[n.Op]), n.Number), nil
+	default:
+		return nil, fmt.Errorf("unknown query field %q", n.Field)
+	}
+}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/jackc/pgx/v4"
@@ -30,7 +31,7 @@ func (r *RelationshipsRepository) WithTX(tx *pgx.Tx) *RelationshipsRepository {
 	return &RelationshipsRepository{
 		dbPool:         r.dbPool,
 		tx:             tx,
-		charactersRepo: r.charactersRepo,
+		charactersRepo: r.charactersRepo.WithTX(tx),
 	}
 }
 
@@ -52,7 +53,7 @@ func (r *RelationshipsRepository) AddRelationship(ctx context.Context, character
 	}
 	_, err = r.getExecutor().Exec(ctx, sql, args...)
 	if err != nil {
-		return fmt.Errorf("%w: %v", ErrRelationshipsRepository, err)
+		return fmt.Errorf("%w: %w", ErrRelationshipsRepository, toDomainError(err))
 	}
 	return nil
 }
@@ -69,102 +70,155 @@ func (r *RelationshipsRepository) AddKilled(ctx context.Context, characterID int
 	return r.AddRelationship(ctx, characterID, characterKilledId, "killed")
 }
 
+func (r *RelationshipsRepository) AddKilledBy(ctx context.Context, characterID int, characterKillerId int) error {
+	return r.AddRelationship(ctx, characterID, characterKillerId, "killed_by")
+}
+
 func (r *RelationshipsRepository) AddMarriedEngaged(ctx context.Context, characterID int, characterMarriedEngagedId int) error {
 	return r.AddRelationship(ctx, characterID, characterMarriedEngagedId, "married_engaged")
 }
 
 func (r *RelationshipsRepository) AddAll(ctx context.Context, character entities.CharacterEntry) error {
-	characterId, err := r.charactersRepo.GetCharacterID(ctx, character.CharacterName)
-	if err != nil || characterId == 0 {
-		return fmt.Errorf("unable to get character %s %w: %v", character.CharacterName, ErrRelationshipsRepository, err)
+	return inTx(ctx, r.dbPool, r.tx, func(tx *pgx.Tx) error {
+		return r.WithTX(tx).addAll(ctx, character)
+	})
+}
+
+func (r *RelationshipsRepository) addAll(ctx context.Context, character entities.CharacterEntry) error {
+	characterId, err := r.characterID(ctx, character)
+	if err != nil {
+		return err
 	}
 
 	// add siblings
 	for _, sibling := range character.Siblings {
 		siblingFromDB, err := r.charactersRepo.GetCharacterID(ctx, sibling)
-		if err != nil || siblingFromDB == 0 {
+		if errors.Is(err, entities.ErrNotFound) {
 			fmt.Printf("Sibling not found: %v\n", sibling)
 			continue
 		}
+		if err != nil {
+			return fmt.Errorf("unable to get sibling %s %w: %w", sibling, ErrRelationshipsRepository, err)
+		}
 
 		err = r.AddSibling(ctx, characterId, siblingFromDB)
 		if err != nil {
-			return fmt.Errorf("unable to create sibling %s %w: %v", character.CharacterName, ErrRelationshipsRepository, err)
+			return fmt.Errorf("unable to create sibling %s %w: %w", character.CharacterName, ErrRelationshipsRepository, err)
 		}
 	}
 
 	// add parents
 	for _, parent := range character.Parents {
 		parentFromDB, err := r.charactersRepo.GetCharacterID(ctx, parent)
-		if err != nil {
-			return fmt.Errorf("unable to get parent %s %w: %v", parent, ErrRelationshipsRepository, err)
-		}
-		if parentFromDB == 0 {
+		if errors.Is(err, entities.ErrNotFound) {
 			fmt.Printf("Parent not found: %v\n", parent)
 			continue
 		}
+		if err != nil {
+			return fmt.Errorf("unable to get parent %s %w: %w", parent, ErrRelationshipsRepository, err)
+		}
 
 		err = r.AddParent(ctx, characterId, parentFromDB)
 		if err != nil {
-			return fmt.Errorf("unable to create parent %s %w: %v", character.CharacterName, ErrRelationshipsRepository, err)
+			return fmt.Errorf("unable to create parent %s %w: %w", character.CharacterName, ErrRelationshipsRepository, err)
 		}
 	}
 
 	// add killed
 	for _, killed := range character.Killed {
 		killedFromDB, err := r.charactersRepo.GetCharacterID(ctx, killed)
-		if err != nil || killedFromDB == 0 {
+		if errors.Is(err, entities.ErrNotFound) {
 			fmt.Printf("Killed not found: %v\n", killed)
 			continue
 		}
+		if err != nil {
+			return fmt.Errorf("unable to get killed %s %w: %w", killed, ErrRelationshipsRepository, err)
+		}
 
 		err = r.AddKilled(ctx, characterId, killedFromDB)
 		if err != nil {
-			return fmt.Errorf("unable to create killed %s %w: %v", character.CharacterName, ErrRelationshipsRepository, err)
+			return fmt.Errorf("unable to create killed %s %w: %w", character.CharacterName, ErrRelationshipsRepository, err)
+		}
+	}
+
+	// add killed_by
+	for _, killedBy := range character.KilledBy {
+		killedByFromDB, err := r.charactersRepo.GetCharacterID(ctx, killedBy)
+		if errors.Is(err, entities.ErrNotFound) {
+			fmt.Printf("KilledBy not found: %v\n", killedBy)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("unable to get killedBy %s %w: %w", killedBy, ErrRelationshipsRepository, err)
+		}
+
+		err = r.AddKilledBy(ctx, characterId, killedByFromDB)
+		if err != nil {
+			return fmt.Errorf("unable to create killedBy %s %w: %w", character.CharacterName, ErrRelationshipsRepository, err)
 		}
 	}
 
 	// add married_engaged
 	for _, marriedEngaged := range character.MarriedEngaged {
 		marriedEngagedFromDB, err := r.charactersRepo.GetCharacterID(ctx, marriedEngaged)
-		if err != nil {
-			return fmt.Errorf("unable to get marriedEngaged %s %w: %v", marriedEngaged, ErrRelationshipsRepository, err)
-		}
-		if marriedEngagedFromDB == 0 {
+		if errors.Is(err, entities.ErrNotFound) {
 			fmt.Printf("marriedEngaged not found: %v\n", marriedEngaged)
 			continue
 		}
+		if err != nil {
+			return fmt.Errorf("unable to get marriedEngaged %s %w: %w", marriedEngaged, ErrRelationshipsRepository, err)
+		}
 
 		err = r.AddMarriedEngaged(ctx, characterId, marriedEngagedFromDB)
 		if err != nil {
-			return fmt.Errorf("unable to create marriedEngaged %s %w: %v", character.CharacterName, ErrRelationshipsRepository, err)
+			return fmt.Errorf("unable to create marriedEngaged %s %w: %w", character.CharacterName, ErrRelationshipsRepository, err)
 		}
 	}
 
-	return nil
+	return enqueueSearchUpdate(ctx, r.getExecutor(), characterId, entities.OutboxUpsert)
 }
 
 func (r *RelationshipsRepository) UpdateAll(ctx context.Context, character entities.CharacterEntry) error {
-	characterId, err := r.charactersRepo.GetCharacterID(ctx, character.CharacterName)
-	if err != nil || characterId == 0 {
-		return fmt.Errorf("unable to get character %s %w: %v", character.CharacterName, ErrRelationshipsRepository, err)
+	return inTx(ctx, r.dbPool, r.tx, func(tx *pgx.Tx) error {
+		return r.WithTX(tx).updateAll(ctx, character)
+	})
+}
+
+func (r *RelationshipsRepository) updateAll(ctx context.Context, character entities.CharacterEntry) error {
+	characterId, err := r.characterID(ctx, character)
+	if err != nil {
+		return err
 	}
+	character.CharacterID = characterId
 
 	// delete all relationships
 	err = r.DeleteAll(ctx, characterId)
 	if err != nil {
-		return fmt.Errorf("unable to delete all relationships %w: %v", ErrRelationshipsRepository, err)
+		return fmt.Errorf("unable to delete all relationships %w: %w", ErrRelationshipsRepository, err)
 	}
 
 	// add all relationships
-	err = r.AddAll(ctx, character)
+	err = r.addAll(ctx, character)
 	if err != nil {
-		return fmt.Errorf("unable to add all relationships %w: %v", ErrRelationshipsRepository, err)
+		return fmt.Errorf("unable to add all relationships %w: %w", ErrRelationshipsRepository, err)
 	}
 
 	return nil
 }
 
+// characterID prefers the ID already set on the entry and falls back to a
+// lookup by name for callers that only know the name, like the importer.
+func (r *RelationshipsRepository) characterID(ctx context.Context, character entities.CharacterEntry) (int, error) {
+	if character.CharacterID != 0 {
+		return character.CharacterID, nil
+	}
+	characterId, err := r.charactersRepo.GetCharacterID(ctx, character.CharacterName)
+	if err != nil {
+		return 0, fmt.Errorf("unable to get character %s %w: %w", character.CharacterName, ErrRelationshipsRepository, err)
+	}
+	return characterId, nil
+}
+
 func (r *RelationshipsRepository) DeleteAll(ctx context.Context, characterID int) error {
 	sql, args, err := Psql.
 		Delete("relationships").
@@ -175,7 +229,7 @@ func (r *RelationshipsRepository) DeleteAll(ctx context.Context, characterID int
 	}
 	_, err = r.getExecutor().Exec(ctx, sql, args...)
 	if err != nil {
-		return fmt.Errorf("%w: %v", ErrRelationshipsRepository, err)
+		return fmt.Errorf("%w: %w", ErrRelationshipsRepository, toDomainError(err))
 	}
 
 	return nil
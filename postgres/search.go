@@ -0,0 +1,159 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	entities "github.com/vitalii-komenda/got/entities"
+)
+
+var _ entities.Searcher = &CharactersRepository{}
+var _ entities.Suggester = &CharactersRepository{}
+
+// Search matches the term against the stored search documents of names,
+// aliases, nicknames, actors and houses, using full text search for whole
+// words and trigram word similarity for misspellings, best matches first.
+// The conditions of a structured query narrow the matches down.
+func (r *CharactersRepository) Search(ctx context.Context, query entities.SearchQuery) ([]entities.CharacterEntry, error) {
+	condition, err := queryCondition(query.Node())
+	if err != nil {
+		return nil, err
+	}
+	text := entities.QueryText(query.Node())
+
+	sql, args, err := Psql.
+		Select("d.character_id").
+		From("character_search_documents AS d").
+		Join("characters AS c ON c.character_id = d.character_id").
+		Where(condition).
+		OrderByClause(
+			"ts_rank(d.document, plainto_tsquery('simple', normalize_name(?))) + word_similarity(normalize_name(?), d.words) DESC, c.character_name, d.character_id",
+			text, text,
+		).
+		Limit(25).
+		Offset(uint64(query.Page) * 25).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building sql: %w", err)
+	}
+
+	rows, err := r.getExecutor().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing query: %w", toDomainError(err))
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", toDomainError(err))
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading rows: %w", toDomainError(err))
+	}
+	if len(ids) == 0 {
+		return []entities.CharacterEntry{}, nil
+	}
+
+	return r.GetByIDs(ctx, ids)
+}
+
+// suggestionsQuery lists every name that can be suggested as name, type,
+// slug, rank of the type and normalized name.
+func suggestionsQuery() sq.SelectBuilder {
+	characters := Psql.
+		Select("c.character_name", fmt.Sprintf("'%s'", entities.SuggestionCharacter), "c.slug", "0", "c.character_name_normalized").
+		From("characters AS c")
+	aliases := Psql.
+		Select("al.alias", fmt.Sprintf("'%s'", entities.SuggestionAlias), "c.slug", "1", "al.alias_normalized").
+		From("character_aliases AS al").
+		Join("characters AS c ON c.character_id = al.character_id")
+	actors := Psql.
+		Select("a.actor_name", fmt.Sprintf("'%s'", entities.SuggestionActor), "''", "2", "a.actor_name_normalized").
+		From("actors AS a")
+	houses := Psql.
+		Select("min(btrim(house))", fmt.Sprintf("'%s'", entities.SuggestionHouse), "''", "3", "normalize_name(house)").
+		From("characters AS c").
+		CrossJoin("unnest(string_to_array(c.house_name, ',')) AS house").
+		Where("btrim(house) <> ''").
+		GroupBy("normalize_name(house)")
+
+	return characters.Suffix("UNION ALL ? UNION ALL ? UNION ALL ?", aliases, actors, houses)
+}
+
+// Suggest completes the prefix with names starting with it and, for typos,
+// names containing a word similar to it. Prefix matches come first, then
+// characters, aliases, actors and houses.
+func (r *CharactersRepository) Suggest(ctx context.Context, prefix string, size int) ([]entities.Suggestion, error) {
+	sql, args, err := Psql.
+		Select("s.name", "s.type", "s.slug").
+		FromSelect(suggestionsQuery(), "s(name, type, slug, rank, normalized)").
+		Where(sq.Or{
+			sq.Expr("s.normalized LIKE normalize_name(?) || '%'", prefix),
+			sq.Expr("normalize_name(?) <% s.normalized", prefix),
+		}).
+		OrderByClause(
+			"(s.normalized LIKE normalize_name(?) || '%') DESC, s.rank, word_similarity(normalize_name(?), s.normalized) DESC, length(s.name), s.name",
+			prefix, prefix,
+		).
+		Limit(uint64(size)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building sql: %w", err)
+	}
+
+	rows, err := r.getExecutor().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing query: %w", toDomainError(err))
+	}
+	defer rows.Close()
+
+	suggestions := []entities.Suggestion{}
+	for rows.Next() {
+		var suggestion entities.Suggestion
+		if err := rows.Scan(&suggestion.Name, &suggestion.Type, &suggestion.Slug); err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", toDomainError(err))
+		}
+		if suggestion.Slug == "" {
+			suggestion.Slug = entities.Slugify(suggestion.Name)
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading rows: %w", toDomainError(err))
+	}
+	return suggestions, nil
+}
+
+// GetByIDs loads the characters in the order of the given IDs.
+func (r *CharactersRepository) GetByIDs(ctx context.Context, characterIDs []int) ([]entities.CharacterEntry, error) {
+	sql, args, err := characterDetailsQuery().
+		Where(sq.Eq{"c.character_id": characterIDs}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building sql: %w", err)
+	}
+	characters, err := r.queryCharacterDetails(ctx, sql, args)
+	if err != nil {
+		return nil, err
+	}
+
+	rank := make(map[int]int, len(characterIDs))
+	for i, id := range characterIDs {
+		rank[id] = i
+	}
+	ordered := make([]entities.CharacterEntry, 0, len(characters))
+	byRank := make([][]entities.CharacterEntry, len(characterIDs))
+	for _, character := range characters {
+		i := rank[character.CharacterID]
+		byRank[i] = append(byRank[i], character)
+	}
+	for _, group := range byRank {
+		ordered = append(ordered, group...)
+	}
+	return ordered, nil
+}
@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/vitalii-komenda/got/entities"
+)
+
+func (s *CharsetTestSuite) TestSearch() {
+	ctx := context.Background()
+	characterEntryEntry := entities.CharacterEntry{
+		CharacterName: "Sandor Clegane",
+		Nickname:      "The Hound",
+		ActorName:     "Rory McCann",
+		HouseName:     []string{"House Clegane"},
+	}
+	err := s.repo.CreateCharacterAndActor(ctx, &characterEntryEntry)
+	s.Require().NoError(err)
+
+	for _, term := range []string{"Sandor", "hound", "rory mccann", "clegane", "Sandr Clegan"} {
+		characters, err := s.repo.Search(ctx, entities.SearchQuery{Term: term})
+		s.Require().NoError(err, term)
+		s.Require().NotEmpty(characters, term)
+		s.Require().Equal("Sandor Clegane", characters[0].CharacterName, term)
+	}
+
+	characters, err := s.repo.Search(ctx, entities.SearchQuery{Term: "Daenerys"})
+	s.Require().NoError(err)
+	s.Require().Empty(characters)
+
+	// updates refresh the stored search document
+	characterEntryEntry.Nickname = "Dog"
+	characterEntryEntry.Aliases = nil
+	_, err = s.repo.UpdateCharacterAndActor(ctx, &characterEntryEntry, characterEntryEntry.CharacterID)
+	s.Require().NoError(err)
+	characters, err = s.repo.Search(ctx, entities.SearchQuery{Term: "dog"})
+	s.Require().NoError(err)
+	s.Require().NotEmpty(characters)
+	s.Require().Equal("Sandor Clegane", characters[0].CharacterName)
+}
+
+func (s *CharsetTestSuite) TestSuggest() {
+	ctx := context.Background()
+	characterEntryEntry := entities.CharacterEntry{
+		CharacterName: "Sandor Clegane",
+		Nickname:      "The Hound",
+		ActorName:     "Rory McCann",
+		HouseName:     []string{"House Clegane"},
+	}
+	err := s.repo.CreateCharacterAndActor(ctx, &characterEntryEntry)
+	s.Require().NoError(err)
+
+	cases := map[string]entities.Suggestion{
+		"sand":       {Name: "Sandor Clegane", Type: entities.SuggestionCharacter, Slug: "sandor-clegane"},
+		"the h":      {Name: "The Hound", Type: entities.SuggestionAlias, Slug: "sandor-clegane"},
+		"rory":       {Name: "Rory McCann", Type: entities.SuggestionActor, Slug: "rory-mccann"},
+		"house cleg": {Name: "House Clegane", Type: entities.SuggestionHouse, Slug: "house-clegane"},
+		"sandr":      {Name: "Sandor Clegane", Type: entities.SuggestionCharacter, Slug: "sandor-clegane"},
+	}
+	for prefix, expected := range cases {
+		suggestions, err := s.repo.Suggest(ctx, prefix, 5)
+		s.Require().NoError(err, prefix)
+		s.Require().NotEmpty(suggestions, prefix)
+		s.Require().Equal(expected, suggestions[0], prefix)
+	}
+}
+
+func (s *CharsetTestSuite) TestSearchStructured() {
+	ctx := context.Background()
+	characterEntryEntry := entities.CharacterEntry{
+		CharacterName: "Sandor Clegane",
+		Nickname:      "The Hound",
+		ActorName:     "Rory McCann",
+		HouseName:     []string{"House Clegane"},
+	}
+	err := s.repo.CreateCharacterAndActor(ctx, &characterEntryEntry)
+	s.Require().NoError(err)
+
+	search := func(term string) []string {
+		query, err := entities.ParseQuery(term)
+		s.Require().NoError(err, term)
+		characters, err := s.repo.Search(ctx, entities.SearchQuery{Term: term, Query: query})
+		s.Require().NoError(err, term)
+		var names []string
+		for _, character := range characters {
+			names = append(names, character.CharacterName)
+		}
+		return names
+	}
+
+	s.Require().Equal([]string{"Sandor Clegane"}, search(`house:clegane actor:"Rory McCann" royal:false alive:true killed:0`))
+	s.Require().Equal([]string{"Sandor Clegane"}, search("hound -house:Stark"))
+	s.Require().Equal([]string{"Test Character"}, search("house:test"))
+	s.Require().Empty(search("hound alias:mountain"))
+	s.Require().Empty(search("house:Clegane killed:>0"))
+}
+
+func (s *CharsetTestSuite) TestSimilar() {
+	ctx := context.Background()
+	sandor := entities.CharacterEntry{CharacterName: "Sandor Clegane", ActorName: "Rory McCann", HouseName: []string{"House Clegane"}}
+	gregor := entities.CharacterEntry{CharacterName: "Gregor Clegane", ActorName: "Hafþór Júlíus Björnsson", HouseName: []string{"House Clegane"}}
+	// only the name is alike, nothing is shared
+	namesake := entities.CharacterEntry{CharacterName: "Sandor Cleganes", ActorName: "Someone Else"}
+	s.Require().NoError(s.repo.CreateCharacterAndActor(ctx, &sandor))
+	s.Require().NoError(s.repo.CreateCharacterAndActor(ctx, &gregor))
+	s.Require().NoError(s.repo.CreateCharacterAndActor(ctx, &namesake))
+
+	similar, err := s.repo.Similar(ctx, sandor.CharacterID, 5)
+	s.Require().NoError(err)
+	s.Require().NotEmpty(similar)
+	s.Equal("Gregor Clegane", similar[0].CharacterName)
+	s.Contains(similar[0].Reasons, "shares House Clegane")
+	for _, character := range similar {
+		s.NotEqual(sandor.CharacterID, character.CharacterID)
+		s.NotEqual(namesake.CharacterID, character.CharacterID)
+	}
+}
@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	entities "github.com/vitalii-komenda/got/entities"
+)
+
+var _ entities.SimilarFinder = &CharactersRepository{}
+
+// Weights of what two characters have in common.
+const (
+	sharedHouseWeight    = 3
+	relatedWeight        = 4
+	sharedRelativeWeight = 2
+	sharedActorWeight    = 3
+	sharedSeasonWeight   = 0.25
+	textWeight           = 2
+)
+
+// similarQuery scores the characters sharing a house, a relationship, a
+// relative or an actor with the character $1 by what they share and by how
+// alike their names, aliases and actors read, and returns the best $2.
+// Everyone else would only score by seasons and text, so they are not
+// looked at.
+const similarQuery = `
+WITH target AS (
+	SELECT t.character_id, t.character_name, td.words,
+		ARRAY(SELECT normalize_name(th) FROM unnest(string_to_array(t.house_name, ',')) AS th) AS houses
+	FROM characters AS t
+	JOIN character_search_documents AS td ON td.character_id = t.character_id
+	WHERE t.character_id = $1
+),
+candidates AS (
+	SELECT o.character_id FROM characters AS o, target AS t
+	WHERE EXISTS(SELECT 1 FROM unnest(string_to_array(o.house_name, ',')) AS oh WHERE normalize_name(oh) = ANY(t.houses))
+	UNION
+	SELECT character_relationship_id FROM relationships WHERE character_id = $1
+	UNION
+	SELECT character_id FROM relationships WHERE character_relationship_id = $1
+	UNION
+	SELECT ro.character_id FROM relationships AS rt
+	JOIN relationships AS ro ON ro.character_relationship_id = rt.character_relationship_id
+	WHERE rt.character_id = $1
+	UNION
+	SELECT ao.character_id FROM characters_actors AS at
+	JOIN characters_actors AS ao ON ao.actor_id = at.actor_id
+	WHERE at.character_id = $1
+),
+shared AS (
+	SELECT
+		o.character_id,
+		o.character_name,
+		t.character_name AS target_name,
+		ARRAY(
+			SELECT DISTINCT btrim(oh) FROM unnest(string_to_array(o.house_name, ',')) AS oh
+			WHERE normalize_name(oh) = ANY(t.houses)
+		) AS houses,
+		EXISTS(
+			SELECT 1 FROM relationships AS r
+			WHERE (r.character_id = t.character_id AND r.character_relationship_id = o.character_id)
+				OR (r.character_id = o.character_id AND r.character_relationship_id = t.character_id)
+		) AS related,
+		ARRAY(
+			SELECT DISTINCT rc.character_name FROM relationships AS ro
+			JOIN relationships AS rt ON rt.character_relationship_id = ro.character_relationship_id
+			JOIN characters AS rc ON rc.character_id = ro.character_relationship_id
+			WHERE ro.character_id = o.character_id AND rt.character_id = t.character_id
+		) AS relatives,
+		ARRAY(
+			SELECT DISTINCT a.actor_name FROM characters_actors AS ao
+			JOIN characters_actors AS at ON at.actor_id = ao.actor_id
+			JOIN actors AS a ON a.actor_id = ao.actor_id
+			WHERE ao.character_id = o.character_id AND at.character_id = t.character_id
+		) AS actors,
+		(
+			SELECT count(DISTINCT season) FROM characters_actors AS ao
+			CROSS JOIN unnest(ao.seasons_active) AS season
+			WHERE ao.character_id = o.character_id AND season IN (
+				SELECT unnest(at.seasons_active) FROM characters_actors AS at WHERE at.character_id = t.character_id
+			)
+		) AS seasons,
+		similarity(od.words, t.words) AS text_similarity
+	FROM candidates AS c
+	JOIN characters AS o ON o.character_id = c.character_id
+	JOIN character_search_documents AS od ON od.character_id = o.character_id
+	CROSS JOIN target AS t
+	WHERE o.character_id <> t.character_id
+)
+SELECT character_id, character_name, target_name, houses, related, relatives, actors, seasons,
+	cardinality(houses) * $3::float8
+		+ CASE WHEN related THEN $4::float8 ELSE 0 END
+		+ cardinality(relatives) * $5::float8
+		+ cardinality(actors) * $6::float8
+		+ seasons * $7::float8
+		+ text_similarity * $8::float8 AS score
+FROM shared
+ORDER BY score DESC, character_name
+LIMIT $2
+`
+
+// Similar finds the characters most like the character, see similarQuery.
+func (r *CharactersRepository) Similar(ctx context.Context, characterID int, size int) ([]entities.SimilarCharacter, error) {
+	rows, err := r.getExecutor().Query(ctx, similarQuery, characterID, size,
+		sharedHouseWeight, relatedWeight, sharedRelativeWeight, sharedActorWeight, sharedSeasonWeight, textWeight)
+	if err != nil {
+		return nil, fmt.Errorf("error executing query: %w", toDomainError(err))
+	}
+	defer rows.Close()
+
+	var similar []entities.SimilarCharacter
+	for rows.Next() {
+		var (
+			character                 entities.SimilarCharacter
+			characterName             string
+			houses, relatives, actors []string
+			related                   bool
+			seasons                   int
+		)
+		err := rows.Scan(&character.CharacterID, &character.CharacterName, &characterName,
+			&houses, &related, &relatives, &actors, &seasons, &character.Score)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", toDomainError(err))
+		}
+
+		for _, house := range houses {
+			character.Reasons = append(character.Reasons, "shares "+house)
+		}
+		if related {
+			character.Reasons = append(character.Reasons, "related to "+characterName)
+		}
+		for _, relative := range relatives {
+			character.Reasons = append(character.Reasons, "also related to "+relative)
+		}
+		for _, actor := range actors {
+			character.Reasons = append(character.Reasons, "also played by "+actor)
+		}
+		if seasons > 0 {
+			character.Reasons = append(character.Reasons, fmt.Sprintf("on screen in %d of the same seasons", seasons))
+		}
+		similar = append(similar, character)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading rows: %w", toDomainError(err))
+	}
+	return r.withDetails(ctx, similar)
+}
+
+// withDetails fills in the characters, with their first actor.
+func (r *CharactersRepository) withDetails(ctx context.Context, similar []entities.SimilarCharacter) ([]entities.SimilarCharacter, error) {
+	if len(similar) == 0 {
+		return []entities.SimilarCharacter{}, nil
+	}
+	ids := make([]int, len(similar))
+	for i, character := range similar {
+		ids[i] = character.CharacterID
+	}
+	characters, err := r.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	details := map[int]entities.CharacterEntry{}
+	for _, character := range characters {
+		if _, ok := details[character.CharacterID]; !ok {
+			details[character.CharacterID] = character
+		}
+	}
+	for i := range similar {
+		similar[i].CharacterEntry = details[similar[i].CharacterID]
+	}
+	return similar, nil
+}
@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/vitalii-komenda/got/entities"
+)
+
+var _ entities.CharacterTransactor = &Transactor{}
+
+func NewTransactor(dbpool *pgxpool.Pool, charactersRepo *CharactersRepository, relationshipsRepo *RelationshipsRepository) *Transactor {
+	return &Transactor{
+		dbPool:            dbpool,
+		charactersRepo:    charactersRepo,
+		relationshipsRepo: relationshipsRepo,
+	}
+}
+
+// Transactor hands out the character and relationship repositories bound
+// to one transaction.
+type Transactor struct {
+	dbPool            *pgxpool.Pool
+	charactersRepo    *CharactersRepository
+	relationshipsRepo *RelationshipsRepository
+}
+
+func (t *Transactor) InTx(ctx context.Context, fn func(characters entities.CharactersRepository, relationships entities.RelationshipsRepository) error) error {
+	return inTx(ctx, t.dbPool, nil, func(tx *pgx.Tx) error {
+		return fn(t.charactersRepo.WithTX(tx), t.relationshipsRepo.WithTX(tx))
+	})
+}
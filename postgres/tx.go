@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// inTx runs fn in the repository's transaction, or in a new one when the
+// repository has none, so a write and its search_outbox event commit
+// together.
+func inTx(ctx context.Context, dbPool *pgxpool.Pool, tx *pgx.Tx, fn func(tx *pgx.Tx) error) error {
+	if tx != nil {
+		return fn(tx)
+	}
+
+	newTx, err := dbPool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", toDomainError(err))
+	}
+	defer newTx.Rollback(ctx)
+
+	err = fn(&newTx)
+	if err != nil {
+		return err
+	}
+	err = newTx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("error committing transaction: %w", toDomainError(err))
+	}
+	return nil
+}
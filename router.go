@@ -23,11 +23,21 @@ func setupRouter(allControllers AllControllers) *gin.Engine {
 	})
 	r.GET("/characters", allControllers.CharactersController.GetAll)
 	r.GET("/characters/:name", allControllers.CharactersController.Get)
+	r.GET("/characters/id/:id", allControllers.CharactersController.GetByID)
+	r.GET("/characters/:name/similar", allControllers.SearchController.Similar)
 	r.POST("/characters", allControllers.CharactersController.Post)
 	r.DELETE("/characters/:name", allControllers.CharactersController.Delete)
 	r.PUT("/characters/:name", allControllers.CharactersController.Put)
+	r.PATCH("/characters/:name", allControllers.CharactersController.Patch)
 
+	r.GET("/actors", allControllers.ActorsController.GetAll)
+	r.GET("/actors/:name", allControllers.ActorsController.Get)
+	r.GET("/actors/:name/characters", allControllers.ActorsController.GetCharacters)
+
+	r.GET("/search", allControllers.SearchController.Search)
+	r.GET("/search/suggest", allControllers.SearchController.Suggest)
 	r.GET("/elastic/search", allControllers.SearchController.GetFromElastic)
+	r.GET("/v1/elastic/search", allControllers.SearchController.GetFromElasticV1)
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vitalii-komenda/got/entities"
+)
+
+// ErrCircuitOpen is returned without sending the request while a service
+// is failing, so callers fail fast instead of piling up on timeouts.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// CircuitBreaker opens after Threshold failures in a row. While open it
+// rejects requests, after Cooldown it lets a single trial through and
+// closes again when the trial succeeds. A trial that never reports back
+// is replaced by another one after Cooldown.
+type CircuitBreaker struct {
+	// Threshold is the number of failures in a row that opens it.
+	Threshold int
+	// Cooldown is how long it stays open before a trial.
+	Cooldown time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	trialAt  time.Time
+	now      func() time.Time
+}
+
+func NewCircuitBreaker() *CircuitBreaker {
+	return &CircuitBreaker{
+		Threshold: 5,
+		Cooldown:  10 * time.Second,
+		now:       time.Now,
+	}
+}
+
+// Allow tells whether a request may be sent.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.Threshold {
+		return nil
+	}
+	now := b.now()
+	wait := b.openedAt.Add(b.Cooldown).Sub(now)
+	if wait <= 0 && now.Sub(b.trialAt) >= b.Cooldown {
+		b.trialAt = now
+		return nil
+	}
+	return fmt.Errorf("%w: %w", ErrCircuitOpen,
+		entities.NewUnavailableError("elasticsearch keeps failing, trying again in %s", max(wait, 0).Round(time.Second)))
+}
+
+// Success closes the breaker.
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.trialAt = time.Time{}
+}
+
+// Failure counts a failure, opening the breaker at the threshold and
+// reopening it when a trial failed.
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.failures >= b.Threshold {
+		b.openedAt = b.now()
+		b.trialAt = time.Time{}
+	}
+}
@@ -1,74 +1,381 @@
 package services
 
 import (
-	"bytes"
-	"encoding/json"
-	"io"
-	"net/http"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/vitalii-komenda/got/entities"
-	"github.com/vitalii-komenda/got/utils"
 )
 
-func SendElasticSearchRequest(term string) ([]entities.CharacterEntryElastic, error) {
-	query := map[string]interface{}{
-		"query": map[string]interface{}{
-			"multi_match": map[string]interface{}{
-				"query":     term,
-				"fields":    []string{"character_name^2", "actor_name", "siblings"},
-				"fuzziness": "AUTO",
-			},
-		},
+var _ entities.Searcher = &ElasticSearcher{}
+var _ entities.Suggester = &ElasticSearcher{}
+var _ entities.FacetedSearcher = &ElasticSearcher{}
+var _ entities.SimilarFinder = &ElasticSearcher{}
+
+// characterDetailsIndex is the index the Indexer keeps in sync with Postgres.
+const characterDetailsIndex = "character_details"
+
+// ElasticSearcher searches the character_details index the Indexer fills
+// from Postgres.
+type ElasticSearcher struct {
+	client *ElasticClient
+}
+
+func NewElasticSearcher(host string) *ElasticSearcher {
+	return &ElasticSearcher{
+		client: NewElasticClient(host),
 	}
+}
 
-	queryJSON, err := json.Marshal(query)
+func (s *ElasticSearcher) Search(ctx context.Context, query entities.SearchQuery) ([]entities.CharacterEntry, error) {
+	result, err := s.Query(ctx, entities.ElasticSearchQuery{Term: query.Term, Query: query.Node(), From: query.Page * 25, Size: 25})
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("GET", utils.MustGetEnvOrPanic("ELASTICSEARCH_HOST")+"/character_details/_search?pretty", bytes.NewBuffer(queryJSON))
+	characters := make([]entities.CharacterEntry, len(result.Hits))
+	for i, hit := range result.Hits {
+		characters[i] = hit.CharacterEntry()
+	}
+	return characters, nil
+}
+
+// suggestionWeights rank the inputs of the suggest completion field,
+// character names before aliases, actors and houses.
+var suggestionWeights = map[string]int{
+	entities.SuggestionCharacter: 10,
+	entities.SuggestionAlias:     8,
+	entities.SuggestionActor:     5,
+	entities.SuggestionHouse:     3,
+}
+
+// characterDocument is what the character_details index stores: the
+// character plus the names it suggests, tagged with their type.
+type characterDocument struct {
+	entities.CharacterEntryElastic
+	Suggest []completionInput `json:"suggest,omitempty"`
+}
+
+type completionInput struct {
+	Input    []string            `json:"input"`
+	Weight   int                 `json:"weight"`
+	Contexts map[string][]string `json:"contexts"`
+}
+
+func newCharacterDocument(character entities.CharacterEntry) characterDocument {
+	names := map[string][]string{
+		entities.SuggestionCharacter: {character.CharacterName},
+		entities.SuggestionHouse:     character.HouseName,
+	}
+	for _, alias := range character.AllAliases() {
+		names[entities.SuggestionAlias] = append(names[entities.SuggestionAlias], alias.Name)
+	}
+	for _, actor := range character.AllActors() {
+		names[entities.SuggestionActor] = append(names[entities.SuggestionActor], actor.ActorName)
+	}
+
+	document := characterDocument{CharacterEntryElastic: character.CharacterEntryElastic()}
+	for _, suggestionType := range []string{entities.SuggestionCharacter, entities.SuggestionAlias, entities.SuggestionActor, entities.SuggestionHouse} {
+		var input []string
+		for _, name := range names[suggestionType] {
+			if name = strings.TrimSpace(name); name != "" {
+				input = append(input, name)
+			}
+		}
+		if len(input) == 0 {
+			continue
+		}
+		document.Suggest = append(document.Suggest, completionInput{
+			Input:    input,
+			Weight:   suggestionWeights[suggestionType],
+			Contexts: map[string][]string{"type": {suggestionType}},
+		})
+	}
+	return document
+}
+
+// Suggest completes the prefix with the suggest completion field, which
+// answers from memory and is fast enough to ask on every keystroke.
+func (s *ElasticSearcher) Suggest(ctx context.Context, prefix string, size int) ([]entities.Suggestion, error) {
+	var response suggestResponse
+	err := s.client.Do(ctx, "POST", "/"+characterDetailsIndex+"/_search", map[string]interface{}{
+		"_source": []string{"slug"},
+		"suggest": map[string]interface{}{
+			"names": map[string]interface{}{
+				"prefix": prefix,
+				"completion": map[string]interface{}{
+					"field":           "suggest",
+					"size":            size,
+					"skip_duplicates": true,
+					"contexts": map[string][]string{
+						"type": {entities.SuggestionCharacter, entities.SuggestionAlias, entities.SuggestionActor, entities.SuggestionHouse},
+					},
+				},
+			},
+		},
+	}, &response)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/json")
+	return response.suggestions(), nil
+}
+
+type suggestResponse struct {
+	Suggest struct {
+		Names []struct {
+			Options []struct {
+				Text     string              `json:"text"`
+				Contexts map[string][]string `json:"contexts"`
+				Source   struct {
+					Slug string `json:"slug"`
+				} `json:"_source"`
+			} `json:"options"`
+		} `json:"names"`
+	} `json:"suggest"`
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+func (r suggestResponse) suggestions() []entities.Suggestion {
+	suggestions := []entities.Suggestion{}
+	for _, entry := range r.Suggest.Names {
+		for _, option := range entry.Options {
+			suggestion := entities.Suggestion{Name: option.Text, Type: entities.SuggestionCharacter, Slug: option.Source.Slug}
+			if types := option.Contexts["type"]; len(types) > 0 {
+				suggestion.Type = types[0]
+			}
+			// actors and houses are not characters, the slug is their own
+			if suggestion.Type == entities.SuggestionActor || suggestion.Type == entities.SuggestionHouse {
+				suggestion.Slug = entities.Slugify(option.Text)
+			}
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	return suggestions
+}
+
+// similarFields are what more_like_this compares: whole houses, relatives
+// and actors, and the words of names and aliases.
+var similarFields = []string{
+	"house_name.keyword", "parents.keyword", "siblings.keyword", "married_engaged.keyword",
+	"actor_name.keyword", "character_name", "aliases",
+}
+
+// Similar finds the characters most like the indexed one with
+// more_like_this, leaving the character itself out.
+func (s *ElasticSearcher) Similar(ctx context.Context, characterID int, size int) ([]entities.SimilarCharacter, error) {
+	id := strconv.Itoa(characterID)
+	var response searchResponse
+	err := s.client.Do(ctx, "POST", "/"+characterDetailsIndex+"/_search", map[string]interface{}{
+		"size": size,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": map[string]interface{}{
+					"more_like_this": map[string]interface{}{
+						"fields":               similarFields,
+						"like":                 []map[string]string{{"_index": characterDetailsIndex, "_id": id}},
+						"min_term_freq":        1,
+						"min_doc_freq":         1,
+						"minimum_should_match": 1,
+					},
+				},
+				"must_not": map[string]interface{}{"ids": map[string]interface{}{"values": []string{id}}},
+			},
+		},
+	}, &response)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, err
+	result := response.result()
+	similar := make([]entities.SimilarCharacter, len(result.Hits))
+	for i, hit := range result.Hits {
+		similar[i] = entities.SimilarCharacter{CharacterEntry: hit.CharacterEntry(), Score: hit.Score}
 	}
+	return similar, nil
+}
+
+// searchFields are the fields the term is matched against, names first.
+var searchFields = []string{"character_name^2", "aliases^2", "actor_name", "siblings"}
 
-	body, err := io.ReadAll(resp.Body)
+// Query runs a faceted search: the term matches searchFields, the filters
+// narrow the hits down and the facets count houses and royals among the
+// characters matching the term and the other facets' filters.
+func (s *ElasticSearcher) Query(ctx context.Context, query entities.ElasticSearchQuery) (entities.ElasticSearchResult, error) {
+	body, err := searchRequestBody(query)
 	if err != nil {
-		return nil, err
+		return entities.ElasticSearchResult{}, err
 	}
 
-	var result map[string]interface{}
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, err
+	var response searchResponse
+	if err := s.client.Do(ctx, "POST", "/"+characterDetailsIndex+"/_search", body, &response); err != nil {
+		return entities.ElasticSearchResult{}, err
 	}
+	return response.result(), nil
+}
 
-	hits := result["hits"].(map[string]interface{})["hits"].([]interface{})
-	var value []entities.CharacterEntryElastic
-	for _, hit := range hits {
-		source := hit.(map[string]interface{})["_source"]
-		sourceJSON, err := json.Marshal(source)
-		if err != nil {
+func searchRequestBody(query entities.ElasticSearchQuery) (map[string]interface{}, error) {
+	var must interface{} = map[string]interface{}{"match_all": map[string]interface{}{}}
+	if query.Query != nil {
+		var err error
+		if must, err = elasticQuery(query.Query); err != nil {
 			return nil, err
 		}
+	} else if query.Term != "" {
+		must = textQuery(query.Term)
+	}
 
-		var characterEntry entities.CharacterEntryElastic
-		err = json.Unmarshal(sourceJSON, &characterEntry)
-		if err != nil {
-			return nil, err
+	// the filters narrow the hits down in post_filter, after the facets
+	// counted the characters matching the term. Each facet applies the other
+	// filters, not its own, so it still offers the values to switch to.
+	var filters []facetFilter
+	if query.House != "" {
+		filters = append(filters, facetFilter{"house", map[string]interface{}{"term": map[string]interface{}{"house_name.keyword": query.House}}})
+	}
+	if query.Royal != nil {
+		filters = append(filters, facetFilter{"royal", map[string]interface{}{"term": map[string]interface{}{"royal": *query.Royal}}})
+	}
+	if query.Alive != nil && *query.Alive {
+		filters = append(filters, facetFilter{"alive", map[string]interface{}{"bool": map[string]interface{}{"must_not": deadQuery}}})
+	} else if query.Alive != nil {
+		filters = append(filters, facetFilter{"alive", deadQuery})
+	}
+
+	var sort []interface{}
+	switch query.Sort {
+	case entities.SortName:
+		sort = []interface{}{map[string]string{"character_name.keyword": "asc"}}
+	case entities.SortNameDesc:
+		sort = []interface{}{map[string]string{"character_name.keyword": "desc"}}
+	default:
+		sort = []interface{}{"_score", map[string]string{"character_name.keyword": "asc"}}
+	}
+
+	size := query.Size
+	if size == 0 {
+		size = 10
+	}
+	body := map[string]interface{}{
+		"from":             query.From,
+		"size":             size,
+		"track_total_hits": true,
+		"sort":             sort,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": must,
+			},
+		},
+		"post_filter": filterQuery(filters, ""),
+		"aggs": map[string]interface{}{
+			"house": map[string]interface{}{
+				"filter": filterQuery(filters, "house"),
+				"aggs":   map[string]interface{}{"values": map[string]interface{}{"terms": map[string]interface{}{"field": "house_name.keyword", "size": 100}}},
+			},
+			"royal": map[string]interface{}{
+				"filter": filterQuery(filters, "royal"),
+				"aggs":   map[string]interface{}{"values": map[string]interface{}{"terms": map[string]interface{}{"field": "royal"}}},
+			},
+		},
+	}
+	if query.Highlight && query.Term != "" {
+		fields := map[string]interface{}{}
+		for _, field := range searchFields {
+			fields[strings.Split(field, "^")[0]] = map[string]interface{}{}
+		}
+		body["highlight"] = map[string]interface{}{
+			"pre_tags":  []string{"<em>"},
+			"post_tags": []string{"</em>"},
+			"fields":    fields,
 		}
-		value = append(value, characterEntry)
 	}
+	if query.Explain {
+		body["explain"] = true
+		// scores are left out when sorting by name otherwise
+		body["track_scores"] = true
+	}
+	return body, nil
+}
 
-	return value, nil
+// facetFilter is a filter of a faceted search and the facet it narrows.
+type facetFilter struct {
+	facet  string
+	clause interface{}
+}
+
+// filterQuery combines the filters, leaving out the one of the facet.
+func filterQuery(filters []facetFilter, except string) map[string]interface{} {
+	clauses := []interface{}{}
+	for _, filter := range filters {
+		if filter.facet != except {
+			clauses = append(clauses, filter.clause)
+		}
+	}
+	return map[string]interface{}{"bool": map[string]interface{}{"filter": clauses}}
+}
+
+type searchResponse struct {
+	Hits struct {
+		Total struct {
+			Value int `json:"value"`
+		} `json:"total"`
+		Hits []struct {
+			Source      entities.CharacterEntryElastic `json:"_source"`
+			Score       *float64                       `json:"_score"`
+			Highlight   map[string][]string            `json:"highlight"`
+			Explanation *entities.Explanation          `json:"_explanation"`
+		} `json:"hits"`
+	} `json:"hits"`
+	Aggregations struct {
+		House filterAggregation `json:"house"`
+		Royal filterAggregation `json:"royal"`
+	} `json:"aggregations"`
+}
+
+// filterAggregation is a facet counted under the other facets' filters.
+type filterAggregation struct {
+	Values termsAggregation `json:"values"`
+}
+
+type termsAggregation struct {
+	Buckets []struct {
+		Key         interface{} `json:"key"`
+		KeyAsString string      `json:"key_as_string"`
+		DocCount    int         `json:"doc_count"`
+	} `json:"buckets"`
+}
+
+func (a termsAggregation) counts() []entities.FacetCount {
+	counts := make([]entities.FacetCount, 0, len(a.Buckets))
+	for _, bucket := range a.Buckets {
+		// booleans come back as 1 and 0 with "true" and "false" as strings
+		value := bucket.KeyAsString
+		if value == "" {
+			value = fmt.Sprint(bucket.Key)
+		}
+		counts = append(counts, entities.FacetCount{Value: value, Count: bucket.DocCount})
+	}
+	return counts
+}
+
+func (r searchResponse) result() entities.ElasticSearchResult {
+	hits := make([]entities.ElasticHit, len(r.Hits.Hits))
+	for i, hit := range r.Hits.Hits {
+		hits[i] = entities.ElasticHit{
+			CharacterEntryElastic: hit.Source,
+			Highlights:            hit.Highlight,
+			Explanation:           hit.Explanation,
+		}
+		// null when sorting by name
+		if hit.Score != nil {
+			hits[i].Score = *hit.Score
+		}
+	}
+	return entities.ElasticSearchResult{
+		Total: r.Hits.Total.Value,
+		Hits:  hits,
+		Facets: entities.ElasticFacets{
+			House: r.Aggregations.House.Values.counts(),
+			Royal: r.Aggregations.Royal.Values.counts(),
+		},
+	}
 }
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/vitalii-komenda/got/entities"
+)
+
+// ElasticError is an error response of Elasticsearch. Overload, 429 and
+// 503, counts as unavailable, anything else as a bad gateway.
+type ElasticError struct {
+	Status int
+	Type   string
+	Reason string
+}
+
+func (e *ElasticError) Error() string {
+	return fmt.Sprintf("elasticsearch responded with %d: %s: %s", e.Status, e.Type, e.Reason)
+}
+
+func (e *ElasticError) Unwrap() error {
+	if e.retryable() {
+		return entities.NewUnavailableError("elasticsearch responded with %d: %s", e.Status, e.Type)
+	}
+	return entities.NewBadGatewayError("elasticsearch responded with %d: %s", e.Status, e.Type)
+}
+
+func (e *ElasticError) retryable() bool {
+	return e.Status == http.StatusTooManyRequests || e.Status >= 500
+}
+
+func isNotFound(err error) bool {
+	var elasticErr *ElasticError
+	return errors.As(err, &elasticErr) && elasticErr.Status == http.StatusNotFound
+}
+
+// ElasticClient sends requests to Elasticsearch. Every attempt has a
+// timeout, network errors, 429 and 5xx are retried after the Retries
+// waits and a circuit breaker stops sending while Elasticsearch is down.
+type ElasticClient struct {
+	host    string
+	client  *http.Client
+	breaker *CircuitBreaker
+
+	// Timeout bounds every attempt.
+	Timeout time.Duration
+	// Retries are the waits before each retry.
+	Retries []time.Duration
+}
+
+func NewElasticClient(host string) *ElasticClient {
+	return &ElasticClient{
+		host:    host,
+		client:  &http.Client{},
+		breaker: NewCircuitBreaker(),
+		Timeout: 5 * time.Second,
+		Retries: []time.Duration{100 * time.Millisecond, 400 * time.Millisecond},
+	}
+}
+
+// Do sends body as JSON and decodes the response into result, if given.
+func (c *ElasticClient) Do(ctx context.Context, method string, path string, body interface{}, result interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	return c.send(ctx, method, path, "application/json", payload, result, c.Retries)
+}
+
+func (c *ElasticClient) send(ctx context.Context, method string, path string, contentType string, payload []byte, result interface{}, retries []time.Duration) error {
+	if c.host == "" {
+		return entities.NewUnavailableError("elasticsearch is not configured, set ELASTICSEARCH_HOST")
+	}
+
+	var err error
+	for attempt := 0; attempt <= len(retries); attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return entities.NewDomainError(entities.ErrCanceled, ctx.Err(), "request canceled")
+			case <-time.After(retries[attempt-1]):
+			}
+		}
+		if err = c.breaker.Allow(); err != nil {
+			return err
+		}
+
+		err = c.attempt(ctx, method, path, contentType, payload, result)
+		var elasticErr *ElasticError
+		switch {
+		case err == nil:
+			c.breaker.Success()
+			return nil
+		case ctx.Err() != nil:
+			// the caller gave up, that says nothing about Elasticsearch
+			return entities.NewDomainError(entities.ErrCanceled, ctx.Err(), "request canceled")
+		case errors.As(err, &elasticErr) && !elasticErr.retryable():
+			// Elasticsearch is fine, the request isn't
+			c.breaker.Success()
+			return err
+		case errors.Is(err, entities.ErrBadGateway):
+			c.breaker.Success()
+			return err
+		}
+		c.breaker.Failure()
+	}
+	return err
+}
+
+func (c *ElasticClient) attempt(ctx context.Context, method string, path string, contentType string, payload []byte, result interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.host+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return entities.NewDomainError(entities.ErrUnavailable, err, "search is unavailable")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return decodeElasticError(resp)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return entities.NewDomainError(entities.ErrBadGateway, err, "search returned an invalid response")
+	}
+	return nil
+}
+
+// decodeElasticError reads {"error": {"type": ..., "reason": ...}}, or
+// whatever else came back.
+func decodeElasticError(resp *http.Response) error {
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	var body struct {
+		Error struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	}
+	elasticErr := &ElasticError{Status: resp.StatusCode, Type: http.StatusText(resp.StatusCode), Reason: string(message)}
+	if json.Unmarshal(message, &body) == nil && body.Error.Type != "" {
+		elasticErr.Type = body.Error.Type
+		elasticErr.Reason = body.Error.Reason
+	}
+	return elasticErr
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vitalii-komenda/got/entities"
+)
+
+func TestElasticClient(t *testing.T) {
+	ctx := context.Background()
+	var requests int
+	var handler http.HandlerFunc
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		handler(w, r)
+	}))
+	defer server.Close()
+
+	newClient := func() *ElasticClient {
+		requests = 0
+		client := NewElasticClient(server.URL)
+		client.Timeout = 50 * time.Millisecond
+		client.Retries = []time.Duration{time.Millisecond, time.Millisecond}
+		return client
+	}
+
+	t.Run("retries until it succeeds", func(t *testing.T) {
+		client := newClient()
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			if requests < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"acknowledged":true}`))
+		}
+
+		var result struct {
+			Acknowledged bool `json:"acknowledged"`
+		}
+		err := client.Do(ctx, "GET", "/", nil, &result)
+
+		assert.NoError(t, err)
+		assert.True(t, result.Acknowledged)
+		assert.Equal(t, 3, requests)
+	})
+
+	t.Run("rejected requests are bad gateways and not retried", func(t *testing.T) {
+		client := newClient()
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"type":"parsing_exception","reason":"unknown query [mtch]"},"status":400}`))
+		}
+
+		err := client.Do(ctx, "POST", "/character_details/_search", map[string]string{}, nil)
+
+		var elasticErr *ElasticError
+		assert.ErrorAs(t, err, &elasticErr)
+		assert.Equal(t, &ElasticError{Status: 400, Type: "parsing_exception", Reason: "unknown query [mtch]"}, elasticErr)
+		assert.ErrorIs(t, err, entities.ErrBadGateway)
+		assert.Equal(t, 1, requests)
+	})
+
+	t.Run("invalid responses are bad gateways", func(t *testing.T) {
+		client := newClient()
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`<html>`))
+		}
+
+		var result map[string]interface{}
+		err := client.Do(ctx, "GET", "/", nil, &result)
+
+		assert.ErrorIs(t, err, entities.ErrBadGateway)
+	})
+
+	t.Run("slow responses time out", func(t *testing.T) {
+		client := newClient()
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		err := client.Do(ctx, "GET", "/", nil, nil)
+
+		assert.ErrorIs(t, err, entities.ErrUnavailable)
+		assert.Equal(t, 3, requests)
+	})
+
+	t.Run("circuit breaker fails fast", func(t *testing.T) {
+		client := newClient()
+		client.breaker.Threshold = 2
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+
+		err := client.Do(ctx, "GET", "/", nil, nil)
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+		assert.ErrorIs(t, err, entities.ErrUnavailable)
+		assert.Equal(t, 2, requests)
+
+		err = client.Do(ctx, "GET", "/", nil, nil)
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+		assert.Equal(t, 2, requests)
+	})
+
+	t.Run("unreachable host stays out of the detail", func(t *testing.T) {
+		client := NewElasticClient("http://127.0.0.1:1")
+		client.Retries = nil
+
+		err := client.Do(ctx, "GET", "/", nil, nil)
+
+		assert.ErrorIs(t, err, entities.ErrUnavailable)
+		var domainErr *entities.DomainError
+		assert.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, "search is unavailable", domainErr.Detail)
+		assert.Contains(t, err.Error(), "127.0.0.1:1", "the cause is still logged")
+	})
+
+	t.Run("caller gave up", func(t *testing.T) {
+		client := newClient()
+		handler = func(w http.ResponseWriter, r *http.Request) {}
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		err := client.Do(canceled, "GET", "/", nil, nil)
+
+		assert.ErrorIs(t, err, entities.ErrCanceled)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		err := NewElasticClient("").Do(ctx, "GET", "/", nil, nil)
+		assert.ErrorIs(t, err, entities.ErrUnavailable)
+	})
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	breaker := NewCircuitBreaker()
+	breaker.Threshold = 2
+	breaker.now = func() time.Time { return now }
+
+	breaker.Failure()
+	assert.NoError(t, breaker.Allow())
+	breaker.Failure()
+	assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen)
+
+	now = now.Add(breaker.Cooldown)
+	assert.NoError(t, breaker.Allow(), "one trial after the cooldown")
+	assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen)
+
+	breaker.Failure()
+	assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen, "the failed trial reopens it")
+
+	now = now.Add(breaker.Cooldown)
+	assert.NoError(t, breaker.Allow())
+	breaker.Success()
+	assert.NoError(t, breaker.Allow())
+	assert.NoError(t, breaker.Allow())
+}
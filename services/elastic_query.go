@@ -0,0 +1,103 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vitalii-komenda/got/entities"
+)
+
+// deadQuery matches the characters someone killed.
+var deadQuery = map[string]interface{}{"exists": map[string]interface{}{"field": "killed_by"}}
+
+// queryFields are the index fields of the text fields of a structured query.
+var queryFields = map[entities.QueryField]string{
+	entities.FieldName:  "character_name",
+	entities.FieldAlias: "aliases",
+	entities.FieldHouse: "house_name",
+	entities.FieldActor: "actor_name",
+}
+
+var scriptComparisons = map[entities.QueryOp]string{
+	entities.OpMatch:        "==",
+	entities.OpGreater:      ">",
+	entities.OpGreaterEqual: ">=",
+	entities.OpLess:         "<",
+	entities.OpLessEqual:    "<=",
+}
+
+// textQuery matches a plain search term against searchFields.
+func textQuery(text string) map[string]interface{} {
+	return map[string]interface{}{
+		"multi_match": map[string]interface{}{
+			"query":     text,
+			"fields":    searchFields,
+			"fuzziness": "AUTO",
+		},
+	}
+}
+
+// elasticQuery compiles a structured query to a bool query. The free text
+// of an AndNode is matched as a whole, the same as a plain search term.
+func elasticQuery(node entities.QueryNode) (map[string]interface{}, error) {
+	switch n := node.(type) {
+	case entities.TextNode:
+		return textQuery(n.Text), nil
+	case entities.AndNode:
+		must := []interface{}{}
+		if text := entities.QueryText(n); text != "" {
+			must = append(must, textQuery(text))
+		}
+		for _, child := range n.Nodes {
+			if _, ok := child.(entities.TextNode); ok {
+				continue
+			}
+			query, err := elasticQuery(child)
+			if err != nil {
+				return nil, err
+			}
+			must = append(must, query)
+		}
+		return map[string]interface{}{"bool": map[string]interface{}{"must": must}}, nil
+	case entities.NotNode:
+		query, err := elasticQuery(n.Node)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"bool": map[string]interface{}{"must_not": []interface{}{query}}}, nil
+	case entities.FieldNode:
+		return fieldQuery(n)
+	default:
+		return nil, fmt.Errorf("unknown query node %T", node)
+	}
+}
+
+func fieldQuery(n entities.FieldNode) (map[string]interface{}, error) {
+	switch n.Field {
+	case entities.FieldRoyal:
+		return map[string]interface{}{"term": map[string]interface{}{"royal": n.Bool}}, nil
+	case entities.FieldAlive:
+		if n.Bool {
+			return map[string]interface{}{"bool": map[string]interface{}{"must_not": []interface{}{deadQuery}}}, nil
+		}
+		return deadQuery, nil
+	case entities.FieldKilled:
+		return map[string]interface{}{
+			"script": map[string]interface{}{
+				"script": map[string]interface{}{
+					"source": fmt.Sprintf("doc['killed.keyword'].size() %s params.count", scriptComparisons[n.Op]),
+					"params": map[string]interface{}{"count": n.Number},
+				},
+			},
+		}, nil
+	}
+
+	field, ok := queryFields[n.Field]
+	if !ok {
+		return nil, fmt.Errorf("unknown query field %q", n.Field)
+	}
+	// whole words in order, like the Postgres query
+	return map[string]interface{}{
+		"match_phrase": map[string]interface{}{field: strings.TrimSpace(n.Value)},
+	}, nil
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vitalii-komenda/got/entities"
+)
+
+func TestElasticSearcherQuery(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/character_details/_search", r.URL.Path)
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{
+			"hits": {
+				"total": {"value": 42},
+				"hits": [{"_source": {"character_id": 1, "character_name": "Jon Snow", "house_name": ["House Stark"]}}]
+			},
+			"aggregations": {
+				"house": {"doc_count": 42, "values": {"buckets": [{"key": "House Stark", "doc_count": 30}, {"key": "House Targaryen", "doc_count": 12}]}},
+				"royal": {"doc_count": 42, "values": {"buckets": [{"key": 0, "key_as_string": "false", "doc_count": 40}, {"key": 1, "key_as_string": "true", "doc_count": 2}]}}
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	alive := true
+	royal := false
+	result, err := NewElasticSearcher(server.URL).Query(context.Background(), entities.ElasticSearchQuery{
+		Term:  "snow",
+		House: "House Stark",
+		Royal: &royal,
+		Alive: &alive,
+		From:  20,
+		Size:  10,
+		Sort:  entities.SortNameDesc,
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 42, result.Total)
+	assert.Len(t, result.Hits, 1)
+	assert.Equal(t, "Jon Snow", result.Hits[0].CharacterName)
+	assert.Equal(t, []entities.FacetCount{{Value: "House Stark", Count: 30}, {Value: "House Targaryen", Count: 12}}, result.Facets.House)
+	assert.Equal(t, []entities.FacetCount{{Value: "false", Count: 40}, {Value: "true", Count: 2}}, result.Facets.Royal)
+
+	assert.Equal(t, float64(20), body["from"])
+	assert.Equal(t, float64(10), body["size"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"character_name.keyword": "desc"}}, body["sort"])
+	boolQuery := body["query"].(map[string]interface{})["bool"].(map[string]interface{})
+	assert.NotContains(t, boolQuery, "filter", "filters must not narrow the facets down")
+
+	house := map[string]interface{}{"term": map[string]interface{}{"house_name.keyword": "House Stark"}}
+	royalFilter := map[string]interface{}{"term": map[string]interface{}{"royal": false}}
+	aliveFilter := map[string]interface{}{"bool": map[string]interface{}{"must_not": map[string]interface{}{"exists": map[string]interface{}{"field": "killed_by"}}}}
+	filter := func(clauses ...interface{}) interface{} {
+		return map[string]interface{}{"bool": map[string]interface{}{"filter": clauses}}
+	}
+	assert.Equal(t, filter(house, royalFilter, aliveFilter), body["post_filter"])
+	aggs := body["aggs"].(map[string]interface{})
+	assert.Equal(t, filter(royalFilter, aliveFilter), aggs["house"].(map[string]interface{})["filter"])
+	assert.Equal(t, filter(house, aliveFilter), aggs["royal"].(map[string]interface{})["filter"])
+}
+
+func TestSearchRequestBodyWithoutTerm(t *testing.T) {
+	alive := false
+	body, err := searchRequestBody(entities.ElasticSearchQuery{Alive: &alive})
+	assert.NoError(t, err)
+
+	boolQuery := body["query"].(map[string]interface{})["bool"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"match_all": map[string]interface{}{}}, boolQuery["must"])
+	assert.Equal(t, map[string]interface{}{"bool": map[string]interface{}{"filter": []interface{}{
+		map[string]interface{}{"exists": map[string]interface{}{"field": "killed_by"}},
+	}}}, body["post_filter"])
+	assert.Equal(t, 10, body["size"])
+}
+
+func TestElasticSearcherSuggest(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"suggest": {"names": [{"options": [
+			{"text": "Jon Snow", "contexts": {"type": ["character"]}, "_source": {"slug": "jon-snow"}},
+			{"text": "Lord Snow", "contexts": {"type": ["alias"]}, "_source": {"slug": "jon-snow"}},
+			{"text": "Kit Harington", "contexts": {"type": ["actor"]}, "_source": {"slug": "jon-snow"}}
+		]}]}}`))
+	}))
+	defer server.Close()
+
+	suggestions, err := NewElasticSearcher(server.URL).Suggest(context.Background(), "jo", 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.Suggestion{
+		{Name: "Jon Snow", Type: entities.SuggestionCharacter, Slug: "jon-snow"},
+		{Name: "Lord Snow", Type: entities.SuggestionAlias, Slug: "jon-snow"},
+		{Name: "Kit Harington", Type: entities.SuggestionActor, Slug: "kit-harington"},
+	}, suggestions)
+
+	completion := body["suggest"].(map[string]interface{})["names"].(map[string]interface{})
+	assert.Equal(t, "jo", completion["prefix"])
+	assert.Equal(t, float64(5), completion["completion"].(map[string]interface{})["size"])
+}
+
+func TestElasticSearcherSimilar(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/character_details/_search", r.URL.Path)
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"hits": {"total": {"value": 1}, "hits": [
+			{"_score": 4.2, "_source": {"character_id": 2, "character_name": "Arya Stark", "slug": "arya-stark", "house_name": ["House Stark"]}}
+		]}}`))
+	}))
+	defer server.Close()
+
+	similar, err := NewElasticSearcher(server.URL).Similar(context.Background(), 1, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.SimilarCharacter{{
+		CharacterEntry: entities.CharacterEntry{CharacterID: 2, CharacterName: "Arya Stark", Slug: "arya-stark", HouseName: entities.HouseNameType{"House Stark"}, Aliases: []entities.Alias{}},
+		Score:          4.2,
+	}}, similar)
+
+	assert.Equal(t, float64(5), body["size"])
+	query := body["query"].(map[string]interface{})["bool"].(map[string]interface{})
+	like := query["must"].(map[string]interface{})["more_like_this"].(map[string]interface{})["like"]
+	assert.Equal(t, []interface{}{map[string]interface{}{"_index": "character_details", "_id": "1"}}, like)
+	assert.Equal(t, map[string]interface{}{"ids": map[string]interface{}{"values": []interface{}{"1"}}}, query["must_not"])
+}
+
+func TestNewCharacterDocument(t *testing.T) {
+	document := newCharacterDocument(entities.CharacterEntry{
+		CharacterName: "Jon Snow",
+		HouseName:     entities.HouseNameType{"House Stark"},
+		Nickname:      "Lord Snow",
+		ActorName:     "Kit Harington",
+	})
+
+	assert.Equal(t, "Jon Snow", document.CharacterName)
+	assert.Equal(t, []completionInput{
+		{Input: []string{"Jon Snow"}, Weight: 10, Contexts: map[string][]string{"type": {"character"}}},
+		{Input: []string{"Lord Snow"}, Weight: 8, Contexts: map[string][]string{"type": {"alias"}}},
+		{Input: []string{"Kit Harington"}, Weight: 5, Contexts: map[string][]string{"type": {"actor"}}},
+		{Input: []string{"House Stark"}, Weight: 3, Contexts: map[string][]string{"type": {"house"}}},
+	}, document.Suggest)
+}
+
+func TestElasticSearcherQueryHighlightAndExplain(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"hits": {"total": {"value": 1}, "hits": [{
+			"_score": 1.5,
+			"_source": {"character_id": 2, "character_name": "Arya Stark"},
+			"highlight": {"character_name": ["<em>Arya</em> Stark"]},
+			"_explanation": {"value": 1.5, "description": "max of:", "details": [
+				{"value": 1.5, "description": "weight(character_name:arya^2.0 in 1)"}
+			]}
+		}]}}`))
+	}))
+	defer server.Close()
+
+	result, err := NewElasticSearcher(server.URL).Query(context.Background(), entities.ElasticSearchQuery{
+		Term:      "Aria",
+		Highlight: true,
+		Explain:   true,
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []entities.ElasticHit{{
+		CharacterEntryElastic: entities.CharacterEntryElastic{CharacterID: 2, CharacterName: "Arya Stark"},
+		Score:                 1.5,
+		Highlights:            map[string][]string{"character_name": {"<em>Arya</em> Stark"}},
+		Explanation: &entities.Explanation{Value: 1.5, Description: "max of:", Details: []entities.Explanation{
+			{Value: 1.5, Description: "weight(character_name:arya^2.0 in 1)"},
+		}},
+	}}, result.Hits)
+
+	assert.Equal(t, true, body["explain"])
+	highlight := body["highlight"].(map[string]interface{})
+	assert.Contains(t, highlight["fields"], "character_name")
+	assert.Contains(t, highlight["fields"], "aliases")
+}
+
+func TestElasticQuery(t *testing.T) {
+	node, err := entities.ParseQuery(`house:Stark royal:true killed:>3 actor:"Kit Harington" -alias:Bastard snow`)
+	assert.NoError(t, err)
+
+	query, err := elasticQuery(node)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"bool": map[string]interface{}{"must": []interface{}{
+		textQuery("snow"),
+		map[string]interface{}{"match_phrase": map[string]interface{}{"house_name": "Stark"}},
+		map[string]interface{}{"term": map[string]interface{}{"royal": true}},
+		map[string]interface{}{"script": map[string]interface{}{"script": map[string]interface{}{
+			"source": "doc['killed.keyword'].size() > params.count",
+			"params": map[string]interface{}{"count": 3},
+		}}},
+		map[string]interface{}{"match_phrase": map[string]interface{}{"actor_name": "Kit Harington"}},
+		map[string]interface{}{"bool": map[string]interface{}{"must_not": []interface{}{
+			map[string]interface{}{"match_phrase": map[string]interface{}{"aliases": "Bastard"}},
+		}}},
+	}}}, query)
+}
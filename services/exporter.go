@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"slices"
+
+	"github.com/vitalii-komenda/got/entities"
+)
+
+// Exporter reads the characters back in the layout of the import file, so
+// importing an export changes nothing.
+type Exporter struct {
+	charactersRepo entities.CharactersRepository
+}
+
+func NewExporter(charactersRepo entities.CharactersRepository) *Exporter {
+	return &Exporter{
+		charactersRepo: charactersRepo,
+	}
+}
+
+// Characters returns every character, one entry each, in ID order.
+func (e *Exporter) Characters(ctx context.Context) ([]entities.CharacterEntry, error) {
+	stored, err := loadCharacters(ctx, e.charactersRepo)
+	if err != nil {
+		return nil, err
+	}
+	characters := make([]entities.CharacterEntry, len(stored))
+	for i, character := range stored {
+		characters[i] = exportCharacter(character)
+	}
+	return characters, nil
+}
+
+// exportCharacter leaves out what the database derives, the ID and slug,
+// names the first actor in actorName like the dataset does and drops the
+// alias the nickname is stored as.
+func exportCharacter(character entities.CharacterEntry) entities.CharacterEntry {
+	character.CharacterID = 0
+	character.Slug = ""
+	if len(character.HouseName) == 0 {
+		character.HouseName = nil
+	}
+
+	character.ActorName, character.ActorLink = "", ""
+	var actors []entities.ActorEntry
+	for _, actor := range character.Actors {
+		entry := entities.ActorEntry{ActorName: actor.ActorName, ActorLink: actor.ActorLink}
+		if len(actor.SeasonsActive) > 0 {
+			entry.SeasonsActive = actor.SeasonsActive
+		}
+		actors = append(actors, entry)
+	}
+	character.Actors = actors
+	if len(actors) > 0 {
+		character.ActorName, character.ActorLink = actors[0].ActorName, actors[0].ActorLink
+	}
+
+	character.Aliases = slices.DeleteFunc(slices.Clone(character.Aliases), func(alias entities.Alias) bool {
+		return alias.Name == character.Nickname && alias.Type == entities.AliasNickname
+	})
+	if len(character.Aliases) == 0 {
+		character.Aliases = nil
+	}
+	for _, names := range []*[]string{&character.Parents, &character.Siblings, &character.Killed, &character.KilledBy, &character.MarriedEngaged} {
+		if len(*names) == 0 {
+			*names = nil
+		}
+	}
+	if len(character.Extra) == 0 {
+		character.Extra = nil
+	}
+	return character
+}
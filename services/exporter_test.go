@@ -0,0 +1,92 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vitalii-komenda/got/entities"
+)
+
+func TestExportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	gregorActors := []entities.ActorEntry{
+		{ActorID: 7, ActorName: "Conan Stevens", ActorLink: "/name/nm1/", SeasonsActive: []int{1}},
+		{ActorID: 8, ActorName: "Hafþór Júlíus Björnsson", ActorLink: "/name/nm2/", SeasonsActive: []int{4, 5, 6}},
+	}
+	gregor := entities.CharacterEntry{
+		CharacterID: 1, CharacterName: "Gregor Clegane", Slug: "gregor-clegane", HouseName: entities.HouseNameType{"Clegane"},
+		CharacterImageThumb: "https://example.com/thumb.jpg", CharacterLink: "/character/ch1/", Nickname: "The Mountain",
+		Aliases: []entities.Alias{{Name: "Ser Gregor", Type: entities.AliasTitle}, {Name: "The Mountain", Type: entities.AliasNickname}},
+		Actors:  gregorActors, Killed: []string{"Oberyn Martell"}, Siblings: []string{"Sandor Clegane"},
+		Extra: map[string]json.RawMessage{
+			"serves":   json.RawMessage(`"Tywin Lannister"`),
+			"parentOf": json.RawMessage(`[]`),
+			"guard":    json.RawMessage(`{"since": 1, "for": ["Cersei"]}`),
+		},
+	}
+	// GetAll has a row per actor, each with all the actors
+	first, second := gregor, gregor
+	first.ActorName, first.ActorLink = gregorActors[0].ActorName, gregorActors[0].ActorLink
+	second.ActorName, second.ActorLink = gregorActors[1].ActorName, gregorActors[1].ActorLink
+	repo := storedCharacters(
+		first, second,
+		entities.CharacterEntry{CharacterID: 2, CharacterName: "Sandor Clegane", Slug: "sandor-clegane", HouseName: entities.HouseNameType{}, Royal: true, Siblings: []string{"Gregor Clegane"}, Actors: []entities.ActorEntry{}, Aliases: []entities.Alias{}},
+		entities.CharacterEntry{CharacterID: 3, CharacterName: "Oberyn Martell", Slug: "oberyn-martell", KilledBy: []string{"Gregor Clegane"}, Extra: map[string]json.RawMessage{}},
+	)
+
+	exported, err := NewExporter(repo).Characters(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, exported, 3)
+	assert.Equal(t, "Conan Stevens", exported[0].ActorName)
+	assert.Equal(t, []entities.Alias{{Name: "Ser Gregor", Type: entities.AliasTitle}}, exported[0].Aliases)
+
+	for _, format := range WriterFormats() {
+		t.Run(format, func(t *testing.T) {
+			writer, err := NewCharacterWriter(format, "")
+			assert.NoError(t, err)
+			var buffer bytes.Buffer
+			assert.NoError(t, writer.Write(&buffer, exported))
+
+			reader, err := NewCharacterReader(format, "")
+			assert.NoError(t, err)
+			imported, err := reader.Read(&buffer)
+			assert.NoError(t, err)
+			assert.Equal(t, compactExtra(exported), compactExtra(imported))
+
+			// importing the export changes nothing
+			plan, err := NewImporter(repo, nil).Plan(ctx, imported, true)
+			assert.NoError(t, err)
+			assert.Equal(t, map[SyncAction]int{SyncUnchanged: 3}, plan.Counts())
+		})
+	}
+}
+
+func TestNewCharacterWriter(t *testing.T) {
+	writer, err := NewCharacterWriter("", "export.jsonl")
+	assert.NoError(t, err)
+	assert.IsType(t, NDJSONWriter{}, writer)
+
+	_, err = NewCharacterWriter("yaml", "")
+	assert.ErrorContains(t, err, "use one of csv, json, ndjson")
+}
+
+// compactExtra makes Extra comparable, as writers may indent it.
+func compactExtra(characters []entities.CharacterEntry) []entities.CharacterEntry {
+	compacted := make([]entities.CharacterEntry, len(characters))
+	for i, character := range characters {
+		if character.Extra != nil {
+			extra := map[string]json.RawMessage{}
+			for key, value := range character.Extra {
+				var buffer bytes.Buffer
+				json.Compact(&buffer, value)
+				extra[key] = buffer.Bytes()
+			}
+			character.Extra = extra
+		}
+		compacted[i] = character
+	}
+	return compacted
+}
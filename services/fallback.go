@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vitalii-komenda/got/entities"
+)
+
+var _ entities.Suggester = &FallbackSuggester{}
+var _ entities.SimilarFinder = &FallbackSimilarFinder{}
+
+// shouldFallBack tells whether the primary failed in a way the fallback
+// can make up for: it is down, answered nonsense or took too long, and
+// the caller is still waiting.
+func shouldFallBack(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	return errors.Is(err, entities.ErrUnavailable) || errors.Is(err, entities.ErrBadGateway) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// FallbackSuggester asks the primary suggester, Elasticsearch, and the
+// fallback, Postgres, when the primary is down, fails or is too slow to
+// keep up with typing.
+type FallbackSuggester struct {
+	primary  entities.Suggester
+	fallback entities.Suggester
+
+	// Timeout is how long the primary gets before the fallback answers.
+	Timeout time.Duration
+}
+
+func NewFallbackSuggester(primary entities.Suggester, fallback entities.Suggester) *FallbackSuggester {
+	return &FallbackSuggester{
+		primary:  primary,
+		fallback: fallback,
+		Timeout:  300 * time.Millisecond,
+	}
+}
+
+func (s *FallbackSuggester) Suggest(ctx context.Context, prefix string, size int) ([]entities.Suggestion, error) {
+	primaryCtx, cancel := context.WithTimeout(ctx, s.Timeout)
+	defer cancel()
+
+	suggestions, err := s.primary.Suggest(primaryCtx, prefix, size)
+	if !shouldFallBack(ctx, err) {
+		return suggestions, err
+	}
+	fmt.Printf("Suggesting from the fallback: %v\n", err)
+	return s.fallback.Suggest(ctx, prefix, size)
+}
+
+// FallbackSimilarFinder finds similar characters with the primary,
+// Elasticsearch, and the fallback, Postgres, when the primary is down,
+// fails or is too slow.
+type FallbackSimilarFinder struct {
+	primary  entities.SimilarFinder
+	fallback entities.SimilarFinder
+
+	// Timeout is how long the primary gets before the fallback answers.
+	Timeout time.Duration
+}
+
+func NewFallbackSimilarFinder(primary entities.SimilarFinder, fallback entities.SimilarFinder) *FallbackSimilarFinder {
+	return &FallbackSimilarFinder{
+		primary:  primary,
+		fallback: fallback,
+		Timeout:  time.Second,
+	}
+}
+
+func (f *FallbackSimilarFinder) Similar(ctx context.Context, characterID int, size int) ([]entities.SimilarCharacter, error) {
+	primaryCtx, cancel := context.WithTimeout(ctx, f.Timeout)
+	defer cancel()
+
+	similar, err := f.primary.Similar(primaryCtx, characterID, size)
+	if !shouldFallBack(ctx, err) {
+		return similar, err
+	}
+	fmt.Printf("Finding similar characters with the fallback: %v\n", err)
+	return f.fallback.Similar(ctx, characterID, size)
+}
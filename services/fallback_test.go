@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vitalii-komenda/got/entities"
+	"github.com/vitalii-komenda/got/mocks"
+)
+
+func TestFallbackSuggester(t *testing.T) {
+	ctx := context.Background()
+	primary := new(mocks.SuggesterMock)
+	fallback := new(mocks.SuggesterMock)
+	fallback.SuggestFunc = func(ctx context.Context, prefix string, size int) ([]entities.Suggestion, error) {
+		return []entities.Suggestion{{Name: "Jon Snow", Type: entities.SuggestionCharacter, Slug: "jon-snow"}}, nil
+	}
+	suggester := NewFallbackSuggester(primary, fallback)
+	suggester.Timeout = 10 * time.Millisecond
+
+	t.Run("primary answers", func(t *testing.T) {
+		primary.SuggestFunc = func(ctx context.Context, prefix string, size int) ([]entities.Suggestion, error) {
+			return []entities.Suggestion{{Name: "Jon Arryn", Type: entities.SuggestionCharacter, Slug: "jon-arryn"}}, nil
+		}
+		suggestions, err := suggester.Suggest(ctx, "jon", 10)
+		assert.NoError(t, err)
+		assert.Equal(t, "Jon Arryn", suggestions[0].Name)
+	})
+
+	t.Run("primary unavailable", func(t *testing.T) {
+		primary.SuggestFunc = func(ctx context.Context, prefix string, size int) ([]entities.Suggestion, error) {
+			return nil, entities.NewUnavailableError("elasticsearch is unreachable")
+		}
+		suggestions, err := suggester.Suggest(ctx, "jon", 10)
+		assert.NoError(t, err)
+		assert.Equal(t, "Jon Snow", suggestions[0].Name)
+	})
+
+	t.Run("primary too slow", func(t *testing.T) {
+		primary.SuggestFunc = func(ctx context.Context, prefix string, size int) ([]entities.Suggestion, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		suggestions, err := suggester.Suggest(ctx, "jon", 10)
+		assert.NoError(t, err)
+		assert.Equal(t, "Jon Snow", suggestions[0].Name)
+	})
+
+	t.Run("other errors are returned", func(t *testing.T) {
+		primary.SuggestFunc = func(ctx context.Context, prefix string, size int) ([]entities.Suggestion, error) {
+			return nil, errors.New("error decoding suggest response")
+		}
+		_, err := suggester.Suggest(ctx, "jon", 10)
+		assert.Error(t, err)
+	})
+}
+
+func TestFallbackSimilarFinder(t *testing.T) {
+	ctx := context.Background()
+	primary := new(mocks.SimilarFinderMock)
+	fallback := new(mocks.SimilarFinderMock)
+	fallback.SimilarFunc = func(ctx context.Context, characterID int, size int) ([]entities.SimilarCharacter, error) {
+		return []entities.SimilarCharacter{{CharacterEntry: entities.CharacterEntry{CharacterName: "Arya Stark"}, Score: 7}}, nil
+	}
+	finder := NewFallbackSimilarFinder(primary, fallback)
+	finder.Timeout = 10 * time.Millisecond
+
+	t.Run("primary answers", func(t *testing.T) {
+		primary.SimilarFunc = func(ctx context.Context, characterID int, size int) ([]entities.SimilarCharacter, error) {
+			return []entities.SimilarCharacter{{CharacterEntry: entities.CharacterEntry{CharacterName: "Sansa Stark"}, Score: 3.5}}, nil
+		}
+		similar, err := finder.Similar(ctx, 1, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, "Sansa Stark", similar[0].CharacterName)
+	})
+
+	t.Run("primary failing", func(t *testing.T) {
+		primary.SimilarFunc = func(ctx context.Context, characterID int, size int) ([]entities.SimilarCharacter, error) {
+			return nil, entities.NewBadGatewayError("elasticsearch responded with 400: search_phase_execution_exception")
+		}
+		similar, err := finder.Similar(ctx, 1, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, "Arya Stark", similar[0].CharacterName)
+	})
+
+	t.Run("primary too slow", func(t *testing.T) {
+		primary.SimilarFunc = func(ctx context.Context, characterID int, size int) ([]entities.SimilarCharacter, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		similar, err := finder.Similar(ctx, 1, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, "Arya Stark", similar[0].CharacterName)
+	})
+}
@@ -0,0 +1,343 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/vitalii-komenda/got/entities"
+)
+
+// SyncAction is what syncing does to a character.
+type SyncAction string
+
+const (
+	SyncCreated   SyncAction = "created"
+	SyncUpdated   SyncAction = "updated"
+	SyncDeleted   SyncAction = "deleted"
+	SyncUnchanged SyncAction = "unchanged"
+)
+
+// CharacterChange is the diff of one character: the fields that differ
+// for an update, nothing for the other actions.
+type CharacterChange struct {
+	Name   string
+	Action SyncAction
+	Fields []string
+
+	characterID   int
+	character     entities.CharacterEntry
+	relationships bool
+}
+
+// SyncPlan lists what syncing the characters changes, in file order with
+// the deletions last.
+type SyncPlan struct {
+	Changes []CharacterChange
+}
+
+// Counts tells how many characters each action applies to.
+func (p SyncPlan) Counts() map[SyncAction]int {
+	counts := map[SyncAction]int{}
+	for _, change := range p.Changes {
+		counts[change.Action]++
+	}
+	return counts
+}
+
+// Importer syncs the characters of a dataset into the repositories:
+// characters, actors and relationships are created or updated to match
+// the file and, when pruning, characters missing from it are deleted.
+// Running it twice changes nothing the second time.
+type Importer struct {
+	charactersRepo    entities.CharactersRepository
+	relationshipsRepo entities.RelationshipsRepository
+}
+
+func NewImporter(charactersRepo entities.CharactersRepository, relationshipsRepo entities.RelationshipsRepository) *Importer {
+	return &Importer{
+		charactersRepo:    charactersRepo,
+		relationshipsRepo: relationshipsRepo,
+	}
+}
+
+// Plan compares the characters with the stored ones without writing.
+func (i *Importer) Plan(ctx context.Context, characters []entities.CharacterEntry, prune bool) (SyncPlan, error) {
+	stored, err := loadCharacters(ctx, i.charactersRepo)
+	if err != nil {
+		return SyncPlan{}, err
+	}
+	characters = mergeDuplicates(characters)
+
+	storedByKey := map[string]entities.CharacterEntry{}
+	for _, character := range stored {
+		storedByKey[characterKey(character.CharacterName)] = character
+	}
+	incoming := map[string]bool{}
+	for _, character := range characters {
+		incoming[characterKey(character.CharacterName)] = true
+	}
+
+	// what relationship names resolve to once synced, the same way the
+	// repository looks them up: by name first, then by alias
+	remaining := slices.Clone(characters)
+	for key, character := range storedByKey {
+		if !prune && !incoming[key] {
+			remaining = append(remaining, character)
+		}
+	}
+	resolve := newNameResolver(remaining)
+
+	var plan SyncPlan
+	for _, character := range characters {
+		current, ok := storedByKey[characterKey(character.CharacterName)]
+		if !ok {
+			plan.Changes = append(plan.Changes, CharacterChange{
+				Name: character.CharacterName, Action: SyncCreated, character: character, relationships: true,
+			})
+			continue
+		}
+
+		change := CharacterChange{Name: character.CharacterName, Action: SyncUnchanged, characterID: current.CharacterID, character: character}
+		want, have := characterFields(character, resolve), characterFields(current, resolve)
+		for _, field := range syncedFields {
+			if want[field] == have[field] {
+				continue
+			}
+			change.Action = SyncUpdated
+			change.Fields = append(change.Fields, field)
+			if slices.Contains(relationshipFields, field) {
+				change.relationships = true
+			}
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+
+	if prune {
+		var deleted []CharacterChange
+		for key, character := range storedByKey {
+			if !incoming[key] {
+				deleted = append(deleted, CharacterChange{Name: character.CharacterName, Action: SyncDeleted, characterID: character.CharacterID})
+			}
+		}
+		slices.SortFunc(deleted, func(a, b CharacterChange) int { return strings.Compare(a.Name, b.Name) })
+		plan.Changes = append(plan.Changes, deleted...)
+	}
+	return plan, nil
+}
+
+// Apply writes the plan: characters first, so that every relationship
+// finds its target, then the deletions and the relationships.
+func (i *Importer) Apply(ctx context.Context, plan SyncPlan) error {
+	ids := map[string]int{}
+	for _, change := range plan.Changes {
+		character := change.character
+		switch change.Action {
+		case SyncCreated:
+			if err := i.charactersRepo.CreateCharacterAndActor(ctx, &character); err != nil {
+				return fmt.Errorf("unable to create character %s: %w", change.Name, err)
+			}
+			ids[change.Name] = character.CharacterID
+		case SyncUpdated:
+			if _, err := i.charactersRepo.UpdateCharacterAndActor(ctx, &character, change.characterID); err != nil {
+				return fmt.Errorf("unable to update character %s: %w", change.Name, err)
+			}
+			ids[change.Name] = change.characterID
+		}
+	}
+
+	for _, change := range plan.Changes {
+		if change.Action != SyncDeleted {
+			continue
+		}
+		if err := i.charactersRepo.Delete(ctx, change.characterID); err != nil {
+			return fmt.Errorf("unable to delete character %s: %w", change.Name, err)
+		}
+	}
+
+	for _, change := range plan.Changes {
+		if !change.relationships {
+			continue
+		}
+		character := change.character
+		character.CharacterID = ids[change.Name]
+		// replaces the stored relationships instead of adding them twice
+		if err := i.relationshipsRepo.UpdateAll(ctx, character); err != nil {
+			return fmt.Errorf("unable to sync relationships of %s: %w", change.Name, err)
+		}
+	}
+	return nil
+}
+
+// loadCharacters loads every character, one entry per character.
+func loadCharacters(ctx context.Context, charactersRepo entities.CharactersRepository) ([]entities.CharacterEntry, error) {
+	var characters []entities.CharacterEntry
+	seen := map[int]bool{}
+	for page := 0; ; page++ {
+		rows, err := charactersRepo.GetAll(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == 0 {
+			return characters, nil
+		}
+		for _, character := range rows {
+			// one row per actor, each with all the actors
+			if !seen[character.CharacterID] {
+				seen[character.CharacterID] = true
+				characters = append(characters, character)
+			}
+		}
+	}
+}
+
+// mergeDuplicates folds the characters the dataset lists once per actor
+// into one, with all of the actors and relationships.
+func mergeDuplicates(characters []entities.CharacterEntry) []entities.CharacterEntry {
+	var merged []entities.CharacterEntry
+	index := map[string]int{}
+	for _, character := range characters {
+		key := characterKey(character.CharacterName)
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(merged)
+			character.Actors = character.AllActors()
+			character.Extra = maps.Clone(character.Extra)
+			merged = append(merged, character)
+			continue
+		}
+		first := &merged[i]
+		for _, actor := range character.AllActors() {
+			if !slices.ContainsFunc(first.Actors, func(a entities.ActorEntry) bool { return a.ActorName == actor.ActorName }) {
+				first.Actors = append(first.Actors, actor)
+			}
+		}
+		first.Parents = appendMissing(first.Parents, character.Parents)
+		first.Siblings = appendMissing(first.Siblings, character.Siblings)
+		first.Killed = appendMissing(first.Killed, character.Killed)
+		first.KilledBy = appendMissing(first.KilledBy, character.KilledBy)
+		first.MarriedEngaged = appendMissing(first.MarriedEngaged, character.MarriedEngaged)
+		for key, value := range character.Extra {
+			if _, ok := first.Extra[key]; !ok {
+				if first.Extra == nil {
+					first.Extra = map[string]json.RawMessage{}
+				}
+				first.Extra[key] = value
+			}
+		}
+	}
+	return merged
+}
+
+func appendMissing(names []string, more []string) []string {
+	for _, name := range more {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func characterKey(name string) string {
+	return entities.Slugify(name)
+}
+
+// newNameResolver maps a relationship name to the character it refers to,
+// or to "" when there is none and the relationship would be skipped.
+func newNameResolver(characters []entities.CharacterEntry) func(string) string {
+	names := map[string]string{}
+	for _, character := range characters {
+		key := characterKey(character.CharacterName)
+		names[key] = key
+	}
+	for _, character := range characters {
+		for _, alias := range character.AllAliases() {
+			if _, ok := names[characterKey(alias.Name)]; !ok {
+				names[characterKey(alias.Name)] = characterKey(character.CharacterName)
+			}
+		}
+	}
+	return func(name string) string {
+		return names[characterKey(name)]
+	}
+}
+
+var relationshipFields = []string{"parents", "siblings", "killed", "killedBy", "marriedEngaged"}
+
+// syncedFields are the fields a character is compared by, named as in the
+// import file. characterName is among them since characters are matched by
+// a looser key, so a rename that only changes case or accents shows up.
+var syncedFields = append([]string{
+	"characterName", "houseName", "characterImageThumb", "characterImageFull", "characterLink", "nickname", "royal", "aliases", "actors", "extra",
+}, relationshipFields...)
+
+// characterFields renders the synced fields of a character comparably:
+// lists are sorted and relationships are the characters they resolve to.
+func characterFields(character entities.CharacterEntry, resolve func(string) string) map[string]string {
+	var aliases, actors []string
+	for _, alias := range character.AllAliases() {
+		aliases = append(aliases, alias.Name+"|"+string(alias.Type))
+	}
+	for _, actor := range character.AllActors() {
+		seasons := slices.Clone(actor.SeasonsActive)
+		slices.Sort(seasons)
+		actors = append(actors, fmt.Sprint(actor.ActorName, "|", actor.ActorLink, "|", seasons))
+	}
+	relationships := func(names []string) string {
+		var resolved []string
+		for _, name := range names {
+			if key := resolve(name); key != "" {
+				resolved = append(resolved, key)
+			}
+		}
+		return sortedJoin(resolved)
+	}
+
+	var houses []string
+	for _, house := range character.HouseName {
+		if house = strings.TrimSpace(house); house != "" {
+			houses = append(houses, house)
+		}
+	}
+	return map[string]string{
+		"characterName":       character.CharacterName,
+		"houseName":           sortedJoin(houses),
+		"characterImageThumb": character.CharacterImageThumb,
+		"characterImageFull":  character.CharacterImageFull,
+		"characterLink":       character.CharacterLink,
+		"nickname":            character.Nickname,
+		"royal":               fmt.Sprint(character.Royal),
+		"aliases":             sortedJoin(aliases),
+		"actors":              sortedJoin(actors),
+		"extra":               extraFields(character.Extra),
+		"parents":             relationships(character.Parents),
+		"siblings":            relationships(character.Siblings),
+		"killed":              relationships(character.Killed),
+		"killedBy":            relationships(character.KilledBy),
+		"marriedEngaged":      relationships(character.MarriedEngaged),
+	}
+}
+
+// extraFields renders Extra as JSON with sorted keys and no spacing, the
+// way it comes back from the jsonb column.
+func extraFields(extra map[string]json.RawMessage) string {
+	if len(extra) == 0 {
+		return ""
+	}
+	var value interface{}
+	data, _ := json.Marshal(extra)
+	if json.Unmarshal(data, &value) != nil {
+		return string(data)
+	}
+	data, _ = json.Marshal(value)
+	return string(data)
+}
+
+func sortedJoin(values []string) string {
+	values = slices.Clone(values)
+	slices.Sort(values)
+	return strings.Join(slices.Compact(values), "\n")
+}
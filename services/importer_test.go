@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vitalii-komenda/got/entities"
+	"github.com/vitalii-komenda/got/mocks"
+)
+
+// storedCharacters serves the characters as GetAll does, one page.
+func storedCharacters(characters ...entities.CharacterEntry) *mocks.CharactersRepositoryMock {
+	repo := new(mocks.CharactersRepositoryMock)
+	repo.GetAllFunc = func(ctx context.Context, page int) ([]entities.CharacterEntry, error) {
+		if page > 0 {
+			return nil, nil
+		}
+		return characters, nil
+	}
+	return repo
+}
+
+func TestImporterPlan(t *testing.T) {
+	ctx := context.Background()
+	repo := storedCharacters(
+		entities.CharacterEntry{
+			CharacterID: 1, CharacterName: "Jon Snow", HouseName: entities.HouseNameType{"Stark"}, Nickname: "Lord Snow",
+			Aliases:   []entities.Alias{{Name: "Lord Snow", Type: entities.AliasNickname}},
+			ActorName: "Kit Harington", Actors: []entities.ActorEntry{{ActorName: "Kit Harington", SeasonsActive: []int{}}},
+			Siblings: []string{"Arya Stark"},
+		},
+		entities.CharacterEntry{CharacterID: 2, CharacterName: "Arya Stark", HouseName: entities.HouseNameType{"Stark"}, Siblings: []string{"Jon Snow"}},
+		entities.CharacterEntry{CharacterID: 3, CharacterName: "Ros"},
+	)
+	characters := []entities.CharacterEntry{
+		{CharacterName: "Jon Snow", HouseName: entities.HouseNameType{"Stark"}, Nickname: "Lord Snow", ActorName: "Kit Harington", Siblings: []string{"Arya Stark"}},
+		{CharacterName: "Arya Stark", HouseName: entities.HouseNameType{"Stark"}, Royal: true, Siblings: []string{"Jon Snow", "Sansa Stark"}},
+		{CharacterName: "Sansa Stark", HouseName: entities.HouseNameType{"Stark"}, Siblings: []string{"Arya Stark", "Nobody"}},
+	}
+	importer := NewImporter(repo, new(mocks.RelationshipsRepositoryMock))
+
+	t.Run("without pruning", func(t *testing.T) {
+		plan, err := importer.Plan(ctx, characters, false)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Jon Snow", "Arya Stark", "Sansa Stark"}, changeNames(plan))
+		assert.Equal(t, SyncUnchanged, plan.Changes[0].Action)
+		assert.Equal(t, SyncUpdated, plan.Changes[1].Action)
+		assert.Equal(t, []string{"royal", "siblings"}, plan.Changes[1].Fields)
+		assert.Equal(t, SyncCreated, plan.Changes[2].Action)
+		assert.Equal(t, map[SyncAction]int{SyncUnchanged: 1, SyncUpdated: 1, SyncCreated: 1}, plan.Counts())
+	})
+
+	t.Run("with pruning", func(t *testing.T) {
+		plan, err := importer.Plan(ctx, characters, true)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Jon Snow", "Arya Stark", "Sansa Stark", "Ros"}, changeNames(plan))
+		assert.Equal(t, SyncDeleted, plan.Changes[3].Action)
+	})
+
+	t.Run("rename of case and accents only", func(t *testing.T) {
+		renamed := slices.Clone(characters)
+		renamed[0].CharacterName = "Jón SNOW"
+		plan, err := importer.Plan(ctx, renamed, false)
+		assert.NoError(t, err)
+		assert.Equal(t, SyncUpdated, plan.Changes[0].Action)
+		assert.Equal(t, []string{"characterName"}, plan.Changes[0].Fields)
+	})
+}
+
+func TestImporterPlanMergesDuplicates(t *testing.T) {
+	importer := NewImporter(storedCharacters(), new(mocks.RelationshipsRepositoryMock))
+	plan, err := importer.Plan(context.Background(), []entities.CharacterEntry{
+		{CharacterName: "Gregor Clegane", ActorName: "Conan Stevens", Killed: []string{"Oberyn Martell"}},
+		{CharacterName: "Gregor Clegane", ActorName: "Ian Whyte"},
+	}, false)
+	assert.NoError(t, err)
+	assert.Len(t, plan.Changes, 1)
+	assert.Equal(t, []entities.ActorEntry{{ActorName: "Conan Stevens"}, {ActorName: "Ian Whyte"}}, plan.Changes[0].character.Actors)
+	assert.Equal(t, []string{"Oberyn Martell"}, plan.Changes[0].character.Killed)
+}
+
+func TestImporterPlanComparesExtra(t *testing.T) {
+	importer := NewImporter(storedCharacters(
+		entities.CharacterEntry{CharacterID: 1, CharacterName: "Eddard Stark", Extra: map[string]json.RawMessage{"allies": json.RawMessage(`["Howland Reed"]`), "parentOf": json.RawMessage(`["Arya Stark","Sansa Stark"]`)}},
+		entities.CharacterEntry{CharacterID: 2, CharacterName: "Catelyn Stark", Extra: map[string]json.RawMessage{}},
+	), new(mocks.RelationshipsRepositoryMock))
+	plan, err := importer.Plan(context.Background(), []entities.CharacterEntry{
+		{CharacterName: "Eddard Stark", Extra: map[string]json.RawMessage{"parentOf": json.RawMessage(`[ "Arya Stark", "Sansa Stark" ]`), "allies": json.RawMessage(`["Howland Reed"]`)}},
+		{CharacterName: "Catelyn Stark", Extra: map[string]json.RawMessage{"parentOf": json.RawMessage(`["Arya Stark"]`)}},
+	}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, SyncUnchanged, plan.Changes[0].Action)
+	assert.Equal(t, []string{"extra"}, plan.Changes[1].Fields)
+}
+
+func TestImporterApply(t *testing.T) {
+	ctx := context.Background()
+	repo := storedCharacters(
+		entities.CharacterEntry{CharacterID: 2, CharacterName: "Arya Stark"},
+		entities.CharacterEntry{CharacterID: 3, CharacterName: "Ros"},
+	)
+	var calls []string
+	repo.CreateCharacterAndActorFunc = func(ctx context.Context, character *entities.CharacterEntry) error {
+		calls = append(calls, "create "+character.CharacterName)
+		character.CharacterID = 4
+		return nil
+	}
+	repo.UpdateCharacterAndActorFunc = func(ctx context.Context, character *entities.CharacterEntry, characterID int) (int, error) {
+		calls = append(calls, "update "+character.CharacterName)
+		assert.Equal(t, 2, characterID)
+		return characterID, nil
+	}
+	repo.DeleteFunc = func(ctx context.Context, characterID int) error {
+		calls = append(calls, "delete")
+		assert.Equal(t, 3, characterID)
+		return nil
+	}
+	relationships := new(mocks.RelationshipsRepositoryMock)
+	relationships.UpdateAllFunc = func(ctx context.Context, character entities.CharacterEntry) error {
+		calls = append(calls, "relate "+character.CharacterName)
+		return nil
+	}
+
+	importer := NewImporter(repo, relationships)
+	plan, err := importer.Plan(ctx, []entities.CharacterEntry{
+		{CharacterName: "Arya Stark", Siblings: []string{"Sansa Stark"}},
+		{CharacterName: "Sansa Stark", Siblings: []string{"Arya Stark"}},
+	}, true)
+	assert.NoError(t, err)
+	assert.NoError(t, importer.Apply(ctx, plan))
+	assert.Equal(t, []string{
+		"update Arya Stark", "create Sansa Stark", "delete", "relate Arya Stark", "relate Sansa Stark",
+	}, calls)
+	assert.Equal(t, 4, relationships.UpdateAllCalls()[1].Character.CharacterID)
+}
+
+func changeNames(plan SyncPlan) []string {
+	var names []string
+	for _, change := range plan.Changes {
+		names = append(names, change.Name)
+	}
+	return names
+}
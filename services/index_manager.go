@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/vitalii-komenda/got/entities"
+)
+
+// buildingAliasSuffix marks the index a reindex is filling, so the Indexer
+// writes to it as well and no write gets lost before the alias swap.
+const buildingAliasSuffix = "_building"
+
+// nameField is a name searched as whole words, as edge n-grams for search
+// as you type, and as an exact keyword for sorting and facets.
+var nameField = map[string]interface{}{
+	"type":     "text",
+	"analyzer": "folded",
+	"fields": map[string]interface{}{
+		"ngram": map[string]interface{}{
+			"type":            "text",
+			"analyzer":        "name_edge_ngram",
+			"search_analyzer": "folded",
+		},
+		"keyword": map[string]interface{}{
+			"type":         "keyword",
+			"ignore_above": 256,
+		},
+	},
+}
+
+// keywordField is text with a keyword subfield for filters and facets.
+var keywordField = map[string]interface{}{
+	"type":     "text",
+	"analyzer": "folded",
+	"fields": map[string]interface{}{
+		"keyword": map[string]interface{}{
+			"type":         "keyword",
+			"ignore_above": 256,
+		},
+	},
+}
+
+var notIndexedField = map[string]interface{}{"type": "keyword", "index": false}
+
+// characterDetailsIndexDefinition are the settings and mappings of every
+// character_details index. Changing them needs a reindex, see cmd/reindex.
+var characterDetailsIndexDefinition = map[string]interface{}{
+	"settings": map[string]interface{}{
+		"analysis": map[string]interface{}{
+			"filter": map[string]interface{}{
+				"name_edge_ngram": map[string]interface{}{
+					"type":     "edge_ngram",
+					"min_gram": 1,
+					"max_gram": 20,
+				},
+			},
+			"analyzer": map[string]interface{}{
+				"folded": map[string]interface{}{
+					"type":      "custom",
+					"tokenizer": "standard",
+					"filter":    []string{"lowercase", "asciifolding"},
+				},
+				"name_edge_ngram": map[string]interface{}{
+					"type":      "custom",
+					"tokenizer": "standard",
+					"filter":    []string{"lowercase", "asciifolding", "name_edge_ngram"},
+				},
+			},
+		},
+	},
+	"mappings": map[string]interface{}{
+		"dynamic": "strict",
+		"properties": map[string]interface{}{
+			"character_id":          map[string]interface{}{"type": "integer"},
+			"character_name":        nameField,
+			"slug":                  map[string]interface{}{"type": "keyword"},
+			"house_name":            keywordField,
+			"character_image_thumb": notIndexedField,
+			"character_image_full":  notIndexedField,
+			"character_link":        notIndexedField,
+			"actor_name":            nameField,
+			"actor_link":            notIndexedField,
+			"nickname":              nameField,
+			"aliases":               nameField,
+			"royal":                 map[string]interface{}{"type": "boolean"},
+			"parents":               keywordField,
+			"siblings":              keywordField,
+			"killed_by":             keywordField,
+			"killed":                keywordField,
+			"married_engaged":       keywordField,
+			"suggest": map[string]interface{}{
+				"type":     "completion",
+				"analyzer": "folded",
+				"contexts": []interface{}{
+					map[string]interface{}{"name": "type", "type": "category"},
+				},
+			},
+		},
+	},
+}
+
+// IndexManager owns the character_details indices. Each mapping version
+// lives in its own character_details_<timestamp> index and searches go
+// through the character_details alias, which a reindex swaps atomically.
+type IndexManager struct {
+	client *ElasticClient
+	Alias  string
+}
+
+func NewIndexManager(host string) *IndexManager {
+	client := NewElasticClient(host)
+	// creating indices and bulk requests take longer than searches
+	client.Timeout = 30 * time.Second
+	return &IndexManager{
+		client: client,
+		Alias:  characterDetailsIndex,
+	}
+}
+
+// EnsureIndex creates the first index behind the alias when there is
+// none, so the index never gets created with dynamic mappings.
+func (m *IndexManager) EnsureIndex(ctx context.Context) error {
+	indices, err := m.aliasIndices(ctx, m.Alias)
+	if err != nil || len(indices) > 0 {
+		return err
+	}
+	index, err := m.CreateIndex(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = m.SwapAlias(ctx, index)
+	return err
+}
+
+// CreateIndex creates a new, empty, versioned index and returns its name.
+func (m *IndexManager) CreateIndex(ctx context.Context) (string, error) {
+	index := fmt.Sprintf("%s_%s", m.Alias, time.Now().UTC().Format("20060102150405"))
+	err := m.client.Do(ctx, "PUT", "/"+index, characterDetailsIndexDefinition, nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating index %s: %w", index, err)
+	}
+	return index, nil
+}
+
+// StartBuilding makes the Indexer write to the index too while a reindex
+// fills it.
+func (m *IndexManager) StartBuilding(ctx context.Context, index string) error {
+	return m.client.Do(ctx, "POST", "/_aliases", map[string]interface{}{
+		"actions": []interface{}{
+			map[string]interface{}{"add": map[string]string{"index": index, "alias": m.Alias + buildingAliasSuffix}},
+		},
+	}, nil)
+}
+
+// SwapAlias points the alias at the index in one atomic step and returns
+// the indices it pointed at before. An old concrete index named like the
+// alias, as logstash used to create, is deleted in the same step.
+func (m *IndexManager) SwapAlias(ctx context.Context, index string) ([]string, error) {
+	previous, err := m.aliasIndices(ctx, m.Alias)
+	if err != nil {
+		return nil, err
+	}
+	building, err := m.aliasIndices(ctx, m.Alias+buildingAliasSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	actions := []interface{}{}
+	legacy, err := m.exists(ctx, m.Alias)
+	if err != nil {
+		return nil, err
+	}
+	if legacy && len(previous) == 0 {
+		actions = append(actions, map[string]interface{}{"remove_index": map[string]string{"index": m.Alias}})
+	}
+	for _, old := range previous {
+		if old != index {
+			actions = append(actions, map[string]interface{}{"remove": map[string]string{"index": old, "alias": m.Alias}})
+		}
+	}
+	for _, b := range building {
+		actions = append(actions, map[string]interface{}{"remove": map[string]string{"index": b, "alias": m.Alias + buildingAliasSuffix}})
+	}
+	actions = append(actions, map[string]interface{}{"add": map[string]string{"index": index, "alias": m.Alias}})
+
+	err = m.client.Do(ctx, "POST", "/_aliases", map[string]interface{}{"actions": actions}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error swapping alias %s to %s: %w", m.Alias, index, err)
+	}
+
+	var old []string
+	for _, p := range previous {
+		if p != index {
+			old = append(old, p)
+		}
+	}
+	return old, nil
+}
+
+// DeleteIndex deletes an index no alias needs anymore.
+func (m *IndexManager) DeleteIndex(ctx context.Context, index string) error {
+	return m.client.Do(ctx, "DELETE", "/"+index, nil, nil)
+}
+
+// WriteIndices are the indices writes have to reach: the one behind the
+// alias and the one a reindex is filling, if any.
+func (m *IndexManager) WriteIndices(ctx context.Context) ([]string, error) {
+	indices, err := m.aliasIndices(ctx, m.Alias)
+	if err != nil {
+		return nil, err
+	}
+	if len(indices) == 0 {
+		return nil, entities.NewUnavailableError("alias %s does not exist yet", m.Alias)
+	}
+	building, err := m.aliasIndices(ctx, m.Alias+buildingAliasSuffix)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range building {
+		if !slices.Contains(indices, b) {
+			indices = append(indices, b)
+		}
+	}
+	return indices, nil
+}
+
+func (m *IndexManager) aliasIndices(ctx context.Context, alias string) ([]string, error) {
+	var result map[string]interface{}
+	err := m.client.Do(ctx, "GET", "/_alias/"+alias, nil, &result)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	indices := make([]string, 0, len(result))
+	for index := range result {
+		indices = append(indices, index)
+	}
+	sort.Strings(indices)
+	return indices, nil
+}
+
+func (m *IndexManager) exists(ctx context.Context, index string) (bool, error) {
+	err := m.client.Do(ctx, "HEAD", "/"+index, nil, nil)
+	if isNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
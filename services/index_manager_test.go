@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexManager(t *testing.T) {
+	ctx := context.Background()
+	aliases := map[string][]string{
+		"character_details":          {"character_details_1"},
+		"character_details_building": {"character_details_2"},
+	}
+	var actions []map[string]map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/_alias/"):
+			indices := aliases[strings.TrimPrefix(r.URL.Path, "/_alias/")]
+			if len(indices) == 0 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			result := map[string]interface{}{}
+			for _, index := range indices {
+				result[index] = map[string]interface{}{}
+			}
+			json.NewEncoder(w).Encode(result)
+		case r.URL.Path == "/_aliases":
+			var body struct {
+				Actions []map[string]map[string]string `json:"actions"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			actions = body.Actions
+		case r.Method == "HEAD" && r.URL.Path == "/character_details":
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	manager := NewIndexManager(server.URL)
+
+	t.Run("writes reach the index being built", func(t *testing.T) {
+		indices, err := manager.WriteIndices(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"character_details_1", "character_details_2"}, indices)
+	})
+
+	t.Run("swaps the alias in one request", func(t *testing.T) {
+		previous, err := manager.SwapAlias(ctx, "character_details_2")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"character_details_1"}, previous)
+		assert.Equal(t, []map[string]map[string]string{
+			{"remove": {"index": "character_details_1", "alias": "character_details"}},
+			{"remove": {"index": "character_details_2", "alias": "character_details_building"}},
+			{"add": {"index": "character_details_2", "alias": "character_details"}},
+		}, actions)
+	})
+
+	t.Run("replaces an index created with dynamic mappings", func(t *testing.T) {
+		aliases = map[string][]string{}
+		_, err := manager.SwapAlias(ctx, "character_details_3")
+		assert.NoError(t, err)
+		assert.Equal(t, []map[string]map[string]string{
+			{"remove_index": {"index": "character_details"}},
+			{"add": {"index": "character_details_3", "alias": "character_details"}},
+		}, actions)
+	})
+}
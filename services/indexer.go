@@ -0,0 +1,280 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/vitalii-komenda/got/entities"
+)
+
+// Indexer delivers the search_outbox events the repositories write to
+// Elasticsearch's bulk API, so the character_details index follows
+// Postgres within seconds, deletes included.
+type Indexer struct {
+	outbox         entities.SearchOutbox
+	charactersRepo entities.CharactersRepository
+	indexManager   *IndexManager
+
+	// Interval is how long to wait when there is nothing to deliver.
+	Interval time.Duration
+	// BatchSize is the number of events per bulk request.
+	BatchSize int
+	// MaxAttempts is how many times an event is delivered before it is
+	// left in the outbox for a human to look at.
+	MaxAttempts int
+	// Retries are the waits between bulk requests failing with a network
+	// error, 429 or 5xx, before the batch counts as failed. They are longer
+	// than the ones of searches, nobody is waiting.
+	Retries []time.Duration
+}
+
+func NewIndexer(outbox entities.SearchOutbox, charactersRepo entities.CharactersRepository, indexManager *IndexManager) *Indexer {
+	return &Indexer{
+		outbox:         outbox,
+		charactersRepo: charactersRepo,
+		indexManager:   indexManager,
+		Interval:       time.Second,
+		BatchSize:      500,
+		MaxAttempts:    10,
+		Retries:        []time.Duration{500 * time.Millisecond, 2 * time.Second, 5 * time.Second},
+	}
+}
+
+// Run delivers events until the context is cancelled.
+func (i *Indexer) Run(ctx context.Context) error {
+	for {
+		delivered, err := i.ProcessBatch(ctx)
+		if err != nil {
+			fmt.Printf("Error indexing: %v\n", err)
+		}
+		if err != nil || delivered < i.BatchSize {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(i.Interval):
+			}
+		}
+	}
+}
+
+// ProcessBatch delivers the oldest pending events and returns how many it
+// took from the outbox.
+func (i *Indexer) ProcessBatch(ctx context.Context) (int, error) {
+	events, err := i.outbox.Pending(ctx, i.BatchSize, i.MaxAttempts)
+	if err != nil {
+		return 0, err
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	// only the last event of a character matters, the document is read
+	// from Postgres anyway
+	eventIDs := map[int][]int64{}
+	operations := map[int]entities.OutboxOperation{}
+	var characterIDs []int
+	for _, event := range events {
+		if _, ok := operations[event.CharacterID]; !ok {
+			characterIDs = append(characterIDs, event.CharacterID)
+		}
+		eventIDs[event.CharacterID] = append(eventIDs[event.CharacterID], event.ID)
+		operations[event.CharacterID] = event.Operation
+	}
+
+	indices, err := i.indexManager.WriteIndices(ctx)
+	if err != nil {
+		return 0, err
+	}
+	documents, err := i.documents(ctx, characterIDs, operations)
+	if err != nil {
+		return 0, err
+	}
+
+	var body bytes.Buffer
+	for _, index := range indices {
+		for _, id := range characterIDs {
+			meta := map[string]string{"_index": index, "_id": strconv.Itoa(id)}
+			document, ok := documents[id]
+			if !ok {
+				// deleted, or deleted again since it was updated
+				writeNDJSON(&body, map[string]interface{}{"delete": meta})
+				continue
+			}
+			writeNDJSON(&body, map[string]interface{}{"index": meta})
+			writeNDJSON(&body, document)
+		}
+	}
+
+	failures, err := i.bulk(ctx, body.Bytes())
+	if err != nil {
+		var all []int64
+		for _, ids := range eventIDs {
+			all = append(all, ids...)
+		}
+		if failErr := i.outbox.Failed(ctx, all, err.Error()); failErr != nil {
+			return 0, failErr
+		}
+		return len(events), err
+	}
+
+	var done []int64
+	for _, id := range characterIDs {
+		reason, failed := failures[strconv.Itoa(id)]
+		if !failed {
+			done = append(done, eventIDs[id]...)
+			continue
+		}
+		if err := i.outbox.Failed(ctx, eventIDs[id], reason); err != nil {
+			return 0, err
+		}
+	}
+	if len(done) > 0 {
+		if err := i.outbox.Done(ctx, done); err != nil {
+			return 0, err
+		}
+	}
+	return len(events), nil
+}
+
+// IndexAll writes every character in Postgres to the index and returns
+// how many it wrote. The Indexer writes to the index too while it is being
+// built, so documents are only created: one the Indexer wrote after the
+// batch was read is newer and stays, and characters deleted meanwhile are
+// deleted again.
+func (i *Indexer) IndexAll(ctx context.Context, index string) (int, error) {
+	written := 0
+	for after := 0; ; {
+		characters, err := i.charactersRepo.GetAfter(ctx, after, i.BatchSize)
+		if err != nil {
+			return written, err
+		}
+		if len(characters) == 0 {
+			return written, nil
+		}
+
+		var characterIDs []int
+		documents := map[int]characterDocument{}
+		for _, character := range characters {
+			// one document per character, with its first actor
+			if _, ok := documents[character.CharacterID]; !ok {
+				characterIDs = append(characterIDs, character.CharacterID)
+				documents[character.CharacterID] = newCharacterDocument(character)
+			}
+		}
+		after = characterIDs[len(characterIDs)-1]
+
+		var body bytes.Buffer
+		for _, id := range characterIDs {
+			writeNDJSON(&body, map[string]interface{}{"create": map[string]string{"_index": index, "_id": strconv.Itoa(id)}})
+			writeNDJSON(&body, documents[id])
+		}
+		failures, err := i.bulk(ctx, body.Bytes())
+		if err != nil {
+			return written, err
+		}
+		for id, reason := range failures {
+			return written, fmt.Errorf("error indexing character %s: %s", id, reason)
+		}
+
+		// the Indexer found nothing to delete if a character was deleted
+		// before its document got created
+		existing, err := i.charactersRepo.GetByIDs(ctx, characterIDs)
+		if err != nil {
+			return written, err
+		}
+		for _, character := range existing {
+			delete(documents, character.CharacterID)
+		}
+		if len(documents) > 0 {
+			body.Reset()
+			for id := range documents {
+				writeNDJSON(&body, map[string]interface{}{"delete": map[string]string{"_index": index, "_id": strconv.Itoa(id)}})
+			}
+			failures, err := i.bulk(ctx, body.Bytes())
+			if err != nil {
+				return written, err
+			}
+			for id, reason := range failures {
+				return written, fmt.Errorf("error deleting character %s: %s", id, reason)
+			}
+		}
+		written += len(characterIDs) - len(documents)
+	}
+}
+
+// documents loads the index documents of the characters to upsert.
+func (i *Indexer) documents(ctx context.Context, characterIDs []int, operations map[int]entities.OutboxOperation) (map[int]characterDocument, error) {
+	var upserts []int
+	for _, id := range characterIDs {
+		if operations[id] == entities.OutboxUpsert {
+			upserts = append(upserts, id)
+		}
+	}
+	documents := map[int]characterDocument{}
+	if len(upserts) == 0 {
+		return documents, nil
+	}
+
+	characters, err := i.charactersRepo.GetByIDs(ctx, upserts)
+	if err != nil {
+		return nil, err
+	}
+	for _, character := range characters {
+		// one document per character, with its first actor
+		if _, ok := documents[character.CharacterID]; !ok {
+			documents[character.CharacterID] = newCharacterDocument(character)
+		}
+	}
+	return documents, nil
+}
+
+type bulkResponse struct {
+	Errors bool                          `json:"errors"`
+	Items  []map[string]bulkResponseItem `json:"items"`
+}
+
+type bulkResponseItem struct {
+	ID     string          `json:"_id"`
+	Status int             `json:"status"`
+	Error  json.RawMessage `json:"error"`
+}
+
+// bulk sends the request, retrying on errors that may go away, and returns
+// the reasons of the documents Elasticsearch rejected by ID.
+func (i *Indexer) bulk(ctx context.Context, body []byte) (map[string]string, error) {
+	var result bulkResponse
+	err := i.indexManager.client.send(ctx, "POST", "/_bulk", "application/x-ndjson", body, &result, i.Retries)
+	if err != nil {
+		return nil, err
+	}
+
+	failures := map[string]string{}
+	if !result.Errors {
+		return failures, nil
+	}
+	for _, item := range result.Items {
+		for action, outcome := range item {
+			if outcome.Status < 300 || (action == "delete" && outcome.Status == http.StatusNotFound) {
+				continue
+			}
+			// a document that exists already was written by the Indexer
+			// after the snapshot was read, see IndexAll
+			if action == "create" && outcome.Status == http.StatusConflict {
+				continue
+			}
+			failures[outcome.ID] = fmt.Sprintf("%s failed with %d: %s", action, outcome.Status, outcome.Error)
+		}
+	}
+	return failures, nil
+}
+
+func writeNDJSON(buffer *bytes.Buffer, value interface{}) {
+	line, _ := json.Marshal(value)
+	buffer.Write(line)
+	buffer.WriteByte('\n')
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vitalii-komenda/got/entities"
+	"github.com/vitalii-komenda/got/mocks"
+)
+
+// fakeElastic serves the character_details alias pointing at
+// character_details_1 and hands bulk requests to the handler.
+func fakeElastic(bulk http.HandlerFunc) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/_alias/character_details":
+			w.Write([]byte(`{"character_details_1":{"aliases":{"character_details":{}}}}`))
+		case "/_bulk":
+			bulk(w, r)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestIndexerProcessBatch(t *testing.T) {
+	ctx := context.Background()
+	outbox := new(mocks.SearchOutboxMock)
+	outbox.PendingFunc = func(ctx context.Context, limit int, maxAttempts int) ([]entities.OutboxEvent, error) {
+		return []entities.OutboxEvent{
+			{ID: 1, CharacterID: 10, Operation: entities.OutboxUpsert},
+			{ID: 2, CharacterID: 20, Operation: entities.OutboxUpsert},
+			{ID: 3, CharacterID: 10, Operation: entities.OutboxUpsert},
+			{ID: 4, CharacterID: 20, Operation: entities.OutboxDelete},
+		}, nil
+	}
+	outbox.DoneFunc = func(ctx context.Context, eventIDs []int64) error { return nil }
+	outbox.FailedFunc = func(ctx context.Context, eventIDs []int64, reason string) error { return nil }
+	repo := new(mocks.CharactersRepositoryMock)
+	repo.GetByIDsFunc = func(ctx context.Context, characterIDs []int) ([]entities.CharacterEntry, error) {
+		assert.Equal(t, []int{10}, characterIDs)
+		return []entities.CharacterEntry{{CharacterID: 10, CharacterName: "Jon Snow", Nickname: "Lord Snow"}}, nil
+	}
+
+	t.Run("sends upserts and deletes", func(t *testing.T) {
+		var lines []string
+		server := fakeElastic(func(w http.ResponseWriter, r *http.Request) {
+			scanner := bufio.NewScanner(r.Body)
+			for scanner.Scan() {
+				lines = append(lines, scanner.Text())
+			}
+			w.Write([]byte(`{"errors":false,"items":[]}`))
+		})
+		defer server.Close()
+
+		indexer := NewIndexer(outbox, repo, NewIndexManager(server.URL))
+		processed, err := indexer.ProcessBatch(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, processed)
+		assert.Equal(t, []string{
+			`{"index":{"_id":"10","_index":"character_details_1"}}`,
+			`{"character_id":10,"character_name":"Jon Snow","nickname":"Lord Snow","aliases":["Lord Snow"],"royal":false,` +
+				`"suggest":[{"input":["Jon Snow"],"weight":10,"contexts":{"type":["character"]}},{"input":["Lord Snow"],"weight":8,"contexts":{"type":["alias"]}}]}`,
+			`{"delete":{"_id":"20","_index":"character_details_1"}}`,
+		}, lines)
+		assert.Equal(t, []int64{1, 3, 2, 4}, outbox.DoneCalls()[0].EventIDs)
+	})
+
+	t.Run("retries and counts failures", func(t *testing.T) {
+		requests := 0
+		server := fakeElastic(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+		defer server.Close()
+
+		indexer := NewIndexer(outbox, repo, NewIndexManager(server.URL))
+		indexer.Retries = []time.Duration{time.Millisecond, time.Millisecond}
+		_, err := indexer.ProcessBatch(ctx)
+
+		assert.ErrorIs(t, err, entities.ErrUnavailable)
+		assert.Equal(t, 3, requests)
+		assert.ElementsMatch(t, []int64{1, 2, 3, 4}, outbox.FailedCalls()[0].EventIDs)
+	})
+
+	t.Run("rejected documents stay in the outbox", func(t *testing.T) {
+		server := fakeElastic(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"errors":true,"items":[
+				{"index":{"_id":"10","status":400,"error":{"type":"mapper_parsing_exception"}}},
+				{"delete":{"_id":"20","status":404}}
+			]}`))
+		})
+		defer server.Close()
+
+		outbox.DoneFunc = func(ctx context.Context, eventIDs []int64) error {
+			assert.Equal(t, []int64{2, 4}, eventIDs)
+			return nil
+		}
+		outbox.FailedFunc = func(ctx context.Context, eventIDs []int64, reason string) error {
+			assert.Equal(t, []int64{1, 3}, eventIDs)
+			assert.Contains(t, reason, "mapper_parsing_exception")
+			return nil
+		}
+
+		indexer := NewIndexer(outbox, repo, NewIndexManager(server.URL))
+		_, err := indexer.ProcessBatch(ctx)
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestIndexerIndexAll(t *testing.T) {
+	ctx := context.Background()
+	repo := new(mocks.CharactersRepositoryMock)
+	repo.GetAfterFunc = func(ctx context.Context, afterID int, limit int) ([]entities.CharacterEntry, error) {
+		assert.Equal(t, 2, limit)
+		switch afterID {
+		case 0:
+			return []entities.CharacterEntry{
+				{CharacterID: 1, CharacterName: "Jon Snow", ActorName: "Kit Harington"},
+				{CharacterID: 1, CharacterName: "Jon Snow", ActorName: "Someone Else"},
+				{CharacterID: 2, CharacterName: "Arya Stark"},
+			}, nil
+		case 2:
+			return []entities.CharacterEntry{{CharacterID: 3, CharacterName: "Sansa Stark"}}, nil
+		default:
+			return nil, nil
+		}
+	}
+	// Arya was deleted after her batch was read
+	repo.GetByIDsFunc = func(ctx context.Context, characterIDs []int) ([]entities.CharacterEntry, error) {
+		var characters []entities.CharacterEntry
+		for _, id := range characterIDs {
+			if id != 2 {
+				characters = append(characters, entities.CharacterEntry{CharacterID: id})
+			}
+		}
+		return characters, nil
+	}
+
+	var actions []string
+	server := fakeElastic(func(w http.ResponseWriter, r *http.Request) {
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			if strings.HasPrefix(scanner.Text(), `{"create"`) || strings.HasPrefix(scanner.Text(), `{"delete"`) {
+				actions = append(actions, scanner.Text())
+			}
+		}
+		// the Indexer wrote Sansa already
+		w.Write([]byte(`{"errors":true,"items":[{"create":{"_id":"3","status":409,"error":{"type":"version_conflict_engine_exception"}}}]}`))
+	})
+	defer server.Close()
+
+	indexer := NewIndexer(new(mocks.SearchOutboxMock), repo, NewIndexManager(server.URL))
+	indexer.BatchSize = 2
+	written, err := indexer.IndexAll(ctx, "character_details_2")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, written)
+	assert.Equal(t, []string{
+		`{"create":{"_id":"1","_index":"character_details_2"}}`,
+		`{"create":{"_id":"2","_index":"character_details_2"}}`,
+		`{"delete":{"_id":"2","_index":"character_details_2"}}`,
+		`{"create":{"_id":"3","_index":"character_details_2"}}`,
+	}, actions)
+	assert.Len(t, repo.GetAfterCalls(), 3)
+}
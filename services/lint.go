@@ -0,0 +1,178 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/vitalii-komenda/got/entities"
+)
+
+// LintSeverity tells whether an issue fails the lint.
+type LintSeverity string
+
+const (
+	LintError   LintSeverity = "error"
+	LintWarning LintSeverity = "warning"
+)
+
+// LintCategory is the kind of problem an issue reports.
+type LintCategory string
+
+const (
+	LintUnknownTarget   LintCategory = "unknown-relationship-target"
+	LintMissingParent   LintCategory = "missing-parent"
+	LintDuplicateName   LintCategory = "duplicate-name"
+	LintInvalidImageURL LintCategory = "invalid-image-url"
+)
+
+// LintCategories are the categories in report order.
+var LintCategories = []LintCategory{LintUnknownTarget, LintInvalidImageURL, LintMissingParent, LintDuplicateName}
+
+var lintSeverities = map[LintCategory]LintSeverity{
+	LintUnknownTarget:   LintError,
+	LintInvalidImageURL: LintError,
+	LintMissingParent:   LintWarning,
+	LintDuplicateName:   LintWarning,
+}
+
+// LintIssue is one problem of one character of the dataset.
+type LintIssue struct {
+	Category  LintCategory `json:"category"`
+	Severity  LintSeverity `json:"severity"`
+	Character string       `json:"character"`
+	Message   string       `json:"message"`
+}
+
+// LintReport lists the issues of a dataset by category.
+type LintReport struct {
+	Issues   []LintIssue `json:"issues"`
+	Errors   int         `json:"errors"`
+	Warnings int         `json:"warnings"`
+}
+
+func (r *LintReport) add(category LintCategory, character string, format string, args ...interface{}) {
+	issue := LintIssue{Category: category, Severity: lintSeverities[category], Character: character, Message: fmt.Sprintf(format, args...)}
+	r.Issues = append(r.Issues, issue)
+	if issue.Severity == LintError {
+		r.Errors++
+	} else {
+		r.Warnings++
+	}
+}
+
+// LintCharacters finds what would import wrongly or not at all: relationship
+// targets that match no character, so the import skips them, children under
+// parentOf without the parent in their parents, names listed more than once
+// and image URLs that aren't absolute http(s) URLs.
+func LintCharacters(characters []entities.CharacterEntry) LintReport {
+	report := LintReport{Issues: []LintIssue{}}
+	lintDuplicates(&report, characters)
+
+	// relationships are checked the way they import, on merged duplicates
+	merged := mergeDuplicates(characters)
+	resolve := newNameResolver(merged)
+	byKey := map[string]entities.CharacterEntry{}
+	for _, character := range merged {
+		byKey[characterKey(character.CharacterName)] = character
+	}
+
+	for _, character := range merged {
+		name := character.CharacterName
+		for _, field := range []struct {
+			name  string
+			names []string
+		}{
+			{"parents", character.Parents},
+			{"siblings", character.Siblings},
+			{"killed", character.Killed},
+			{"killedBy", character.KilledBy},
+			{"marriedEngaged", character.MarriedEngaged},
+		} {
+			for _, target := range field.names {
+				if resolve(target) == "" {
+					report.add(LintUnknownTarget, name, "%s: %q matches no character or alias", field.name, target)
+				}
+			}
+		}
+
+		for _, image := range []struct {
+			name string
+			url  string
+		}{
+			{"characterImageThumb", character.CharacterImageThumb},
+			{"characterImageFull", character.CharacterImageFull},
+		} {
+			if image.url != "" && !isHTTPURL(image.url) {
+				report.add(LintInvalidImageURL, name, "%s: %q is not an absolute http(s) URL", image.name, image.url)
+			}
+		}
+
+		children, ok := character.Extra["parentOf"]
+		if !ok {
+			continue
+		}
+		var childNames []string
+		if err := json.Unmarshal(children, &childNames); err != nil {
+			report.add(LintMissingParent, name, "parentOf: %s is not a list of names", children)
+			continue
+		}
+		for _, childName := range childNames {
+			childKey := resolve(childName)
+			if childKey == "" {
+				report.add(LintUnknownTarget, name, "parentOf: %q matches no character or alias", childName)
+				continue
+			}
+			child := byKey[childKey]
+			if !slices.ContainsFunc(child.Parents, func(parent string) bool { return resolve(parent) == characterKey(name) }) {
+				report.add(LintMissingParent, name, "parentOf: %q doesn't list %q in parents", child.CharacterName, name)
+			}
+		}
+	}
+
+	slices.SortStableFunc(report.Issues, func(a, b LintIssue) int {
+		return slices.Index(LintCategories, a.Category) - slices.Index(LintCategories, b.Category)
+	})
+	return report
+}
+
+// lintDuplicates reports the names listed more than once. The dataset lists
+// some characters once per actor, which imports fine, so it's a warning.
+func lintDuplicates(report *LintReport, characters []entities.CharacterEntry) {
+	spellings := map[string][]string{}
+	var keys []string
+	for _, character := range characters {
+		key := characterKey(character.CharacterName)
+		if _, ok := spellings[key]; !ok {
+			keys = append(keys, key)
+		}
+		spellings[key] = append(spellings[key], character.CharacterName)
+	}
+	for _, key := range keys {
+		names := spellings[key]
+		if len(names) < 2 {
+			continue
+		}
+		distinct := slices.Compact(slices.Sorted(slices.Values(names)))
+		if len(distinct) > 1 {
+			report.add(LintDuplicateName, names[0], "listed %d times, as %s, merged into one character on import", len(names), quoteAll(distinct))
+		} else {
+			report.add(LintDuplicateName, names[0], "listed %d times, merged into one character on import", len(names))
+		}
+	}
+}
+
+func isHTTPURL(value string) bool {
+	parsed, err := url.Parse(value)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+func quoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vitalii-komenda/got/entities"
+)
+
+func TestLintCharacters(t *testing.T) {
+	report := LintCharacters([]entities.CharacterEntry{
+		{
+			CharacterName: "Eddard Stark", CharacterImageThumb: "https://example.com/ned.jpg",
+			Extra: map[string]json.RawMessage{"parentOf": json.RawMessage(`["Arya Stark", "Jon Snow", "Benjen's Son"]`)},
+		},
+		{CharacterName: "Arya Stark", Nickname: "Arry", Parents: []string{"Eddard Stark"}, CharacterImageFull: "images/arya.jpg"},
+		{CharacterName: "Jon Snow", Killed: []string{"Olly"}},
+		{CharacterName: "Gendry", Siblings: []string{"Arry"}, ActorName: "Joe Dempsie"},
+		{CharacterName: "gendry", ActorName: "Someone Else"},
+	})
+
+	assert.Equal(t, []LintIssue{
+		{Category: LintUnknownTarget, Severity: LintError, Character: "Eddard Stark", Message: `parentOf: "Benjen's Son" matches no character or alias`},
+		{Category: LintUnknownTarget, Severity: LintError, Character: "Jon Snow", Message: `killed: "Olly" matches no character or alias`},
+		{Category: LintInvalidImageURL, Severity: LintError, Character: "Arya Stark", Message: `characterImageFull: "images/arya.jpg" is not an absolute http(s) URL`},
+		{Category: LintMissingParent, Severity: LintWarning, Character: "Eddard Stark", Message: `parentOf: "Jon Snow" doesn't list "Eddard Stark" in parents`},
+		{Category: LintDuplicateName, Severity: LintWarning, Character: "Gendry", Message: `listed 2 times, as "Gendry", "gendry", merged into one character on import`},
+	}, report.Issues)
+	assert.Equal(t, 3, report.Errors)
+	assert.Equal(t, 2, report.Warnings)
+}
+
+func TestLintCharactersClean(t *testing.T) {
+	report := LintCharacters([]entities.CharacterEntry{{CharacterName: "Hodor"}})
+	assert.Empty(t, report.Issues)
+	assert.Zero(t, report.Errors)
+}
@@ -0,0 +1,306 @@
+package services
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/vitalii-komenda/got/entities"
+)
+
+// infoboxFields map the parameters of the character infobox to what they
+// fill in.
+var infoboxFields = map[string]func(character *entities.CharacterEntry, names []string){
+	"name": func(c *entities.CharacterEntry, names []string) { c.CharacterName = names[0] },
+	"house": func(c *entities.CharacterEntry, names []string) {
+		c.HouseName = appendMissing(c.HouseName, houseNames(names))
+	},
+	"allegiance": func(c *entities.CharacterEntry, names []string) {
+		c.HouseName = appendMissing(c.HouseName, houseNames(names))
+	},
+	"father":   func(c *entities.CharacterEntry, names []string) { c.Parents = appendMissing(c.Parents, names) },
+	"mother":   func(c *entities.CharacterEntry, names []string) { c.Parents = appendMissing(c.Parents, names) },
+	"siblings": func(c *entities.CharacterEntry, names []string) { c.Siblings = appendMissing(c.Siblings, names) },
+	"spouse": func(c *entities.CharacterEntry, names []string) {
+		c.MarriedEngaged = appendMissing(c.MarriedEngaged, names)
+	},
+	"killed by": func(c *entities.CharacterEntry, names []string) { c.KilledBy = appendMissing(c.KilledBy, names) },
+	"actor": func(c *entities.CharacterEntry, names []string) {
+		actors := c.AllActors()
+		for _, name := range names {
+			actors = append(actors, entities.ActorEntry{ActorName: name})
+		}
+		// like the dataset, actorName names the first and actors lists
+		// them all when there are more
+		c.ActorName, c.Actors = actors[0].ActorName, nil
+		if len(actors) > 1 {
+			c.Actors = actors
+		}
+	},
+}
+
+// infoboxAliases are other spellings of the infobox parameters.
+var infoboxAliases = map[string]string{
+	"sibling":   "siblings",
+	"spouses":   "spouse",
+	"killedby":  "killed by",
+	"actors":    "actor",
+	"portrayed": "actor",
+	"played by": "actor",
+}
+
+// MediaWikiIssue is an infobox value the reader could not interpret.
+type MediaWikiIssue struct {
+	Page  string
+	Field string
+	Value string
+}
+
+// MediaWikiReport tells how a dump mapped to characters.
+type MediaWikiReport struct {
+	Pages int
+	// WithoutInfobox counts the articles with no character infobox, which
+	// are skipped.
+	WithoutInfobox int
+	// Unmapped are the infobox parameters no field maps to, by how many
+	// pages have them.
+	Unmapped []UnknownField
+	// Uninterpreted are the values of mapped parameters no name could be
+	// read from.
+	Uninterpreted []MediaWikiIssue
+}
+
+// MediaWikiReader reads the character infoboxes of a MediaWiki XML dump of
+// the fan wiki, one character per article that has one.
+type MediaWikiReader struct{}
+
+func (r MediaWikiReader) Read(reader io.Reader) ([]entities.CharacterEntry, error) {
+	characters, _, err := r.ReadWithReport(reader)
+	return characters, err
+}
+
+// ReadWithReport also tells what could not be mapped.
+func (MediaWikiReader) ReadWithReport(reader io.Reader) ([]entities.CharacterEntry, MediaWikiReport, error) {
+	var report MediaWikiReport
+	var characters []entities.CharacterEntry
+	unmapped := map[string]*UnknownField{}
+
+	decoder := xml.NewDecoder(reader)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, report, fmt.Errorf("error reading XML: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "page" {
+			continue
+		}
+		var page struct {
+			Title    string    `xml:"title"`
+			NS       int       `xml:"ns"`
+			Redirect *struct{} `xml:"redirect"`
+			Text     string    `xml:"revision>text"`
+		}
+		if err := decoder.DecodeElement(&page, &start); err != nil {
+			return nil, report, fmt.Errorf("error reading page %d: %w", report.Pages+1, err)
+		}
+		// articles only
+		if page.NS != 0 || page.Redirect != nil {
+			continue
+		}
+		report.Pages++
+
+		params, ok := characterInfobox(page.Text)
+		if !ok {
+			report.WithoutInfobox++
+			continue
+		}
+		character := entities.CharacterEntry{CharacterName: page.Title}
+		for _, param := range params {
+			field := param.name
+			if alias, ok := infoboxAliases[field]; ok {
+				field = alias
+			}
+			fill, ok := infoboxFields[field]
+			if !ok {
+				if _, ok := unmapped[param.name]; !ok {
+					unmapped[param.name] = &UnknownField{Key: param.name}
+				}
+				unmapped[param.name].Count++
+				if len(unmapped[param.name].Examples) < unknownFieldExamples {
+					unmapped[param.name].Examples = append(unmapped[param.name].Examples, page.Title)
+				}
+				continue
+			}
+			names := wikiNames(param.value)
+			if len(names) == 0 {
+				report.Uninterpreted = append(report.Uninterpreted, MediaWikiIssue{Page: page.Title, Field: param.name, Value: param.value})
+				continue
+			}
+			fill(&character, names)
+		}
+		characters = append(characters, character)
+	}
+
+	for _, field := range unmapped {
+		report.Unmapped = append(report.Unmapped, *field)
+	}
+	slices.SortFunc(report.Unmapped, func(a, b UnknownField) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+	return characters, report, nil
+}
+
+type infoboxParam struct {
+	name  string
+	value string
+}
+
+// characterInfobox finds the first {{Character}} or {{Infobox character}}
+// template of the wikitext and returns its named, non-empty parameters.
+func characterInfobox(text string) ([]infoboxParam, bool) {
+	text = wikiComment.ReplaceAllString(text, "")
+	for offset := 0; ; {
+		start := strings.Index(text[offset:], "{{")
+		if start < 0 {
+			return nil, false
+		}
+		start += offset
+		end := templateEnd(text, start)
+		if end < 0 {
+			return nil, false
+		}
+		parts := splitTemplate(text[start+2 : end-2])
+		name := strings.ToLower(strings.TrimSpace(parts[0]))
+		name = strings.TrimSpace(strings.TrimPrefix(name, "infobox"))
+		if name != "character" {
+			offset = start + 2
+			continue
+		}
+
+		var params []infoboxParam
+		for _, part := range parts[1:] {
+			key, value, ok := strings.Cut(part, "=")
+			value = strings.TrimSpace(value)
+			if !ok || value == "" {
+				continue
+			}
+			key = strings.ToLower(strings.Join(strings.Fields(strings.ReplaceAll(key, "_", " ")), " "))
+			params = append(params, infoboxParam{name: key, value: value})
+		}
+		return params, true
+	}
+}
+
+// templateEnd is the index after the "}}" closing the template at start.
+func templateEnd(text string, start int) int {
+	depth := 0
+	for i := start; i < len(text)-1; i++ {
+		switch text[i : i+2] {
+		case "{{":
+			depth++
+			i++
+		case "}}":
+			depth--
+			i++
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return -1
+}
+
+// splitTemplate splits the inside of a template on the "|" that aren't in
+// nested links or templates.
+func splitTemplate(inner string) []string {
+	var parts []string
+	depth, last := 0, 0
+	for i := 0; i < len(inner); i++ {
+		switch {
+		case strings.HasPrefix(inner[i:], "{{"), strings.HasPrefix(inner[i:], "[["):
+			depth++
+			i++
+		case strings.HasPrefix(inner[i:], "}}"), strings.HasPrefix(inner[i:], "]]"):
+			depth--
+			i++
+		case inner[i] == '|' && depth == 0:
+			parts = append(parts, inner[last:i])
+			last = i + 1
+		}
+	}
+	return append(parts, inner[last:])
+}
+
+var (
+	wikiComment   = regexp.MustCompile(`(?s)<!--.*?-->`)
+	wikiRef       = regexp.MustCompile(`(?is)<ref[^>/]*/>|<ref[^>]*>.*?</ref>`)
+	wikiLink      = regexp.MustCompile(`\[\[([^\[\]|]+)(?:\|[^\[\]]*)?\]\]`)
+	wikiTemplate  = regexp.MustCompile(`\{\{[^{}]*\}\}`)
+	wikiBreak     = regexp.MustCompile(`(?i)<br\s*/?>|\n`)
+	wikiNote      = regexp.MustCompile(`\([^()]*\)`)
+	wikiMarkup    = regexp.MustCompile(`'{2,}|<[^>]+>|^[*#:\s]+`)
+	wikiSeparator = regexp.MustCompile(`[,;]`)
+)
+
+// wikiNames reads the names an infobox value lists: the targets of its
+// links or else its lines of plain text. Notes in parentheses, references
+// and templates like {{Deceased}} are left out.
+func wikiNames(value string) []string {
+	value = wikiRef.ReplaceAllString(value, "")
+	var names []string
+	if links := wikiLink.FindAllStringSubmatch(value, -1); len(links) > 0 {
+		for _, link := range links {
+			target, _, _ := strings.Cut(link[1], "#")
+			target = strings.TrimSpace(strings.ReplaceAll(target, "_", " "))
+			if target == "" || strings.Contains(target, ":") {
+				// File:, Category: and interwiki links
+				continue
+			}
+			names = appendMissing(names, []string{target})
+		}
+		return names
+	}
+
+	// innermost first, for nested templates
+	for wikiTemplate.MatchString(value) {
+		value = wikiTemplate.ReplaceAllString(value, "")
+	}
+	for _, line := range wikiBreak.Split(value, -1) {
+		line = wikiMarkup.ReplaceAllString(wikiNote.ReplaceAllString(line, ""), "")
+		for _, name := range wikiSeparator.Split(line, -1) {
+			if name = strings.TrimSpace(name); name != "" && !isUnknownValue(name) {
+				names = appendMissing(names, []string{name})
+			}
+		}
+	}
+	return names
+}
+
+func isUnknownValue(value string) bool {
+	switch strings.ToLower(strings.Trim(value, "?.")) {
+	case "", "unknown", "none", "n/a", "various":
+		return true
+	}
+	return false
+}
+
+// houseNames drops the "House " prefix, as the dataset names houses
+// "Stark" rather than "House Stark".
+func houseNames(names []string) []string {
+	houses := make([]string, len(names))
+	for i, name := range names {
+		houses[i] = strings.TrimPrefix(name, "House ")
+	}
+	return houses
+}
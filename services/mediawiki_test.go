@@ -0,0 +1,87 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vitalii-komenda/got/entities"
+)
+
+const wikiDump = `<mediawiki xmlns="http://www.mediawiki.org/xml/export-0.10/" xml:lang="en">
+  <siteinfo><sitename>Game of Thrones Wiki</sitename></siteinfo>
+  <page>
+    <title>Jon Snow</title>
+    <ns>0</ns>
+    <revision><text xml:space="preserve">{{Spoiler}}
+{{Infobox character
+| Image = [[File:Jon Snow.jpg|250px]]
+| Name = Jon Snow
+| House = [[House Stark]]&lt;br&gt;[[House Targaryen|Targaryen]]
+| Allegiance = [[Night's Watch]] (formerly)
+| Father = [[Rhaegar Targaryen]]&lt;ref&gt;Season 7, episode 7&lt;/ref&gt;
+| Mother = [[Lyanna Stark]]
+| Siblings = [[Robb Stark]] {{Deceased}}&lt;br&gt;[[Arya_Stark]]
+| Killed by = [[Olly]], [[Alliser Thorne]] (resurrected)
+| Born = 283 AC
+| Actor = [[Kit Harington]]
+}}
+'''Jon Snow''' is ...</text></revision>
+  </page>
+  <page>
+    <title>Hot Pie</title>
+    <ns>0</ns>
+    <revision><text>{{Character
+|father = Unknown
+|spouse = &lt;!-- none --&gt;
+|portrayed = Ben Hawkey
+|born = 286 AC
+}}</text></revision>
+  </page>
+  <page>
+    <title>Winterfell</title>
+    <ns>0</ns>
+    <revision><text>{{Location|region = The North}}</text></revision>
+  </page>
+  <page>
+    <title>Lord Snow</title>
+    <ns>0</ns>
+    <redirect title="Jon Snow" />
+    <revision><text>#REDIRECT [[Jon Snow]]</text></revision>
+  </page>
+  <page>
+    <title>Template:Character</title>
+    <ns>10</ns>
+    <revision><text>{{Character}}</text></revision>
+  </page>
+</mediawiki>`
+
+func TestMediaWikiReader(t *testing.T) {
+	characters, report, err := MediaWikiReader{}.ReadWithReport(strings.NewReader(wikiDump))
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.CharacterEntry{
+		{
+			CharacterName: "Jon Snow",
+			HouseName:     entities.HouseNameType{"Stark", "Targaryen", "Night's Watch"},
+			Parents:       []string{"Rhaegar Targaryen", "Lyanna Stark"},
+			Siblings:      []string{"Robb Stark", "Arya Stark"},
+			KilledBy:      []string{"Olly", "Alliser Thorne"},
+			ActorName:     "Kit Harington",
+		},
+		{CharacterName: "Hot Pie", ActorName: "Ben Hawkey"},
+	}, characters)
+
+	assert.Equal(t, 3, report.Pages)
+	assert.Equal(t, 1, report.WithoutInfobox)
+	assert.Equal(t, []UnknownField{
+		{Key: "born", Count: 2, Examples: []string{"Jon Snow", "Hot Pie"}},
+		{Key: "image", Count: 1, Examples: []string{"Jon Snow"}},
+	}, report.Unmapped)
+	assert.Equal(t, []MediaWikiIssue{{Page: "Hot Pie", Field: "father", Value: "Unknown"}}, report.Uninterpreted)
+}
+
+func TestWikiNames(t *testing.T) {
+	assert.Equal(t, []string{"Tyrion Lannister", "Sansa Stark"}, wikiNames("[[Tyrion Lannister]] (annulled)<br />[[Sansa Stark|Sansa]]"))
+	assert.Equal(t, []string{"Shae", "Tywin Lannister"}, wikiNames("* Shae\n* Tywin Lannister {{Deceased|{{S|4}}}}"))
+	assert.Empty(t, wikiNames("''Unknown''"))
+}
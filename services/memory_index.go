@@ -0,0 +1,405 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/vitalii-komenda/got/entities"
+)
+
+var _ entities.Searcher = &MemoryIndex{}
+var _ entities.CharacterListener = &MemoryIndex{}
+
+// memoryIndexFields are the searched fields and their boosts, the same as
+// the multi_match query sent to Elasticsearch.
+var memoryIndexFields = []struct {
+	name  string
+	boost float64
+	terms func(c entities.CharacterEntry) []string
+}{
+	{"character_name", 2, func(c entities.CharacterEntry) []string { return []string{c.CharacterName} }},
+	{"aliases", 2, func(c entities.CharacterEntry) []string {
+		names := make([]string, 0, len(c.Aliases))
+		for _, alias := range c.AllAliases() {
+			names = append(names, alias.Name)
+		}
+		return names
+	}},
+	{"actor_name", 1, func(c entities.CharacterEntry) []string { return []string{c.ActorName} }},
+	{"siblings", 1, func(c entities.CharacterEntry) []string { return c.Siblings }},
+}
+
+// MemoryIndex is an inverted index over all characters kept in memory, so
+// search works without Elasticsearch. Build loads it from the repository
+// and it stays current as a CharacterListener of the characters controller.
+//
+// Query terms match index terms exactly, with up to two typos like
+// Elasticsearch's AUTO fuzziness, or as a prefix when they end with "*".
+// The conditions of a structured query are checked on the characters.
+type MemoryIndex struct {
+	charactersRepo entities.CharactersRepository
+
+	mu         sync.RWMutex
+	characters map[int][]entities.CharacterEntry
+	// postings maps a term to the characters containing it and the boost of
+	// the best field it appears in.
+	postings map[string]map[int]float64
+	// terms are the keys of postings, sorted for prefix lookups.
+	terms []string
+}
+
+func NewMemoryIndex(charactersRepo entities.CharactersRepository) *MemoryIndex {
+	return &MemoryIndex{
+		charactersRepo: charactersRepo,
+		characters:     map[int][]entities.CharacterEntry{},
+		postings:       map[string]map[int]float64{},
+	}
+}
+
+// Build indexes every character in the repository.
+func (m *MemoryIndex) Build(ctx context.Context) error {
+	byID := map[int][]entities.CharacterEntry{}
+	for page := 0; ; page++ {
+		characters, err := m.charactersRepo.GetAll(ctx, page)
+		if err != nil {
+			return err
+		}
+		if len(characters) == 0 {
+			break
+		}
+		for _, character := range characters {
+			byID[character.CharacterID] = append(byID[character.CharacterID], character)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, rows := range byID {
+		m.put(id, rows)
+	}
+	m.sortTerms()
+	return nil
+}
+
+func (m *MemoryIndex) CharacterSaved(ctx context.Context, characterID int) error {
+	rows, err := m.charactersRepo.Get(ctx, characterID)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.remove(characterID)
+	if len(rows) > 0 {
+		m.put(characterID, rows)
+	}
+	m.sortTerms()
+	return nil
+}
+
+func (m *MemoryIndex) CharacterDeleted(ctx context.Context, characterID int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.remove(characterID)
+	m.sortTerms()
+	return nil
+}
+
+func (m *MemoryIndex) Search(ctx context.Context, query entities.SearchQuery) ([]entities.CharacterEntry, error) {
+	if query.Page < 0 {
+		return nil, entities.NewValidationError("page must not be negative")
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	node := query.Node()
+	scores := map[int]float64{}
+	if text := entities.QueryText(node); text != "" {
+		scores = m.textScores(text)
+	} else {
+		for id := range m.characters {
+			scores[id] = 0
+		}
+	}
+	matches, err := m.predicate(node)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(scores))
+	for id := range scores {
+		if matches(id) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if scores[ids[i]] != scores[ids[j]] {
+			return scores[ids[i]] > scores[ids[j]]
+		}
+		return m.characters[ids[i]][0].CharacterName < m.characters[ids[j]][0].CharacterName
+	})
+
+	from := query.Page * 25
+	if from >= len(ids) {
+		return []entities.CharacterEntry{}, nil
+	}
+	ids = ids[from:min(from+25, len(ids))]
+
+	characters := []entities.CharacterEntry{}
+	for _, id := range ids {
+		characters = append(characters, m.characters[id]...)
+	}
+	return characters, nil
+}
+
+// textScores adds up the scores of the words of the text.
+func (m *MemoryIndex) textScores(text string) map[int]float64 {
+	scores := map[int]float64{}
+	for _, word := range strings.Fields(text) {
+		prefix := strings.HasSuffix(word, "*")
+		for _, token := range tokenize(strings.TrimSuffix(word, "*")) {
+			for id, score := range m.match(token, prefix) {
+				scores[id] += score
+			}
+		}
+	}
+	return scores
+}
+
+// predicate compiles a structured query to a check of a character. The
+// free text of an AndNode is left to textScores.
+func (m *MemoryIndex) predicate(node entities.QueryNode) (func(id int) bool, error) {
+	switch n := node.(type) {
+	case entities.TextNode:
+		scores := m.textScores(n.Text)
+		return func(id int) bool { _, ok := scores[id]; return ok }, nil
+	case entities.AndNode:
+		var predicates []func(id int) bool
+		for _, child := range n.Nodes {
+			if _, ok := child.(entities.TextNode); ok {
+				continue
+			}
+			predicate, err := m.predicate(child)
+			if err != nil {
+				return nil, err
+			}
+			predicates = append(predicates, predicate)
+		}
+		return func(id int) bool {
+			for _, predicate := range predicates {
+				if !predicate(id) {
+					return false
+				}
+			}
+			return true
+		}, nil
+	case entities.NotNode:
+		predicate, err := m.predicate(n.Node)
+		if err != nil {
+			return nil, err
+		}
+		return func(id int) bool { return !predicate(id) }, nil
+	case entities.FieldNode:
+		matches, err := fieldPredicate(n)
+		if err != nil {
+			return nil, err
+		}
+		return func(id int) bool { return matches(m.characters[id]) }, nil
+	default:
+		return nil, fmt.Errorf("unknown query node %T", node)
+	}
+}
+
+func fieldPredicate(n entities.FieldNode) (func(rows []entities.CharacterEntry) bool, error) {
+	words := tokenize(n.Value)
+	anyContains := func(names []string) bool {
+		for _, name := range names {
+			if containsWords(tokenize(name), words) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch n.Field {
+	case entities.FieldName:
+		return func(rows []entities.CharacterEntry) bool { return anyContains([]string{rows[0].CharacterName}) }, nil
+	case entities.FieldAlias:
+		return func(rows []entities.CharacterEntry) bool {
+			var names []string
+			for _, alias := range rows[0].AllAliases() {
+				names = append(names, alias.Name)
+			}
+			return anyContains(names)
+		}, nil
+	case entities.FieldHouse:
+		return func(rows []entities.CharacterEntry) bool { return anyContains(rows[0].HouseName) }, nil
+	case entities.FieldActor:
+		return func(rows []entities.CharacterEntry) bool {
+			var names []string
+			for _, row := range rows {
+				for _, actor := range row.AllActors() {
+					names = append(names, actor.ActorName)
+				}
+			}
+			return anyContains(names)
+		}, nil
+	case entities.FieldRoyal:
+		return func(rows []entities.CharacterEntry) bool { return rows[0].Royal == n.Bool }, nil
+	case entities.FieldAlive:
+		// the dead are the characters someone killed
+		return func(rows []entities.CharacterEntry) bool { return (len(rows[0].KilledBy) == 0) == n.Bool }, nil
+	case entities.FieldKilled:
+		return func(rows []entities.CharacterEntry) bool {
+			killed := len(rows[0].Killed)
+			switch n.Op {
+			case entities.OpGreater:
+				return killed > n.Number
+			case entities.OpGreaterEqual:
+				return killed >= n.Number
+			case entities.OpLess:
+				return killed < n.Number
+			case entities.OpLessEqual:
+				return killed <= n.Number
+			default:
+				return killed == n.Number
+			}
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown query field %q", n.Field)
+	}
+}
+
+// containsWords tells whether words appear in tokens in a row.
+func containsWords(tokens []string, words []string) bool {
+	if len(words) == 0 {
+		return false
+	}
+	for i := 0; i+len(words) <= len(tokens); i++ {
+		if slices.Equal(tokens[i:i+len(words)], words) {
+			return true
+		}
+	}
+	return false
+}
+
+// match scores the characters containing the token, 1 for an exact match
+// down to about 0.5 for a prefix or a misspelling, times the field boost.
+func (m *MemoryIndex) match(token string, prefix bool) map[int]float64 {
+	scores := map[int]float64{}
+	add := func(term string, quality float64) {
+		for id, boost := range m.postings[term] {
+			scores[id] = max(scores[id], boost*quality)
+		}
+	}
+
+	if prefix {
+		for i := sort.SearchStrings(m.terms, token); i < len(m.terms) && strings.HasPrefix(m.terms[i], token); i++ {
+			add(m.terms[i], 0.5+0.5*float64(len(token))/float64(len(m.terms[i])))
+		}
+		return scores
+	}
+
+	allowed := fuzziness(token)
+	for _, term := range m.terms {
+		if term == token {
+			add(term, 1)
+			continue
+		}
+		if allowed == 0 || abs(len(term)-len(token)) > allowed {
+			continue
+		}
+		if distance := levenshtein(token, term); distance <= allowed {
+			add(term, 1-float64(distance)/float64(len(token)+1))
+		}
+	}
+	return scores
+}
+
+func (m *MemoryIndex) put(id int, rows []entities.CharacterEntry) {
+	m.characters[id] = rows
+	for _, row := range rows {
+		for _, field := range memoryIndexFields {
+			for _, value := range field.terms(row) {
+				for _, term := range tokenize(value) {
+					if m.postings[term] == nil {
+						m.postings[term] = map[int]float64{}
+					}
+					m.postings[term][id] = max(m.postings[term][id], field.boost)
+				}
+			}
+		}
+	}
+}
+
+func (m *MemoryIndex) remove(id int) {
+	delete(m.characters, id)
+	for term, ids := range m.postings {
+		delete(ids, id)
+		if len(ids) == 0 {
+			delete(m.postings, term)
+		}
+	}
+}
+
+func (m *MemoryIndex) sortTerms() {
+	m.terms = make([]string, 0, len(m.postings))
+	for term := range m.postings {
+		m.terms = append(m.terms, term)
+	}
+	sort.Strings(m.terms)
+}
+
+// tokenize splits text into lowercase words without accents or
+// apostrophes, the same way slugs are made.
+func tokenize(text string) []string {
+	slug := entities.Slugify(text)
+	if slug == "" {
+		return nil
+	}
+	return strings.Split(slug, "-")
+}
+
+// fuzziness is the number of typos allowed in a term, following
+// Elasticsearch's AUTO setting.
+func fuzziness(term string) int {
+	switch n := len(term); {
+	case n <= 2:
+		return 0
+	case n <= 5:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
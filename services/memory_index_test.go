@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vitalii-komenda/got/entities"
+	"github.com/vitalii-komenda/got/mocks"
+)
+
+func names(characters []entities.CharacterEntry) []string {
+	result := []string{}
+	for _, c := range characters {
+		result = append(result, c.CharacterName)
+	}
+	return result
+}
+
+func TestMemoryIndex(t *testing.T) {
+	ctx := context.Background()
+	characters := []entities.CharacterEntry{
+		{CharacterID: 1, CharacterName: "Jon Snow", ActorName: "Kit Harington", Siblings: []string{"Arya Stark"}, HouseName: []string{"House Stark"}, KilledBy: []string{"Olly"}},
+		{CharacterID: 2, CharacterName: "Arya Stark", ActorName: "Maisie Williams", Siblings: []string{"Jon Snow"}, HouseName: []string{"House Stark"}, Killed: []string{"Polliver", "Walder Frey", "Meryn Trant", "Night King"}},
+		{CharacterID: 3, CharacterName: "Sandor Clegane", Nickname: "The Hound", ActorName: "Rory McCann"},
+		{CharacterID: 4, CharacterName: "Jaqen H'ghar", ActorName: "Tom Wlaschiha"},
+	}
+	repo := new(mocks.CharactersRepositoryMock)
+	repo.GetAllFunc = func(ctx context.Context, page int) ([]entities.CharacterEntry, error) {
+		if page > 0 {
+			return nil, nil
+		}
+		return characters, nil
+	}
+	index := NewMemoryIndex(repo)
+	assert.NoError(t, index.Build(ctx))
+
+	search := func(term string) []string {
+		result, err := index.Search(ctx, entities.SearchQuery{Term: term})
+		assert.NoError(t, err)
+		return names(result)
+	}
+
+	t.Run("name is boosted over sibling", func(t *testing.T) {
+		assert.Equal(t, []string{"Jon Snow", "Arya Stark"}, search("snow"))
+	})
+
+	t.Run("fuzzy", func(t *testing.T) {
+		assert.Equal(t, []string{"Sandor Clegane"}, search("Sandr Cleagne"))
+		assert.Equal(t, []string{"Jaqen H'ghar"}, search("jaqen hghar"))
+	})
+
+	t.Run("alias and actor", func(t *testing.T) {
+		assert.Equal(t, []string{"Sandor Clegane"}, search("hound"))
+		assert.Equal(t, []string{"Jon Snow"}, search("harington"))
+	})
+
+	t.Run("negative page", func(t *testing.T) {
+		_, err := index.Search(ctx, entities.SearchQuery{Term: "snow", Page: -1})
+		assert.ErrorIs(t, err, entities.ErrValidation)
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		assert.Equal(t, []string{"Sandor Clegane"}, search("cleg*"))
+		assert.Empty(t, search("cleg"))
+	})
+
+	t.Run("structured query", func(t *testing.T) {
+		structured := func(term string) []string {
+			query, err := entities.ParseQuery(term)
+			assert.NoError(t, err)
+			result, err := index.Search(ctx, entities.SearchQuery{Term: term, Query: query})
+			assert.NoError(t, err)
+			return names(result)
+		}
+		assert.Equal(t, []string{"Arya Stark", "Jon Snow"}, structured("house:stark"))
+		assert.Equal(t, []string{"Arya Stark"}, structured("house:Stark killed:>3"))
+		assert.Equal(t, []string{"Arya Stark"}, structured("house:Stark alive:true"))
+		assert.Equal(t, []string{"Jon Snow"}, structured(`snow -actor:"Maisie Williams"`))
+		assert.Equal(t, []string{"Sandor Clegane"}, structured("alias:hound royal:false"))
+		assert.Empty(t, structured("house:Stark royal:true"))
+	})
+
+	t.Run("updates on writes", func(t *testing.T) {
+		repo.GetFunc = func(ctx context.Context, characterID int) ([]entities.CharacterEntry, error) {
+			return []entities.CharacterEntry{{CharacterID: 3, CharacterName: "Gregor Clegane", Nickname: "The Mountain"}}, nil
+		}
+		assert.NoError(t, index.CharacterSaved(ctx, 3))
+		assert.Empty(t, search("hound"))
+		assert.Equal(t, []string{"Gregor Clegane"}, search("mountain"))
+
+		assert.NoError(t, index.CharacterDeleted(ctx, 3))
+		assert.Empty(t, search("mountain"))
+	})
+}
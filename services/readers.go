@@ -0,0 +1,384 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/vitalii-komenda/got/entities"
+	"gopkg.in/yaml.v3"
+)
+
+// CharacterReader reads the characters of a dataset in one format.
+type CharacterReader interface {
+	Read(r io.Reader) ([]entities.CharacterEntry, error)
+}
+
+// characterReaders are the supported formats by name.
+var characterReaders = map[string]CharacterReader{
+	"json":      JSONReader{},
+	"ndjson":    NDJSONReader{},
+	"csv":       CSVReader{},
+	"yaml":      YAMLReader{},
+	"mediawiki": MediaWikiReader{},
+}
+
+// formatExtensions map file extensions to formats.
+var formatExtensions = map[string]string{
+	".json":   "json",
+	".ndjson": "ndjson",
+	".jsonl":  "ndjson",
+	".csv":    "csv",
+	".yaml":   "yaml",
+	".yml":    "yaml",
+	".xml":    "mediawiki",
+}
+
+// NewCharacterReader returns the reader of the format, or when format is
+// empty the one the extension of path stands for, JSON by default.
+func NewCharacterReader(format string, path string) (CharacterReader, error) {
+	if format == "" {
+		format = formatExtensions[strings.ToLower(filepath.Ext(path))]
+	}
+	if format == "" {
+		format = "json"
+	}
+	reader, ok := characterReaders[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown format %q, use one of %s", format, strings.Join(CharacterFormats(), ", "))
+	}
+	return reader, nil
+}
+
+// CharacterFormats are the names of the supported formats.
+func CharacterFormats() []string {
+	formats := make([]string, 0, len(characterReaders))
+	for format := range characterReaders {
+		formats = append(formats, format)
+	}
+	slices.Sort(formats)
+	return formats
+}
+
+// characterKeys and actorKeys are the JSON keys CharacterEntry and
+// ActorEntry have a field for.
+var (
+	characterKeys = jsonKeys(reflect.TypeOf(entities.CharacterEntry{}))
+	actorKeys     = jsonKeys(reflect.TypeOf(entities.ActorEntry{}))
+)
+
+// actorExtraPrefix prefixes the Extra keys holding an unknown key of the
+// actors entries, as an object with the value of each actor by actorName,
+// or by "#" and the position for actors without a name.
+const actorExtraPrefix = "actors."
+
+func actorExtraKey(actor entities.ActorEntry, i int) string {
+	if actor.ActorName == "" {
+		return "#" + strconv.Itoa(i)
+	}
+	return actor.ActorName
+}
+
+func jsonKeys(t reflect.Type) map[string]bool {
+	keys := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+}
+
+// knownKey tells whether encoding/json decodes the key into one of the
+// fields, which it matches exactly or else ignoring case.
+func knownKey(keys map[string]bool, key string) bool {
+	if keys[key] {
+		return true
+	}
+	for known := range keys {
+		if strings.EqualFold(known, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeCharacter decodes a character object, keeping the keys no field
+// maps to in Extra instead of dropping them, those of the actors entries
+// included.
+func decodeCharacter(data []byte) (entities.CharacterEntry, error) {
+	var character entities.CharacterEntry
+	if err := json.Unmarshal(data, &character); err != nil {
+		return character, err
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return character, err
+	}
+	extra := map[string]json.RawMessage{}
+	for key, value := range object {
+		if strings.EqualFold(key, "actors") {
+			if err := decodeActorExtra(value, character.Actors, extra); err != nil {
+				return character, err
+			}
+		}
+		if !knownKey(characterKeys, key) {
+			extra[key] = value
+		}
+	}
+	if len(extra) > 0 {
+		character.Extra = extra
+	}
+	return character, nil
+}
+
+// decodeActorExtra keeps the keys of the actors entries no ActorEntry field
+// maps to under actorExtraPrefix.
+func decodeActorExtra(data json.RawMessage, actors []entities.ActorEntry, extra map[string]json.RawMessage) error {
+	var objects []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &objects); err != nil {
+		return err
+	}
+	values := map[string]map[string]json.RawMessage{}
+	for i, object := range objects {
+		// only differently cased duplicates of the key can tell apart
+		if i >= len(actors) {
+			break
+		}
+		for key, value := range object {
+			if knownKey(actorKeys, key) {
+				continue
+			}
+			if _, ok := values[key]; !ok {
+				values[key] = map[string]json.RawMessage{}
+			}
+			values[key][actorExtraKey(actors[i], i)] = value
+		}
+	}
+	for key, byActor := range values {
+		data, err := json.Marshal(byActor)
+		if err != nil {
+			return err
+		}
+		extra[actorExtraPrefix+key] = data
+	}
+	return nil
+}
+
+// JSONReader reads data/got-characters.json, {"characters": [...]}.
+type JSONReader struct{}
+
+func (JSONReader) Read(r io.Reader) ([]entities.CharacterEntry, error) {
+	var file struct {
+		Characters []json.RawMessage `json:"characters"`
+	}
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("error decoding JSON: %w", err)
+	}
+	characters := make([]entities.CharacterEntry, len(file.Characters))
+	for i, data := range file.Characters {
+		var err error
+		if characters[i], err = decodeCharacter(data); err != nil {
+			return nil, fmt.Errorf("error decoding character %d: %w", i+1, err)
+		}
+	}
+	return characters, nil
+}
+
+// NDJSONReader reads one character object per line, skipping blank lines.
+type NDJSONReader struct{}
+
+func (NDJSONReader) Read(r io.Reader) ([]entities.CharacterEntry, error) {
+	var characters []entities.CharacterEntry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		character, err := decodeCharacter(scanner.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("error decoding line %d: %w", line, err)
+		}
+		characters = append(characters, character)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading NDJSON: %w", err)
+	}
+	return characters, nil
+}
+
+// YAMLReader reads the JSON layout written as YAML.
+type YAMLReader struct{}
+
+func (YAMLReader) Read(r io.Reader) ([]entities.CharacterEntry, error) {
+	var document interface{}
+	if err := yaml.NewDecoder(r).Decode(&document); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("error decoding YAML: %w", err)
+	}
+	// through JSON, so the fields decode exactly as they do from JSON
+	data, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding YAML: %w", err)
+	}
+	return JSONReader{}.Read(bytes.NewReader(data))
+}
+
+// CSV lists are separated by ";", the parts of an alias or actor in a
+// list by "|" and the seasons of an actor by ",".
+const (
+	csvListSeparator    = ";"
+	csvPartSeparator    = "|"
+	csvSeasonsSeparator = ","
+	// csvJSONSuffix marks an unknown column whose cells hold JSON, as in
+	// parentOf:json, rather than text.
+	csvJSONSuffix = ":json"
+)
+
+// CSVColumns are the columns CSVReader knows, named as the JSON fields.
+// aliases lists name|type, actors lists name|link|seasons for the actors
+// besides actorName. Other columns are kept in Extra as strings, or as the
+// JSON they hold when named with csvJSONSuffix.
+var CSVColumns = []string{
+	"characterName", "houseName", "royal", "nickname", "aliases",
+	"characterImageThumb", "characterImageFull", "characterLink",
+	"actorName", "actorLink", "actors",
+	"parents", "siblings", "killed", "killedBy", "marriedEngaged",
+}
+
+// CSVReader reads one character per row, with a header row naming the
+// columns in any order. Lists, like parents, are separated by ";", so
+// the content team can keep the data in a spreadsheet.
+type CSVReader struct{}
+
+func (CSVReader) Read(r io.Reader) ([]entities.CharacterEntry, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading CSV header: %w", err)
+	}
+	if !slices.Contains(header, "characterName") {
+		return nil, fmt.Errorf("CSV needs a characterName column")
+	}
+
+	var characters []entities.CharacterEntry
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return characters, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		row := map[string]string{}
+		for i, column := range header {
+			row[column] = strings.TrimSpace(record[i])
+		}
+		character, err := csvCharacter(row)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CSV line %d: %w", line, err)
+		}
+		characters = append(characters, character)
+	}
+}
+
+func csvCharacter(row map[string]string) (entities.CharacterEntry, error) {
+	character := entities.CharacterEntry{
+		CharacterName:       row["characterName"],
+		HouseName:           csvList(row["houseName"]),
+		Nickname:            row["nickname"],
+		CharacterImageThumb: row["characterImageThumb"],
+		CharacterImageFull:  row["characterImageFull"],
+		CharacterLink:       row["characterLink"],
+		ActorName:           row["actorName"],
+		ActorLink:           row["actorLink"],
+		Parents:             csvList(row["parents"]),
+		Siblings:            csvList(row["siblings"]),
+		Killed:              csvList(row["killed"]),
+		KilledBy:            csvList(row["killedBy"]),
+		MarriedEngaged:      csvList(row["marriedEngaged"]),
+	}
+	for column, cell := range row {
+		if slices.Contains(CSVColumns, column) || cell == "" {
+			continue
+		}
+		key, value, err := csvExtra(column, cell)
+		if err != nil {
+			return character, err
+		}
+		if character.Extra == nil {
+			character.Extra = map[string]json.RawMessage{}
+		}
+		character.Extra[key] = value
+	}
+	if row["royal"] != "" {
+		royal, err := strconv.ParseBool(row["royal"])
+		if err != nil {
+			return character, fmt.Errorf("royal must be true or false, not %q", row["royal"])
+		}
+		character.Royal = royal
+	}
+	for _, alias := range csvList(row["aliases"]) {
+		name, aliasType, _ := strings.Cut(alias, csvPartSeparator)
+		character.Aliases = append(character.Aliases, entities.Alias{Name: strings.TrimSpace(name), Type: entities.AliasType(strings.TrimSpace(aliasType))})
+	}
+	for _, actor := range csvList(row["actors"]) {
+		parts := strings.Split(actor, csvPartSeparator)
+		entry := entities.ActorEntry{ActorName: strings.TrimSpace(parts[0])}
+		if len(parts) > 1 {
+			entry.ActorLink = strings.TrimSpace(parts[1])
+		}
+		if len(parts) > 2 {
+			for _, season := range csvList(strings.ReplaceAll(parts[2], csvSeasonsSeparator, csvListSeparator)) {
+				number, err := strconv.Atoi(season)
+				if err != nil {
+					return character, fmt.Errorf("seasons of %s must be numbers, not %q", entry.ActorName, season)
+				}
+				entry.SeasonsActive = append(entry.SeasonsActive, number)
+			}
+		}
+		character.Actors = append(character.Actors, entry)
+	}
+	return character, nil
+}
+
+// csvExtra returns the Extra key and value of a cell of an unknown column:
+// the JSON it holds, as in ["Arya Stark"] or true, when the column is named
+// with csvJSONSuffix, else the text, so a name like 1984 or null stays one.
+func csvExtra(column string, cell string) (string, json.RawMessage, error) {
+	key, isJSON := strings.CutSuffix(column, csvJSONSuffix)
+	if !isJSON {
+		value, _ := json.Marshal(cell)
+		return key, value, nil
+	}
+	if !json.Valid([]byte(cell)) {
+		return key, nil, fmt.Errorf("%s must hold JSON, not %q", column, cell)
+	}
+	return key, json.RawMessage(cell), nil
+}
+
+// csvList splits a ";" separated cell, leaving out empty items.
+func csvList(cell string) []string {
+	var items []string
+	for _, item := range strings.Split(cell, csvListSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
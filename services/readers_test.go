@@ -0,0 +1,155 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vitalii-komenda/got/entities"
+)
+
+var jonSnow = entities.CharacterEntry{
+	CharacterName: "Jon Snow",
+	HouseName:     entities.HouseNameType{"Stark", "Targaryen"},
+	Royal:         true,
+	Nickname:      "Lord Snow",
+	Aliases:       []entities.Alias{{Name: "The White Wolf", Type: entities.AliasTitle}},
+	ActorName:     "Kit Harington",
+	ActorLink:     "/name/nm3229685/",
+	Actors:        []entities.ActorEntry{{ActorName: "Kit Harington", ActorLink: "/name/nm3229685/", SeasonsActive: []int{1, 2}}},
+	Parents:       []string{"Lyanna Stark", "Rhaegar Targaryen"},
+	KilledBy:      []string{"Olly"},
+}
+
+func TestCharacterReaders(t *testing.T) {
+	cases := map[string]string{
+		"json": `{"characters": [{
+			"characterName": "Jon Snow", "houseName": ["Stark", "Targaryen"], "royal": true, "nickname": "Lord Snow",
+			"aliases": [{"name": "The White Wolf", "type": "title"}],
+			"actorName": "Kit Harington", "actorLink": "/name/nm3229685/",
+			"actors": [{"actorName": "Kit Harington", "actorLink": "/name/nm3229685/", "seasonsActive": [1, 2]}],
+			"parents": ["Lyanna Stark", "Rhaegar Targaryen"], "killedBy": ["Olly"]
+		}]}`,
+		"ndjson": `
+{"characterName": "Jon Snow", "houseName": ["Stark", "Targaryen"], "royal": true, "nickname": "Lord Snow", "aliases": [{"name": "The White Wolf", "type": "title"}], "actorName": "Kit Harington", "actorLink": "/name/nm3229685/", "actors": [{"actorName": "Kit Harington", "actorLink": "/name/nm3229685/", "seasonsActive": [1, 2]}], "parents": ["Lyanna Stark", "Rhaegar Targaryen"], "killedBy": ["Olly"]}
+`,
+		"csv": `characterName,houseName,royal,nickname,aliases,actorName,actorLink,actors,parents,killedBy
+Jon Snow,Stark;Targaryen,true,Lord Snow,The White Wolf|title,Kit Harington,/name/nm3229685/,"Kit Harington|/name/nm3229685/|1,2",Lyanna Stark; Rhaegar Targaryen,Olly
+`,
+		"yaml": `
+characters:
+  - characterName: Jon Snow
+    houseName: [Stark, Targaryen]
+    royal: true
+    nickname: Lord Snow
+    aliases:
+      - {name: The White Wolf, type: title}
+    actorName: Kit Harington
+    actorLink: /name/nm3229685/
+    actors:
+      - {actorName: Kit Harington, actorLink: /name/nm3229685/, seasonsActive: [1, 2]}
+    parents: [Lyanna Stark, Rhaegar Targaryen]
+    killedBy: [Olly]
+`,
+	}
+	for format, input := range cases {
+		t.Run(format, func(t *testing.T) {
+			reader, err := NewCharacterReader(format, "")
+			assert.NoError(t, err)
+			characters, err := reader.Read(strings.NewReader(input))
+			assert.NoError(t, err)
+			assert.Equal(t, []entities.CharacterEntry{jonSnow}, characters)
+		})
+	}
+}
+
+func TestCharacterReaderErrors(t *testing.T) {
+	_, err := NDJSONReader{}.Read(strings.NewReader("{\"characterName\": \"Jon Snow\"}\n{\"characterName\": \n"))
+	assert.ErrorContains(t, err, "line 2")
+
+	_, err = CSVReader{}.Read(strings.NewReader("characterName,royal\nJon Snow,yes please\n"))
+	assert.ErrorContains(t, err, "line 2: royal must be true or false")
+}
+
+func TestCharacterReadersKeepUnknownKeys(t *testing.T) {
+	characters, err := JSONReader{}.Read(strings.NewReader(`{"characters": [
+		{"characterName": "Eddard Stark", "parentOf": ["Arya Stark", "Robb Stark"], "kingsguard": false}
+	]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]json.RawMessage{
+		"parentOf":   json.RawMessage(`["Arya Stark", "Robb Stark"]`),
+		"kingsguard": json.RawMessage(`false`),
+	}, characters[0].Extra)
+
+	// only columns marked as JSON are read as JSON, text like 1984 stays text
+	input := "characterName,parentOf:json,serves,kingsguard,motto\n" +
+		`Eddard Stark,"[""Arya Stark""]",Robert Baratheon,,1984` + "\n"
+	characters, err = CSVReader{}.Read(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]json.RawMessage{
+		"parentOf": json.RawMessage(`["Arya Stark"]`),
+		"serves":   json.RawMessage(`"Robert Baratheon"`),
+		"motto":    json.RawMessage(`"1984"`),
+	}, characters[0].Extra)
+
+	var output strings.Builder
+	assert.NoError(t, CSVWriter{}.Write(&output, characters))
+	assert.Contains(t, output.String(), "motto,parentOf:json,serves\n")
+	written, err := CSVReader{}.Read(strings.NewReader(output.String()))
+	assert.NoError(t, err)
+	assert.Equal(t, characters[0].Extra, written[0].Extra)
+
+	_, err = CSVReader{}.Read(strings.NewReader("characterName,parentOf:json\nEddard Stark,Arya Stark\n"))
+	assert.ErrorContains(t, err, "line 2: parentOf:json must hold JSON")
+}
+
+func TestCharacterReadersMatchKeysLikeJSON(t *testing.T) {
+	characters, err := JSONReader{}.Read(strings.NewReader(`{"characters": [
+		{"characterName": "Arya Stark", "Siblings": ["Jon Snow"], "actors": [
+			{"actorName": "Maisie Williams", "SeasonsActive": [1, 2], "age": 12},
+			{"actorName": "Someone Else", "age": 30, "stunts": true}
+		]}
+	]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Jon Snow"}, characters[0].Siblings)
+	assert.Equal(t, []int{1, 2}, characters[0].Actors[0].SeasonsActive)
+	assert.Equal(t, map[string]json.RawMessage{
+		"actors.age":    json.RawMessage(`{"Maisie Williams":12,"Someone Else":30}`),
+		"actors.stunts": json.RawMessage(`{"Someone Else":true}`),
+	}, characters[0].Extra)
+	assert.Equal(t, []UnknownField{
+		{Key: "actors.age", Count: 1, Examples: []string{"Arya Stark"}},
+		{Key: "actors.stunts", Count: 1, Examples: []string{"Arya Stark"}},
+	}, FindUnknownFields(characters))
+
+	// written back into the actors entries
+	var buffer bytes.Buffer
+	assert.NoError(t, NDJSONWriter{}.Write(&buffer, characters))
+	assert.Contains(t, buffer.String(), `{"actorName":"Someone Else","age":30,"stunts":true}`)
+	read, err := NDJSONReader{}.Read(&buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, characters, read)
+}
+
+func TestNewCharacterReader(t *testing.T) {
+	reader, err := NewCharacterReader("", "data/characters.CSV")
+	assert.NoError(t, err)
+	assert.IsType(t, CSVReader{}, reader)
+
+	reader, err = NewCharacterReader("", "data/characters.yml")
+	assert.NoError(t, err)
+	assert.IsType(t, YAMLReader{}, reader)
+
+	reader, err = NewCharacterReader("", "data/wiki-dump.xml")
+	assert.NoError(t, err)
+	assert.IsType(t, MediaWikiReader{}, reader)
+
+	reader, err = NewCharacterReader("", "")
+	assert.NoError(t, err)
+	assert.IsType(t, JSONReader{}, reader)
+
+	_, err = NewCharacterReader("xml", "")
+	assert.ErrorContains(t, err, "use one of csv, json, mediawiki, ndjson, yaml")
+}
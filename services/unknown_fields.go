@@ -0,0 +1,50 @@
+package services
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/vitalii-komenda/got/entities"
+)
+
+// unknownFieldExamples is how many character names an UnknownField lists.
+const unknownFieldExamples = 3
+
+// UnknownField is a key of the dataset no CharacterEntry field maps to,
+// with how many characters have it and the names of the first of them.
+type UnknownField struct {
+	Key      string
+	Count    int
+	Examples []string
+}
+
+// FindUnknownFields lists the keys the readers kept in Extra, the most
+// common first.
+func FindUnknownFields(characters []entities.CharacterEntry) []UnknownField {
+	byKey := map[string]*UnknownField{}
+	for _, character := range characters {
+		for key := range character.Extra {
+			field, ok := byKey[key]
+			if !ok {
+				field = &UnknownField{Key: key}
+				byKey[key] = field
+			}
+			field.Count++
+			if len(field.Examples) < unknownFieldExamples {
+				field.Examples = append(field.Examples, character.CharacterName)
+			}
+		}
+	}
+
+	fields := make([]UnknownField, 0, len(byKey))
+	for _, field := range byKey {
+		fields = append(fields, *field)
+	}
+	slices.SortFunc(fields, func(a, b UnknownField) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+	return fields
+}
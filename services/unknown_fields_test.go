@@ -0,0 +1,26 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vitalii-komenda/got/entities"
+)
+
+func TestFindUnknownFields(t *testing.T) {
+	parentOf := json.RawMessage(`["Arya Stark"]`)
+	characters := []entities.CharacterEntry{
+		{CharacterName: "Eddard Stark", Extra: map[string]json.RawMessage{"parentOf": parentOf, "serves": json.RawMessage(`["Robert Baratheon"]`)}},
+		{CharacterName: "Catelyn Stark", Extra: map[string]json.RawMessage{"parentOf": parentOf}},
+		{CharacterName: "Arya Stark"},
+		{CharacterName: "Lyanna Stark", Extra: map[string]json.RawMessage{"parentOf": parentOf}},
+		{CharacterName: "Rhaegar Targaryen", Extra: map[string]json.RawMessage{"parentOf": parentOf}},
+	}
+
+	assert.Equal(t, []UnknownField{
+		{Key: "parentOf", Count: 4, Examples: []string{"Eddard Stark", "Catelyn Stark", "Lyanna Stark"}},
+		{Key: "serves", Count: 1, Examples: []string{"Eddard Stark"}},
+	}, FindUnknownFields(characters))
+	assert.Empty(t, FindUnknownFields(characters[2:3]))
+}
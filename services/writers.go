@@ -0,0 +1,301 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/vitalii-komenda/got/entities"
+)
+
+// CharacterWriter writes characters in a format its CharacterReader reads
+// back unchanged.
+type CharacterWriter interface {
+	Write(w io.Writer, characters []entities.CharacterEntry) error
+}
+
+// characterWriters are the formats that can be written by name.
+var characterWriters = map[string]CharacterWriter{
+	"json":   JSONWriter{},
+	"ndjson": NDJSONWriter{},
+	"csv":    CSVWriter{},
+}
+
+// NewCharacterWriter returns the writer of the format, or when format is
+// empty the one the extension of path stands for, JSON by default.
+func NewCharacterWriter(format string, path string) (CharacterWriter, error) {
+	if format == "" {
+		format = formatExtensions[strings.ToLower(filepath.Ext(path))]
+	}
+	if format == "" {
+		format = "json"
+	}
+	writer, ok := characterWriters[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown format %q, use one of %s", format, strings.Join(WriterFormats(), ", "))
+	}
+	return writer, nil
+}
+
+// WriterFormats are the names of the formats that can be written.
+func WriterFormats() []string {
+	formats := make([]string, 0, len(characterWriters))
+	for format := range characterWriters {
+		formats = append(formats, format)
+	}
+	slices.Sort(formats)
+	return formats
+}
+
+// encodeCharacter encodes a character with its Extra keys back where the
+// readers found them: at the top level, or in the actors entries for those
+// under actorExtraPrefix.
+func encodeCharacter(character entities.CharacterEntry) (json.RawMessage, error) {
+	extra := maps.Clone(character.Extra)
+	character.Extra = nil
+	if actors, ok, err := encodeActors(character.Actors, extra); err != nil {
+		return nil, fmt.Errorf("actors of %s: %w", character.CharacterName, err)
+	} else if ok {
+		character.Actors = nil
+		extra["actors"] = actors
+	}
+	data, err := json.Marshal(character)
+	if err != nil {
+		return nil, err
+	}
+	if len(extra) == 0 {
+		return data, nil
+	}
+
+	keys := slices.Sorted(maps.Keys(extra))
+	var buffer bytes.Buffer
+	buffer.Write(data[:len(data)-1])
+	for _, key := range keys {
+		if knownKey(characterKeys, key) && key != "actors" {
+			continue
+		}
+		name, _ := json.Marshal(key)
+		buffer.WriteString(",")
+		buffer.Write(name)
+		buffer.WriteString(":")
+		if err := json.Compact(&buffer, extra[key]); err != nil {
+			return nil, fmt.Errorf("extra %s of %s: %w", key, character.CharacterName, err)
+		}
+	}
+	buffer.WriteString("}")
+	return buffer.Bytes(), nil
+}
+
+// encodeActors encodes the actors with the values of their Extra keys,
+// which it takes out of extra. Keys with values for actors the character
+// no longer lists stay at the top level.
+func encodeActors(actors []entities.ActorEntry, extra map[string]json.RawMessage) (json.RawMessage, bool, error) {
+	positions := map[string]int{}
+	for i, actor := range actors {
+		positions[actorExtraKey(actor, i)] = i
+	}
+	entries := make([]map[string]json.RawMessage, len(actors))
+	found := false
+	for key, value := range extra {
+		name, ok := strings.CutPrefix(key, actorExtraPrefix)
+		if !ok {
+			continue
+		}
+		var byActor map[string]json.RawMessage
+		if json.Unmarshal(value, &byActor) != nil {
+			continue
+		}
+		matched := true
+		for actor := range byActor {
+			if _, ok := positions[actor]; !ok {
+				matched = false
+			}
+		}
+		if !matched {
+			continue
+		}
+		for actor, value := range byActor {
+			i := positions[actor]
+			if entries[i] == nil {
+				entries[i] = map[string]json.RawMessage{}
+			}
+			entries[i][name] = value
+		}
+		delete(extra, key)
+		found = true
+	}
+	if !found {
+		return nil, false, nil
+	}
+
+	for i, actor := range actors {
+		data, err := json.Marshal(actor)
+		if err != nil {
+			return nil, false, err
+		}
+		if entries[i] == nil {
+			entries[i] = map[string]json.RawMessage{}
+		}
+		if err := json.Unmarshal(data, &entries[i]); err != nil {
+			return nil, false, err
+		}
+	}
+	data, err := json.Marshal(entries)
+	return data, err == nil, err
+}
+
+// JSONWriter writes the {"characters": [...]} layout of
+// data/got-characters.json.
+type JSONWriter struct{}
+
+func (JSONWriter) Write(w io.Writer, characters []entities.CharacterEntry) error {
+	file := struct {
+		Characters []json.RawMessage `json:"characters"`
+	}{Characters: make([]json.RawMessage, len(characters))}
+	for i, character := range characters {
+		var err error
+		if file.Characters[i], err = encodeCharacter(character); err != nil {
+			return fmt.Errorf("error encoding character %d: %w", i+1, err)
+		}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(file); err != nil {
+		return fmt.Errorf("error writing JSON: %w", err)
+	}
+	return nil
+}
+
+// NDJSONWriter writes one character object per line.
+type NDJSONWriter struct{}
+
+func (NDJSONWriter) Write(w io.Writer, characters []entities.CharacterEntry) error {
+	for i, character := range characters {
+		data, err := encodeCharacter(character)
+		if err != nil {
+			return fmt.Errorf("error encoding character %d: %w", i+1, err)
+		}
+		if _, err := w.Write(append(data, '\n')); err != nil {
+			return fmt.Errorf("error writing NDJSON: %w", err)
+		}
+	}
+	return nil
+}
+
+// CSVWriter writes the columns CSVReader knows, then a column per Extra
+// key, named with csvJSONSuffix unless every value of the key is a string.
+// Names can't contain the ";" and "|" separators.
+type CSVWriter struct{}
+
+func (CSVWriter) Write(w io.Writer, characters []entities.CharacterEntry) error {
+	var extraColumns []string
+	jsonColumns := map[string]bool{}
+	for _, character := range characters {
+		for key, value := range character.Extra {
+			if slices.Contains(CSVColumns, key) {
+				continue
+			}
+			if !slices.Contains(extraColumns, key) {
+				extraColumns = append(extraColumns, key)
+			}
+			var text string
+			if json.Unmarshal(value, &text) != nil {
+				jsonColumns[key] = true
+			}
+		}
+	}
+	slices.Sort(extraColumns)
+
+	header := slices.Clone(CSVColumns)
+	for _, column := range extraColumns {
+		if jsonColumns[column] {
+			column += csvJSONSuffix
+		}
+		header = append(header, column)
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("error writing CSV: %w", err)
+	}
+	for _, character := range characters {
+		row := csvRow(character)
+		record := make([]string, 0, len(CSVColumns)+len(extraColumns))
+		for _, column := range CSVColumns {
+			record = append(record, row[column])
+		}
+		for _, column := range extraColumns {
+			value, ok := character.Extra[column]
+			if !ok {
+				record = append(record, "")
+				continue
+			}
+			record = append(record, csvExtraCell(value, jsonColumns[column]))
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("error writing CSV: %w", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("error writing CSV: %w", err)
+	}
+	return nil
+}
+
+// csvRow is the reverse of csvCharacter.
+func csvRow(character entities.CharacterEntry) map[string]string {
+	var aliases, actors []string
+	for _, alias := range character.Aliases {
+		aliases = append(aliases, alias.Name+csvPartSeparator+string(alias.Type))
+	}
+	for _, actor := range character.Actors {
+		seasons := make([]string, len(actor.SeasonsActive))
+		for i, season := range actor.SeasonsActive {
+			seasons[i] = strconv.Itoa(season)
+		}
+		actors = append(actors, strings.Join([]string{actor.ActorName, actor.ActorLink, strings.Join(seasons, csvSeasonsSeparator)}, csvPartSeparator))
+	}
+	row := map[string]string{
+		"characterName":       character.CharacterName,
+		"houseName":           strings.Join(character.HouseName, csvListSeparator),
+		"nickname":            character.Nickname,
+		"aliases":             strings.Join(aliases, csvListSeparator),
+		"characterImageThumb": character.CharacterImageThumb,
+		"characterImageFull":  character.CharacterImageFull,
+		"characterLink":       character.CharacterLink,
+		"actorName":           character.ActorName,
+		"actorLink":           character.ActorLink,
+		"actors":              strings.Join(actors, csvListSeparator),
+		"parents":             strings.Join(character.Parents, csvListSeparator),
+		"siblings":            strings.Join(character.Siblings, csvListSeparator),
+		"killed":              strings.Join(character.Killed, csvListSeparator),
+		"killedBy":            strings.Join(character.KilledBy, csvListSeparator),
+		"marriedEngaged":      strings.Join(character.MarriedEngaged, csvListSeparator),
+	}
+	if character.Royal {
+		row["royal"] = "true"
+	}
+	return row
+}
+
+// csvExtraCell is the reverse of csvExtra: values of a JSON column are
+// written as JSON, those of a text column bare.
+func csvExtraCell(value json.RawMessage, isJSON bool) string {
+	var text string
+	if !isJSON && json.Unmarshal(value, &text) == nil {
+		return text
+	}
+	var buffer bytes.Buffer
+	if json.Compact(&buffer, value) != nil {
+		return string(value)
+	}
+	return buffer.String()
+}